@@ -58,12 +58,6 @@ func main() {
 		panic(err)
 	}
 
-	/*getDepth := fn(routestep camel.RouteStep, depth int) error {
-		fmt.Printf("%s> %s [%T]\n", strings.Repeat("-", depth+1), routestep.StepName(), routestep)
-		return nil
-	}
-	_ = camel.WalkRoute(r, getDepth)*/
-
 	camelRuntime.MustRegisterRoute(r)
 
 	// Ticks every 5 seconds
@@ -86,7 +80,8 @@ func main() {
 	}
 
 	// Calc sum
-	ctx, _ := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
 	ex, err := camelRuntime.Send(ctx, "direct:sum", 0, exchange.Map{
 		"a": 1,
 		"b": 39,
@@ -5,4 +5,5 @@ go 1.24.7
 require (
 	github.com/expr-lang/expr v1.17.5
 	github.com/google/uuid v1.6.0
+	gopkg.in/yaml.v3 v3.0.1
 )
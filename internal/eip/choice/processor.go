@@ -60,22 +60,35 @@ func (p *choiceProcessor) SetOtherwise(processor api.Processor) *choiceProcessor
 }
 
 func (p *choiceProcessor) Process(e *exchange.Exchange) {
+	if selected := p.selectProcessor(e); selected != nil {
+		selected.Process(e)
+	}
+}
+
+func (p *choiceProcessor) ProcessAsync(e *exchange.Exchange, done func()) {
+	selected := p.selectProcessor(e)
+	if selected == nil {
+		done()
+		return
+	}
+	api.ToAsync(selected).ProcessAsync(e, done)
+}
+
+// selectProcessor returns the processor of the first matched case or otherwise processor (may be nil).
+func (p *choiceProcessor) selectProcessor(e *exchange.Exchange) api.Processor {
 	for _, whenCase := range p.cases {
 		caseMatched, err := whenCase.match(e)
 		if err != nil {
 			// In case of error stop processing choice
 			e.SetError(err)
-			return
+			return nil
 		}
 
 		if caseMatched {
-			whenCase.processor.Process(e)
-			return
+			return whenCase.processor
 		}
 	}
 
 	// No one case was found
-	if p.otherwise != nil {
-		p.otherwise.Process(e)
-	}
+	return p.otherwise
 }
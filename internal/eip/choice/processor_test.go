@@ -22,3 +22,18 @@ func TestChoiceProcessor(t *testing.T) {
 		t.Errorf("TestChoiceProcessor() = %v; want %v", e.Message().Body, expected)
 	}
 }
+
+func BenchmarkChoiceProcessor(b *testing.B) {
+	c := NewProcessor("", "bench").
+		AddWhen(expression.MustSimple("header.val > 5 && header.kind == 'vip'"), setbody.NewProcessor("", "", expression.NewConst(555))).
+		AddWhen(expression.MustSimple("header.val < 5"), setbody.NewProcessor("", "", expression.NewConst(777)))
+
+	e := exchange.NewExchange(nil)
+	e.Message().SetHeader("val", 2)
+	e.Message().SetHeader("kind", "regular")
+
+	b.ReportAllocs()
+	for b.Loop() {
+		c.Process(e)
+	}
+}
@@ -30,3 +30,12 @@ func (p *delayProcessor) RouteName() string {
 func (p *delayProcessor) Process(_ *exchange.Exchange) {
 	time.Sleep(time.Duration(p.durMs) * time.Millisecond)
 }
+
+// ProcessAsync completes the exchange after the delay by means of a timer, no goroutine is parked while waiting.
+func (p *delayProcessor) ProcessAsync(_ *exchange.Exchange, done func()) {
+	if p.durMs <= 0 {
+		done()
+		return
+	}
+	time.AfterFunc(time.Duration(p.durMs)*time.Millisecond, done)
+}
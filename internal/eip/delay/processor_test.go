@@ -20,3 +20,23 @@ func TestDelayProcessor(t *testing.T) {
 		t.Fatalf("TestDelayProcessor() = %d elapsed ms; want >= %d", elapsedMs, expectedValue)
 	}
 }
+
+func TestDelayProcessor_ProcessAsync(t *testing.T) {
+	p := NewProcessor("test", "test", 100)
+	e := exchange.NewExchange(nil)
+	start := time.Now()
+	done := make(chan struct{})
+
+	p.ProcessAsync(e, func() {
+		close(done)
+	})
+
+	if returnedMs := time.Since(start).Milliseconds(); returnedMs >= 100 {
+		t.Fatalf("TestDelayProcessor_ProcessAsync() returned after %d ms; want immediate return", returnedMs)
+	}
+
+	<-done
+	if elapsedMs := time.Since(start).Milliseconds(); elapsedMs < 100 {
+		t.Fatalf("TestDelayProcessor_ProcessAsync() = %d elapsed ms; want >= %d", elapsedMs, 100)
+	}
+}
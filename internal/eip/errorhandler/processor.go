@@ -0,0 +1,284 @@
+package errorhandler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/paveldanilin/go-camel/internal/expression"
+	"github.com/paveldanilin/go-camel/internal/processor"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"time"
+)
+
+type RedeliveryPolicy struct {
+	// MaximumRedeliveries is the max number of redeliveries (zero - no redelivery, negative - unlimited)
+	MaximumRedeliveries int
+	Delay               time.Duration
+	// BackOffMultiplier multiplies the delay of each next redelivery (values <= 1 - constant delay)
+	BackOffMultiplier float64
+	// MaximumDelay caps the delay (zero - no cap)
+	MaximumDelay time.Duration
+	// Jitter randomizes the delay by the given fraction [0..1]
+	Jitter float64
+	// RetryWhile is tested against the failed exchange (nil - redeliver any failed exchange)
+	RetryWhile expression.Predicate
+	// RetryOn reports whether the error can be redelivered (nil - any error)
+	RetryOn func(e *exchange.Exchange, err error) bool
+}
+
+// RedeliveryDelay returns the delay before the given redelivery (starts with 1).
+func (p RedeliveryPolicy) RedeliveryDelay(redelivery int) time.Duration {
+	d := float64(p.Delay)
+	if p.BackOffMultiplier > 1 && redelivery > 1 {
+		d *= math.Pow(p.BackOffMultiplier, float64(redelivery-1))
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*rand.Float64() - 1)
+	}
+	if p.MaximumDelay > 0 && d > float64(p.MaximumDelay) {
+		d = float64(p.MaximumDelay)
+	}
+	if d > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(d)
+}
+
+// OnException handles the matched error once the redeliveries are exhausted.
+type OnException struct {
+	Matcher func(e *exchange.Exchange, err error) bool
+	// Handler processes the failed exchange (nil - no handler steps)
+	Handler api.Processor
+	// Handled clears the error and stops the route
+	Handled bool
+	// Continued clears the error and continues the route with the next step
+	Continued bool
+}
+
+// errorHandlerProcessor processes the wrapped step again while it fails and the policy allows redelivery,
+// once the redeliveries are exhausted the failed exchange is sent to the dead letter channel (if any).
+type errorHandlerProcessor struct {
+	routeName string
+	name      string
+	processor api.Processor
+	policy    RedeliveryPolicy
+	// deadLetter is the producer of the dead letter endpoint (nil - the exchange fails with the error)
+	deadLetter         api.Processor
+	useOriginalMessage bool
+	// onExceptions are tested in order, the first matched clause takes precedence over the dead letter channel
+	onExceptions []OnException
+}
+
+func NewProcessor(routeName, name string, processor api.Processor, policy RedeliveryPolicy) *errorHandlerProcessor {
+	return &errorHandlerProcessor{
+		routeName: routeName,
+		name:      name,
+		processor: processor,
+		policy:    policy,
+	}
+}
+
+func (p *errorHandlerProcessor) Name() string {
+	return p.name
+}
+
+func (p *errorHandlerProcessor) RouteName() string {
+	return p.routeName
+}
+
+// SetDeadLetter sets the dead letter channel, if useOriginalMessage is set the message is replaced with
+// the message stored in exchange.CamelPropertyOriginalMessage before it is sent to the dead letter channel.
+func (p *errorHandlerProcessor) SetDeadLetter(deadLetter api.Processor, useOriginalMessage bool) *errorHandlerProcessor {
+	p.deadLetter = deadLetter
+	p.useOriginalMessage = useOriginalMessage
+	return p
+}
+
+// SetOnExceptions sets the clauses that handle the errors once the redeliveries are exhausted,
+// the first matched clause is used.
+func (p *errorHandlerProcessor) SetOnExceptions(onExceptions []OnException) *errorHandlerProcessor {
+	p.onExceptions = onExceptions
+	return p
+}
+
+func (p *errorHandlerProcessor) Process(e *exchange.Exchange) {
+	api.AsyncProcessorFunc(p.ProcessAsync).Process(e)
+}
+
+// ProcessAsync processes the step and redelivers it after the delay if it fails.
+// The redelivery stops once the exchange is cancelled or the delay would exceed the exchange deadline.
+func (p *errorHandlerProcessor) ProcessAsync(e *exchange.Exchange, done func()) {
+	p.deliver(e, 0, done)
+}
+
+func (p *errorHandlerProcessor) deliver(e *exchange.Exchange, redeliveries int, done func()) {
+	processor.InvokeAsync(p.processor, e, func() {
+		if !e.IsError() {
+			done()
+			return
+		}
+		if isExhausted(e) {
+			// The error is already processed by the error handler of the nested step
+			done()
+			return
+		}
+		if !p.canRedeliver(e, redeliveries) {
+			p.exhausted(e, done)
+			return
+		}
+
+		delay := p.policy.RedeliveryDelay(redeliveries + 1)
+		if deadline, hasDeadline := e.Deadline(); hasDeadline && time.Now().Add(delay).After(deadline) {
+			p.exhausted(e, done)
+			return
+		}
+
+		// The delay is awaited in a separate goroutine, thus unlimited redeliveries do not grow the stack
+		go func() {
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+
+			select {
+			case <-timer.C:
+				p.redeliver(e, redeliveries+1, done)
+			case <-e.Context().Done():
+				p.exhausted(e, done)
+			}
+		}()
+	})
+}
+
+func (p *errorHandlerProcessor) redeliver(e *exchange.Exchange, redelivery int, done func()) {
+	e.AddErrorHistory(e.Error())
+	e.SetError(nil)
+	e.Message().SetHeader(exchange.CamelHeaderRedelivered, true)
+	e.Message().SetHeader(exchange.CamelHeaderRedeliveryCounter, redelivery)
+	if p.policy.MaximumRedeliveries >= 0 {
+		e.Message().SetHeader(exchange.CamelHeaderRedeliveryMaxCounter, p.policy.MaximumRedeliveries)
+	}
+	p.deliver(e, redelivery, done)
+}
+
+func (p *errorHandlerProcessor) canRedeliver(e *exchange.Exchange, redeliveries int) bool {
+	if p.policy.MaximumRedeliveries >= 0 && redeliveries >= p.policy.MaximumRedeliveries {
+		return false
+	}
+	if e.CheckCancelOrTimeout() != nil {
+		return false
+	}
+	if p.policy.RetryOn != nil && !p.policy.RetryOn(e, e.Error()) {
+		return false
+	}
+	if p.policy.RetryWhile != nil {
+		retry, err := p.policy.RetryWhile.Test(e)
+		if err != nil {
+			e.SetError(errors.Join(e.Error(), fmt.Errorf("redelivery: retryWhile: %w", err)))
+			return false
+		}
+		return retry
+	}
+	return true
+}
+
+// exhausted passes the failed exchange to the matched onException clause or to the dead letter channel,
+// on success of the dead letter channel the error is handled: the exchange error is cleared
+// and the rest of the route is skipped.
+func (p *errorHandlerProcessor) exhausted(e *exchange.Exchange, done func()) {
+	done = markExhausted(e, done)
+
+	for i := range p.onExceptions {
+		if p.onExceptions[i].Matcher(e, e.Error()) {
+			p.handleException(e, p.onExceptions[i], done)
+			return
+		}
+	}
+
+	if p.deadLetter == nil {
+		done()
+		return
+	}
+
+	failure := e.Error()
+	if p.useOriginalMessage {
+		originalMessage, _ := e.Property(exchange.CamelPropertyOriginalMessage)
+		if originalMessage, isMessage := originalMessage.(*exchange.Message); isMessage {
+			e.SetMessage(originalMessage.Copy())
+		}
+	}
+	e.Message().SetHeader(exchange.CamelHeaderFailureRoute, p.routeName)
+	e.Message().SetHeader(exchange.CamelHeaderFailureStep, p.name)
+	e.Message().SetHeader(exchange.CamelHeaderFailureError, failure.Error())
+	e.Message().SetHeader(exchange.CamelHeaderFailureTime, time.Now())
+	e.SetProperty(exchange.CamelPropertyExceptionCaught, failure)
+	e.SetError(nil)
+
+	processor.InvokeAsync(p.deadLetter, e, func() {
+		if e.IsError() {
+			e.SetError(errors.Join(failure, fmt.Errorf("dead letter channel: %w", e.Error())))
+			done()
+			return
+		}
+		e.AddErrorHistory(failure)
+		e.SetProperty(exchange.CamelPropertyErrorHandled, true)
+		e.StopRoute()
+		done()
+	})
+}
+
+// handleException processes the handler steps of the clause without the error, then the error is either handled,
+// ignored (continued) or restored.
+func (p *errorHandlerProcessor) handleException(e *exchange.Exchange, onException OnException, done func()) {
+	failure := e.Error()
+	e.SetProperty(exchange.CamelPropertyExceptionCaught, failure)
+	e.SetError(nil)
+
+	complete := func() {
+		if e.IsError() {
+			e.SetError(errors.Join(failure, fmt.Errorf("onException: %w", e.Error())))
+			done()
+			return
+		}
+		switch {
+		case onException.Handled:
+			e.AddErrorHistory(failure)
+			e.SetProperty(exchange.CamelPropertyErrorHandled, true)
+			e.StopRoute()
+		case onException.Continued:
+			e.AddErrorHistory(failure)
+		default:
+			e.SetError(failure)
+		}
+		done()
+	}
+
+	if onException.Handler == nil {
+		complete()
+		return
+	}
+	processor.InvokeAsync(onException.Handler, e, complete)
+}
+
+// markExhausted records the error the exchange still fails with once the error handler is done,
+// thus the error handlers of the enclosing steps do not process it again.
+func markExhausted(e *exchange.Exchange, done func()) func() {
+	return func() {
+		if e.IsError() {
+			e.SetProperty(exchange.CamelPropertyExhaustedError, e.Error())
+		}
+		done()
+	}
+}
+
+func isExhausted(e *exchange.Exchange) bool {
+	v, _ := e.Property(exchange.CamelPropertyExhaustedError)
+	exhaustedErr, isErr := v.(error)
+	if !isErr {
+		return false
+	}
+	err := e.Error()
+	errType := reflect.TypeOf(err)
+	return errType == reflect.TypeOf(exhaustedErr) && errType.Comparable() && err == exhaustedErr
+}
@@ -0,0 +1,254 @@
+package errorhandler
+
+import (
+	"context"
+	"errors"
+	"github.com/paveldanilin/go-camel/internal/eip/fn"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingStep fails the first n deliveries.
+func failingStep(n int, deliveries *int) func(e *exchange.Exchange) {
+	return func(e *exchange.Exchange) {
+		*deliveries++
+		if *deliveries <= n {
+			e.SetError(errors.New("connection refused"))
+			return
+		}
+		e.Message().Body = "ok"
+	}
+}
+
+func TestErrorHandlerProcessor(t *testing.T) {
+	deliveries := 0
+	p := NewProcessor("test", "test", fn.NewProcessor("test", "test", failingStep(2, &deliveries)), RedeliveryPolicy{
+		MaximumRedeliveries: 3,
+		Delay:               time.Millisecond,
+	})
+	e := exchange.NewExchange(nil)
+
+	p.Process(e)
+
+	if e.IsError() {
+		t.Fatalf("TestErrorHandlerProcessor(): unexpected error: %s", e.Error())
+	}
+	if deliveries != 3 {
+		t.Fatalf("TestErrorHandlerProcessor() = %d deliveries; want %d", deliveries, 3)
+	}
+	if counter, _ := e.Message().Header(exchange.CamelHeaderRedeliveryCounter); counter != 2 {
+		t.Fatalf("TestErrorHandlerProcessor() = %v redelivery counter; want %d", counter, 2)
+	}
+}
+
+func TestErrorHandlerProcessor_Exhausted(t *testing.T) {
+	deliveries := 0
+	p := NewProcessor("test", "test", fn.NewProcessor("test", "test", failingStep(10, &deliveries)), RedeliveryPolicy{
+		MaximumRedeliveries: 2,
+	})
+	e := exchange.NewExchange(nil)
+
+	p.Process(e)
+
+	if !e.IsError() {
+		t.Fatalf("TestErrorHandlerProcessor_Exhausted(): expected error")
+	}
+	if deliveries != 3 {
+		t.Fatalf("TestErrorHandlerProcessor_Exhausted() = %d deliveries; want %d", deliveries, 3)
+	}
+}
+
+func TestErrorHandlerProcessor_RetryOn(t *testing.T) {
+	deliveries := 0
+	p := NewProcessor("test", "test", fn.NewProcessor("test", "test", failingStep(10, &deliveries)), RedeliveryPolicy{
+		MaximumRedeliveries: 5,
+		RetryOn: func(_ *exchange.Exchange, err error) bool {
+			return strings.Contains(err.Error(), "timeout")
+		},
+	})
+	e := exchange.NewExchange(nil)
+
+	p.Process(e)
+
+	if deliveries != 1 {
+		t.Fatalf("TestErrorHandlerProcessor_RetryOn() = %d deliveries; want %d", deliveries, 1)
+	}
+}
+
+func TestErrorHandlerProcessor_Deadline(t *testing.T) {
+	deliveries := 0
+	p := NewProcessor("test", "test", fn.NewProcessor("test", "test", failingStep(10, &deliveries)), RedeliveryPolicy{
+		MaximumRedeliveries: -1,
+		Delay:               40 * time.Millisecond,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	e := exchange.NewExchange(ctx)
+
+	p.Process(e)
+
+	if !e.IsError() {
+		t.Fatalf("TestErrorHandlerProcessor_Deadline(): expected error")
+	}
+	if deliveries != 3 {
+		t.Fatalf("TestErrorHandlerProcessor_Deadline() = %d deliveries; want %d", deliveries, 3)
+	}
+}
+
+func TestRedeliveryPolicy_RedeliveryDelay(t *testing.T) {
+	policy := RedeliveryPolicy{
+		Delay:             100 * time.Millisecond,
+		BackOffMultiplier: 2,
+		MaximumDelay:      300 * time.Millisecond,
+	}
+
+	for redelivery, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 300 * time.Millisecond,
+		4: 300 * time.Millisecond,
+	} {
+		if got := policy.RedeliveryDelay(redelivery); got != want {
+			t.Fatalf("TestRedeliveryPolicy_RedeliveryDelay(%d) = %s; want %s", redelivery, got, want)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.RedeliveryDelay(1); got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("TestRedeliveryPolicy_RedeliveryDelay() = %s; want delay in range [50ms..150ms]", got)
+		}
+	}
+}
+
+func TestErrorHandlerProcessor_DeadLetter(t *testing.T) {
+	deliveries := 0
+	var deadLetter *exchange.Exchange
+	p := NewProcessor("test", "failing", fn.NewProcessor("test", "failing", failingStep(10, &deliveries)), RedeliveryPolicy{
+		MaximumRedeliveries: 1,
+	}).SetDeadLetter(fn.NewProcessor("test", "dlq", func(e *exchange.Exchange) {
+		deadLetter = e
+	}), true)
+	e := exchange.NewExchange(nil)
+	e.Message().Body = "original"
+	e.SetProperty(exchange.CamelPropertyOriginalMessage, e.Message().Copy())
+	e.Message().Body = "modified"
+
+	p.Process(e)
+
+	if e.IsError() {
+		t.Fatalf("TestErrorHandlerProcessor_DeadLetter(): unexpected error: %s", e.Error())
+	}
+	if deliveries != 2 {
+		t.Fatalf("TestErrorHandlerProcessor_DeadLetter() = %d deliveries; want %d", deliveries, 2)
+	}
+	if deadLetter == nil || deadLetter.Message().Body != "original" {
+		t.Fatalf("TestErrorHandlerProcessor_DeadLetter(): expected original message in dead letter channel")
+	}
+	if step, _ := e.Message().Header(exchange.CamelHeaderFailureStep); step != "failing" {
+		t.Fatalf("TestErrorHandlerProcessor_DeadLetter() = %v failure step; want %s", step, "failing")
+	}
+	if errText, _ := e.Message().Header(exchange.CamelHeaderFailureError); errText != "connection refused" {
+		t.Fatalf("TestErrorHandlerProcessor_DeadLetter() = %v failure error; want %s", errText, "connection refused")
+	}
+	if !e.IsRouteStopped() {
+		t.Fatalf("TestErrorHandlerProcessor_DeadLetter(): expected route stop")
+	}
+}
+
+func TestErrorHandlerProcessor_DeadLetterFailed(t *testing.T) {
+	deliveries := 0
+	p := NewProcessor("test", "failing", fn.NewProcessor("test", "failing", failingStep(10, &deliveries)), RedeliveryPolicy{}).
+		SetDeadLetter(fn.NewProcessor("test", "dlq", func(e *exchange.Exchange) {
+			e.SetError(errors.New("dead letter queue is full"))
+		}), false)
+	e := exchange.NewExchange(nil)
+
+	p.Process(e)
+
+	if !e.IsError() || e.IsRouteStopped() {
+		t.Fatalf("TestErrorHandlerProcessor_DeadLetterFailed(): expected not handled error")
+	}
+	if !strings.Contains(e.Error().Error(), "connection refused") || !strings.Contains(e.Error().Error(), "dead letter queue is full") {
+		t.Fatalf("TestErrorHandlerProcessor_DeadLetterFailed() = %s; want both errors", e.Error())
+	}
+}
+
+func TestErrorHandlerProcessor_OnException(t *testing.T) {
+	tests := []struct {
+		name      string
+		handled   bool
+		continued bool
+		wantError bool
+		wantStop  bool
+	}{
+		{name: "handled", handled: true, wantStop: true},
+		{name: "continued", continued: true},
+		{name: "rethrown", wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliveries := 0
+			handled := 0
+			p := NewProcessor("test", "test", fn.NewProcessor("test", "test", failingStep(10, &deliveries)), RedeliveryPolicy{
+				MaximumRedeliveries: 1,
+			}).SetOnExceptions([]OnException{
+				{
+					Matcher: func(_ *exchange.Exchange, err error) bool { return strings.Contains(err.Error(), "timeout") },
+					Handler: fn.NewProcessor("test", "timeout", func(e *exchange.Exchange) {}),
+				},
+				{
+					Matcher: func(_ *exchange.Exchange, err error) bool { return strings.Contains(err.Error(), "refused") },
+					Handler: fn.NewProcessor("test", "refused", func(e *exchange.Exchange) {
+						if e.IsError() {
+							t.Errorf("TestErrorHandlerProcessor_OnException(): handler must not see the error")
+						}
+						handled++
+					}),
+					Handled:   tt.handled,
+					Continued: tt.continued,
+				},
+			})
+			e := exchange.NewExchange(nil)
+
+			p.Process(e)
+
+			if deliveries != 2 || handled != 1 {
+				t.Fatalf("TestErrorHandlerProcessor_OnException() = %d deliveries, %d handled; want 2, 1", deliveries, handled)
+			}
+			if e.IsError() != tt.wantError {
+				t.Fatalf("TestErrorHandlerProcessor_OnException() = error %v; want error %v", e.Error(), tt.wantError)
+			}
+			if e.IsRouteStopped() != tt.wantStop {
+				t.Fatalf("TestErrorHandlerProcessor_OnException() = route stopped %v; want %v", e.IsRouteStopped(), tt.wantStop)
+			}
+			if caught, _ := e.Property(exchange.CamelPropertyExceptionCaught); caught == nil {
+				t.Fatalf("TestErrorHandlerProcessor_OnException(): expected caught exception")
+			}
+		})
+	}
+}
+
+func TestErrorHandlerProcessor_OnExceptionHandlerFailed(t *testing.T) {
+	deliveries := 0
+	p := NewProcessor("test", "test", fn.NewProcessor("test", "test", failingStep(10, &deliveries)), RedeliveryPolicy{}).
+		SetOnExceptions([]OnException{{
+			Matcher: func(_ *exchange.Exchange, err error) bool { return true },
+			Handler: fn.NewProcessor("test", "handler", func(e *exchange.Exchange) {
+				e.SetError(errors.New("handler failed"))
+			}),
+			Handled: true,
+		}})
+	e := exchange.NewExchange(nil)
+
+	p.Process(e)
+
+	if !e.IsError() || !strings.Contains(e.Error().Error(), "connection refused") || !strings.Contains(e.Error().Error(), "handler failed") {
+		t.Fatalf("TestErrorHandlerProcessor_OnExceptionHandlerFailed(): expected both errors, but got %v", e.Error())
+	}
+	if e.IsRouteStopped() {
+		t.Fatalf("TestErrorHandlerProcessor_OnExceptionHandlerFailed(): expected route not stopped")
+	}
+}
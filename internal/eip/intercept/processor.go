@@ -0,0 +1,66 @@
+package intercept
+
+import (
+	"github.com/paveldanilin/go-camel/internal/processor"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+)
+
+// interceptProcessor processes the interceptor before the target, the target is skipped
+// if the interceptor fails or stops the routing of the exchange.
+type interceptProcessor struct {
+	routeName   string
+	name        string
+	interceptor api.Processor
+	target      api.Processor
+	skipTarget  bool
+	endpointURI string
+}
+
+func NewProcessor(routeName, name string, interceptor, target api.Processor) *interceptProcessor {
+	return &interceptProcessor{
+		routeName:   routeName,
+		name:        name,
+		interceptor: interceptor,
+		target:      target,
+	}
+}
+
+func (p *interceptProcessor) Name() string {
+	return p.name
+}
+
+func (p *interceptProcessor) RouteName() string {
+	return p.routeName
+}
+
+// SetSkipTarget processes the interceptor instead of the target.
+func (p *interceptProcessor) SetSkipTarget(skipTarget bool) *interceptProcessor {
+	p.skipTarget = skipTarget
+	return p
+}
+
+// SetEndpointURI sets the URI of the intercepted endpoint, it is exposed to the interceptor
+// by means of the exchange.CamelPropertyInterceptedEndpoint property.
+func (p *interceptProcessor) SetEndpointURI(uri string) *interceptProcessor {
+	p.endpointURI = uri
+	return p
+}
+
+func (p *interceptProcessor) Process(e *exchange.Exchange) {
+	api.AsyncProcessorFunc(p.ProcessAsync).Process(e)
+}
+
+func (p *interceptProcessor) ProcessAsync(e *exchange.Exchange, done func()) {
+	if p.endpointURI != "" {
+		e.SetProperty(exchange.CamelPropertyInterceptedEndpoint, p.endpointURI)
+	}
+
+	processor.InvokeAsync(p.interceptor, e, func() {
+		if p.skipTarget || e.IsError() || e.IsRouteStopped() {
+			done()
+			return
+		}
+		processor.InvokeAsync(p.target, e, done)
+	})
+}
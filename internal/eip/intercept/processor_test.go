@@ -0,0 +1,44 @@
+package intercept
+
+import (
+	"errors"
+	"github.com/paveldanilin/go-camel/internal/eip/fn"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"testing"
+)
+
+func TestInterceptProcessor(t *testing.T) {
+	tests := []struct {
+		name       string
+		skipTarget bool
+		intercept  func(e *exchange.Exchange)
+		wantBody   string
+	}{
+		{name: "target processed", intercept: func(e *exchange.Exchange) { e.Message().Body = "intercepted" }, wantBody: "intercepted;target"},
+		{name: "target skipped", skipTarget: true, intercept: func(e *exchange.Exchange) { e.Message().Body = "intercepted" }, wantBody: "intercepted"},
+		{name: "interceptor failed", intercept: func(e *exchange.Exchange) { e.SetError(errors.New("denied")) }, wantBody: ""},
+		{name: "route stopped", intercept: func(e *exchange.Exchange) { e.StopRoute() }, wantBody: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := fn.NewProcessor("test", "interceptor", tt.intercept)
+			target := fn.NewProcessor("test", "target", func(e *exchange.Exchange) {
+				e.Message().Body = e.Message().Body.(string) + ";target"
+			})
+			p := NewProcessor("test", "intercept", interceptor, target).
+				SetSkipTarget(tt.skipTarget).
+				SetEndpointURI("direct:target")
+			e := exchange.NewExchange(nil)
+			e.Message().Body = ""
+
+			p.Process(e)
+
+			if e.Message().Body != tt.wantBody {
+				t.Fatalf("TestInterceptProcessor() = %v; want %v", e.Message().Body, tt.wantBody)
+			}
+			if uri, _ := e.Property(exchange.CamelPropertyInterceptedEndpoint); uri != "direct:target" {
+				t.Fatalf("TestInterceptProcessor() = intercepted endpoint %v; want direct:target", uri)
+			}
+		})
+	}
+}
@@ -34,3 +34,15 @@ func TestLoopWhileProcessor(t *testing.T) {
 		t.Errorf("TestLoopWhileProcessor() = %v; want body %v", e.Message().Body, expectedBody)
 	}
 }
+
+func BenchmarkLoopWhileProcessor(b *testing.B) {
+	loop := NewWhileProcessor("", "Loop with 10 iterations", expression.MustSimple("property.CAMEL_LOOP_INDEX < 10")).
+		AddProcessor(setbody.NewProcessor("", "set body", expression.NewConst(1)))
+
+	e := exchange.NewExchange(nil)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		loop.Process(e)
+	}
+}
@@ -1,10 +1,15 @@
 package multicast
 
 import (
+	"context"
+	"fmt"
 	"github.com/paveldanilin/go-camel/internal/processor"
+	"github.com/paveldanilin/go-camel/pkg/camel/aggregator"
 	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
 	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
 	"sync"
+	"time"
 )
 
 type multicastProcessor struct {
@@ -12,8 +17,12 @@ type multicastProcessor struct {
 	name        string
 	parallel    bool
 	stopOnError bool
-	aggregator  api.ExchangeAggregator
-	outputs     []api.Processor // each output is a start of sub route
+	// timeout limits the processing time of all outputs (zero - no limit)
+	timeout    time.Duration
+	aggregator api.ExchangeAggregator
+	// executorService caps the number of outputs processed in parallel (nil - goroutine per output)
+	executorService api.ExecutorService
+	outputs         []api.Processor // each output is a start of sub route
 }
 
 func NewProcessor(routeName, name string, parallel, stopOnError bool, aggregator api.ExchangeAggregator) *multicastProcessor {
@@ -35,68 +44,203 @@ func (p *multicastProcessor) RouteName() string {
 	return p.routeName
 }
 
+func (p *multicastProcessor) SetExecutorService(executorService api.ExecutorService) *multicastProcessor {
+	p.executorService = executorService
+	return p
+}
+
+func (p *multicastProcessor) SetTimeout(timeout time.Duration) *multicastProcessor {
+	p.timeout = timeout
+	return p
+}
+
 func (p *multicastProcessor) AddOutput(processor api.Processor) {
 	p.outputs = append(p.outputs, processor)
 }
 
+// Process sends the exchange copies to the outputs and waits until the multicast is completed.
 func (p *multicastProcessor) Process(e *exchange.Exchange) {
+	api.AsyncProcessorFunc(p.ProcessAsync).Process(e)
+}
+
+// ProcessAsync sends the exchange copies to the outputs and calls done when the multicast is completed:
+// all outputs are done, an output failed (stopOnError) or the timeout elapsed.
+// In sequential mode the next output is started by the completion callback of the previous one,
+// in parallel mode each output is started by the executor service (or in its own goroutine).
+//
+// The remaining outputs are cancelled by means of the copy context once the multicast is completed.
+// The outputs are aggregated in the order of output index as soon as they are completed, so that
+// api.CompletionAwareAggregator can complete the multicast early. The result of the aggregator (if any)
+// is copied back to the exchange, errors of the outputs are combined into errs.MultiError.
+func (p *multicastProcessor) ProcessAsync(e *exchange.Exchange, done func()) {
+	if len(p.outputs) == 0 {
+		done()
+		return
+	}
+
+	r := &multicastRun{
+		p:           p,
+		e:           e,
+		originalErr: e.Error(),
+		completed:   make([]*exchange.Exchange, len(p.outputs)),
+		pending:     len(p.outputs),
+		done:        done,
+	}
+	r.ctx, r.cancel = context.WithCancel(e.Context())
+
+	r.mu.Lock()
+	if p.timeout > 0 {
+		r.timer = time.AfterFunc(p.timeout, func() {
+			r.finish(fmt.Errorf("multicast timeout %s: %w", p.timeout, context.DeadlineExceeded))
+		})
+	}
+	r.mu.Unlock()
+
 	if p.parallel {
-		p.parallelProcess(e)
+		for i := range p.outputs {
+			r.startParallel(i)
+		}
 	} else {
-		p.syncProcess(e)
+		r.start(0)
 	}
 }
 
-func (p *multicastProcessor) syncProcess(e *exchange.Exchange) {
-	var oldExchange *exchange.Exchange = nil
+// multicastRun holds the state of a single multicast processing.
+type multicastRun struct {
+	p           *multicastProcessor
+	e           *exchange.Exchange
+	originalErr error
+	ctx         context.Context
+	cancel      context.CancelFunc
+	done        func()
+
+	mu sync.Mutex
+	// completed holds the copies of the completed outputs by output index
+	completed []*exchange.Exchange
+	pending   int
+	finished  bool
+	timer     *time.Timer
+
+	aggregated *exchange.Exchange
+	// nextAggregate is the index of the next output to aggregate
+	nextAggregate       int
+	aggregationComplete bool
+}
 
-	for _, outputProcessor := range p.outputs {
-		copyExchange := e.Copy()
+func (r *multicastRun) newCopy(index int) *exchange.Exchange {
+	copyExchange := r.e.CopyWithContext(r.ctx)
+	// Outputs start without the error of the original exchange
+	copyExchange.SetError(nil)
+	copyExchange.SetProperty(exchange.CamelPropertyMulticastIndex, index)
+	return copyExchange
+}
 
-		processor.Invoke(outputProcessor, copyExchange)
-		if copyExchange.IsError() && p.stopOnError {
-			break
+// start processes the output in the caller goroutine (sequential mode).
+func (r *multicastRun) start(index int) {
+	copyExchange := r.newCopy(index)
+	processor.InvokeAsync(r.p.outputs[index], copyExchange, func() {
+		if r.complete(index, copyExchange) {
+			r.start(index + 1)
 		}
+	})
+}
 
-		if p.aggregator != nil {
-			oldExchange = p.aggregator.AggregateExchange(oldExchange, copyExchange)
-		}
+// startParallel hands the output over to the executor service (or a new goroutine).
+func (r *multicastRun) startParallel(index int) {
+	copyExchange := r.newCopy(index)
+	task := func() {
+		processor.InvokeAsync(r.p.outputs[index], copyExchange, func() {
+			r.complete(index, copyExchange)
+		})
 	}
 
-	if oldExchange != nil {
-		e = oldExchange
+	if r.p.executorService == nil {
+		go task()
+		return
+	}
+	if err := r.p.executorService.Submit(task); err != nil {
+		copyExchange.SetError(fmt.Errorf("multicast: %w", err))
+		r.complete(index, copyExchange)
 	}
 }
 
-func (p *multicastProcessor) parallelProcess(e *exchange.Exchange) {
-	copyExchanges := make([]*exchange.Exchange, len(p.outputs))
-	for i := 0; i < len(p.outputs); i++ {
-		copyExchanges[i] = e.Copy()
+// complete records the completed output, returns TRUE if the next output must be started (sequential mode).
+func (r *multicastRun) complete(index int, copyExchange *exchange.Exchange) bool {
+	r.mu.Lock()
+	if r.finished {
+		// Late output of the timed out or stopped multicast
+		r.mu.Unlock()
+		return false
 	}
+	r.completed[index] = copyExchange
+	r.pending--
+	r.aggregateCompleted(false)
+	last := r.pending == 0 || (copyExchange.IsError() && r.p.stopOnError) || r.aggregationComplete
+	r.mu.Unlock()
+
+	if last {
+		r.finish(nil)
+		return false
+	}
+	return !r.p.parallel
+}
 
-	// TODO: errgroup?
-	var wg sync.WaitGroup
-	wg.Add(len(p.outputs))
+// finish completes the multicast once, cancels the remaining outputs and aggregates the completed ones.
+func (r *multicastRun) finish(cause error) {
+	r.mu.Lock()
+	if r.finished {
+		r.mu.Unlock()
+		return
+	}
+	r.finished = true
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	r.aggregateCompleted(true)
+	aggregated := r.aggregated
+	r.mu.Unlock()
 
-	for i := 0; i < len(p.outputs); i++ {
-		outputProcessor := p.outputs[i]
-		ex := copyExchanges[i]
+	r.cancel()
 
-		go func() {
-			defer wg.Done()
-			processor.Invoke(outputProcessor, ex)
-		}()
+	var outputErrs []error
+	for i, copyExchange := range r.completed {
+		if copyExchange != nil && copyExchange.IsError() {
+			outputErrs = append(outputErrs, fmt.Errorf("output[%d]: %w", i, copyExchange.Error()))
+		}
+	}
+	if cause != nil {
+		outputErrs = append(outputErrs, cause)
 	}
 
-	wg.Wait()
-
-	if p.aggregator != nil {
-		var oldExchange *exchange.Exchange = nil
-		for _, ex := range copyExchanges {
-			oldExchange = p.aggregator.AggregateExchange(oldExchange, ex)
+	err := r.originalErr
+	if aggregated != nil {
+		r.e.CopyFrom(aggregated)
+		if aggregated.IsError() {
+			err = aggregated.Error()
 		}
-		if oldExchange != nil {
-			e = oldExchange
+	}
+	if len(outputErrs) > 0 {
+		err = &errs.MultiError{Errors: outputErrs}
+	}
+	r.e.SetError(err)
+
+	r.done()
+}
+
+// aggregateCompleted aggregates the completed outputs in the order of output index, stops at the first
+// not completed output unless skipMissing is set. Must be called under r.mu.
+func (r *multicastRun) aggregateCompleted(skipMissing bool) {
+	if r.p.aggregator == nil {
+		return
+	}
+	for ; r.nextAggregate < len(r.completed) && !r.aggregationComplete; r.nextAggregate++ {
+		copyExchange := r.completed[r.nextAggregate]
+		if copyExchange == nil {
+			if skipMissing {
+				continue
+			}
+			return
 		}
+		r.aggregated, r.aggregationComplete = aggregator.Aggregate(r.p.aggregator, r.nextAggregate, r.aggregated, copyExchange)
 	}
 }
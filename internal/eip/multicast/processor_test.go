@@ -0,0 +1,150 @@
+package multicast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/paveldanilin/go-camel/internal/eip/fn"
+	"github.com/paveldanilin/go-camel/internal/eip/seterror"
+	"github.com/paveldanilin/go-camel/pkg/camel/aggregator"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"testing"
+	"time"
+)
+
+// joinBodies aggregates bodies of the outputs into a slice ordered by output index.
+type joinBodies struct{}
+
+func (joinBodies) AggregateExchange(oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	if oldExchange == nil {
+		newExchange.Message().Body = []any{newExchange.Message().Body}
+		return newExchange
+	}
+	oldExchange.Message().Body = append(oldExchange.Message().Body.([]any), newExchange.Message().Body)
+	return oldExchange
+}
+
+func indexBody(e *exchange.Exchange) {
+	index, _ := e.Property(exchange.CamelPropertyMulticastIndex)
+	e.Message().Body = fmt.Sprintf("output %v", index)
+}
+
+// waitForCancel blocks until the output exchange is cancelled.
+func waitForCancel(e *exchange.Exchange) {
+	select {
+	case <-e.Context().Done():
+		e.SetError(e.Context().Err())
+	case <-time.After(time.Second):
+	}
+}
+
+func TestMulticastProcessor_Aggregation(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		p := NewProcessor("", "", parallel, false, joinBodies{})
+		p.AddOutput(fn.NewProcessor("", "", indexBody))
+		p.AddOutput(fn.NewProcessor("", "", indexBody))
+
+		e := exchange.NewExchange(nil)
+		e.Message().Body = "original"
+		p.Process(e)
+
+		if e.IsError() {
+			t.Fatalf("TestMulticastProcessor_Aggregation(parallel=%v): unexpected error: %s", parallel, e.Error())
+		}
+		bodies, isSlice := e.Message().Body.([]any)
+		if !isSlice || len(bodies) != 2 || bodies[0] != "output 0" || bodies[1] != "output 1" {
+			t.Fatalf("TestMulticastProcessor_Aggregation(parallel=%v) = %v; want [output 0 output 1]", parallel, e.Message().Body)
+		}
+	}
+}
+
+func TestMulticastProcessor_StopOnErrorParallel(t *testing.T) {
+	failure := errors.New("output failed")
+
+	p := NewProcessor("", "", true, true, nil)
+	p.AddOutput(seterror.NewProcessor("", "", failure))
+	p.AddOutput(fn.NewProcessor("", "", waitForCancel))
+
+	e := exchange.NewExchange(nil)
+	start := time.Now()
+	p.Process(e)
+
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Fatalf("TestMulticastProcessor_StopOnErrorParallel(): expected remaining outputs to be cancelled, took %s", elapsed)
+	}
+	if !errors.Is(e.Error(), failure) {
+		t.Fatalf("TestMulticastProcessor_StopOnErrorParallel() = %v; want %v", e.Error(), failure)
+	}
+}
+
+func TestMulticastProcessor_MultiError(t *testing.T) {
+	first, second := errors.New("first"), errors.New("second")
+
+	p := NewProcessor("", "", true, false, nil)
+	p.AddOutput(seterror.NewProcessor("", "", first))
+	p.AddOutput(fn.NewProcessor("", "", indexBody))
+	p.AddOutput(seterror.NewProcessor("", "", second))
+
+	e := exchange.NewExchange(nil)
+	p.Process(e)
+
+	var multiErr *errs.MultiError
+	if !errors.As(e.Error(), &multiErr) || len(multiErr.Errors) != 2 {
+		t.Fatalf("TestMulticastProcessor_MultiError() = %v; want MultiError with 2 errors", e.Error())
+	}
+	if !errors.Is(e.Error(), first) || !errors.Is(e.Error(), second) {
+		t.Fatalf("TestMulticastProcessor_MultiError() = %v; want both output errors", e.Error())
+	}
+}
+
+func TestMulticastProcessor_Timeout(t *testing.T) {
+	var cancelled = make(chan struct{})
+
+	p := NewProcessor("", "", true, false, joinBodies{}).SetTimeout(50 * time.Millisecond)
+	p.AddOutput(fn.NewProcessor("", "", indexBody))
+	p.AddOutput(api.AsyncProcessorFunc(func(e *exchange.Exchange, done func()) {
+		go func() {
+			waitForCancel(e)
+			close(cancelled)
+			done()
+		}()
+	}))
+
+	e := exchange.NewExchange(nil)
+	start := time.Now()
+	p.Process(e)
+
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Fatalf("TestMulticastProcessor_Timeout(): expected multicast to time out, took %s", elapsed)
+	}
+	if !errors.Is(e.Error(), context.DeadlineExceeded) {
+		t.Fatalf("TestMulticastProcessor_Timeout() = %v; want %v", e.Error(), context.DeadlineExceeded)
+	}
+	if bodies, _ := e.Message().Body.([]any); len(bodies) != 1 || bodies[0] != "output 0" {
+		t.Fatalf("TestMulticastProcessor_Timeout() = %v; want [output 0]", e.Message().Body)
+	}
+	<-cancelled
+}
+
+func TestMulticastProcessor_CompletionAwareAggregator(t *testing.T) {
+	p := NewProcessor("", "", true, false, aggregator.CompleteAfter(aggregator.NewCollectBodies(), 1))
+	p.AddOutput(fn.NewProcessor("", "", indexBody))
+	p.AddOutput(fn.NewProcessor("", "", waitForCancel))
+
+	e := exchange.NewExchange(nil)
+	start := time.Now()
+	p.Process(e)
+
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Fatalf("TestMulticastProcessor_CompletionAwareAggregator() = %s elapsed; want early completion", elapsed)
+	}
+	if e.IsError() {
+		t.Fatalf("TestMulticastProcessor_CompletionAwareAggregator(): unexpected error: %s", e.Error())
+	}
+	bodies, isSlice := e.Message().Body.([]any)
+	if !isSlice || len(bodies) != 1 || bodies[0] != "output 0" {
+		t.Fatalf("TestMulticastProcessor_CompletionAwareAggregator() = %v; want [output 0]", e.Message().Body)
+	}
+}
@@ -0,0 +1,95 @@
+package oncompletion
+
+import (
+	"context"
+	"github.com/paveldanilin/go-camel/internal/expression"
+	"github.com/paveldanilin/go-camel/internal/processor"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+)
+
+type Mode string
+
+const (
+	ModeAlways       Mode = "always"
+	ModeCompleteOnly Mode = "onCompleteOnly"
+	ModeFailureOnly  Mode = "onFailureOnly"
+)
+
+// onCompletionProcessor processes the handler on the copy of the exchange the route is done with,
+// the outcome of the handler does not affect the exchange.
+type onCompletionProcessor struct {
+	routeName string
+	name      string
+	handler   api.Processor
+	mode      Mode
+	onWhen    expression.Predicate
+	parallel  bool
+}
+
+func NewProcessor(routeName, name string, handler api.Processor) *onCompletionProcessor {
+	return &onCompletionProcessor{
+		routeName: routeName,
+		name:      name,
+		handler:   handler,
+		mode:      ModeAlways,
+	}
+}
+
+func (p *onCompletionProcessor) Name() string {
+	return p.name
+}
+
+func (p *onCompletionProcessor) RouteName() string {
+	return p.routeName
+}
+
+// SetMode sets the outcome of the exchange the handler is processed on.
+func (p *onCompletionProcessor) SetMode(mode Mode) *onCompletionProcessor {
+	p.mode = mode
+	return p
+}
+
+// SetOnWhen sets the predicate the exchange must match, the predicate evaluation error is treated as mismatch.
+func (p *onCompletionProcessor) SetOnWhen(onWhen expression.Predicate) *onCompletionProcessor {
+	p.onWhen = onWhen
+	return p
+}
+
+// SetParallel processes the handler in a separate goroutine.
+func (p *onCompletionProcessor) SetParallel(parallel bool) *onCompletionProcessor {
+	p.parallel = parallel
+	return p
+}
+
+func (p *onCompletionProcessor) Process(e *exchange.Exchange) {
+	if !p.matches(e) {
+		return
+	}
+
+	if p.parallel {
+		// The copy outlives the exchange, so it must not be canceled along with it
+		go processor.Invoke(p.handler, e.CopyWithContext(context.WithoutCancel(e.Context())))
+		return
+	}
+	processor.Invoke(p.handler, e.Copy())
+}
+
+func (p *onCompletionProcessor) matches(e *exchange.Exchange) bool {
+	switch p.mode {
+	case ModeCompleteOnly:
+		if e.IsError() {
+			return false
+		}
+	case ModeFailureOnly:
+		if !e.IsError() {
+			return false
+		}
+	}
+
+	if p.onWhen == nil {
+		return true
+	}
+	matched, err := p.onWhen.Test(e)
+	return err == nil && matched
+}
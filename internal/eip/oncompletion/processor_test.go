@@ -0,0 +1,54 @@
+package oncompletion
+
+import (
+	"errors"
+	"github.com/paveldanilin/go-camel/internal/eip/fn"
+	"github.com/paveldanilin/go-camel/internal/expression"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"testing"
+)
+
+func TestOnCompletionProcessor(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    Mode
+		fail    bool
+		onWhen  expression.Predicate
+		wantRun bool
+	}{
+		{name: "always", mode: ModeAlways, fail: true, wantRun: true},
+		{name: "complete only", mode: ModeCompleteOnly, wantRun: true},
+		{name: "complete only on failure", mode: ModeCompleteOnly, fail: true},
+		{name: "failure only", mode: ModeFailureOnly, fail: true, wantRun: true},
+		{name: "failure only on success", mode: ModeFailureOnly},
+		{name: "on when false", mode: ModeAlways, onWhen: expression.PredicateFunc(func(e *exchange.Exchange) (bool, error) {
+			return false, nil
+		})},
+		{name: "on when error", mode: ModeAlways, onWhen: expression.PredicateFunc(func(e *exchange.Exchange) (bool, error) {
+			return true, errors.New("bad predicate")
+		})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := false
+			handler := fn.NewProcessor("test", "handler", func(e *exchange.Exchange) {
+				run = true
+				e.SetError(errors.New("handler failed"))
+			})
+			p := NewProcessor("test", "onCompletion", handler).SetMode(tt.mode).SetOnWhen(tt.onWhen)
+			e := exchange.NewExchange(nil)
+			if tt.fail {
+				e.SetError(errors.New("failed"))
+			}
+
+			p.Process(e)
+
+			if run != tt.wantRun {
+				t.Fatalf("TestOnCompletionProcessor() = run %v; want %v", run, tt.wantRun)
+			}
+			if e.IsError() != tt.fail {
+				t.Fatalf("TestOnCompletionProcessor() = error %v; want error %v", e.Error(), tt.fail)
+			}
+		})
+	}
+}
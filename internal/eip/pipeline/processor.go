@@ -39,8 +39,25 @@ func (p *pipelineProcessor) AddProcessor(processor api.Processor) *pipelineProce
 func (p *pipelineProcessor) Process(e *exchange.Exchange) {
 	for _, pp := range p.processors {
 		pp.Process(e)
-		if e.IsError() && p.stopOnError {
+		if (e.IsError() && p.stopOnError) || e.IsRouteStopped() {
 			break
 		}
 	}
 }
+
+// ProcessAsync processes the steps one after another, the next step is started by the completion callback
+// of the previous one, so an asynchronous step does not hold the caller goroutine.
+func (p *pipelineProcessor) ProcessAsync(e *exchange.Exchange, done func()) {
+	p.processNext(e, 0, done)
+}
+
+func (p *pipelineProcessor) processNext(e *exchange.Exchange, index int, done func()) {
+	if index >= len(p.processors) || (index > 0 && ((e.IsError() && p.stopOnError) || e.IsRouteStopped())) {
+		done()
+		return
+	}
+
+	api.ToAsync(p.processors[index]).ProcessAsync(e, func() {
+		p.processNext(e, index+1, done)
+	})
+}
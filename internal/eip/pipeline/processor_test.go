@@ -1,11 +1,13 @@
 package pipeline
 
 import (
+	"github.com/paveldanilin/go-camel/internal/eip/delay"
 	"github.com/paveldanilin/go-camel/internal/eip/fn"
 	"github.com/paveldanilin/go-camel/internal/eip/setheader"
 	"github.com/paveldanilin/go-camel/internal/expression"
 	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
 	"testing"
+	"time"
 )
 
 func TestPipelineProcessor(t *testing.T) {
@@ -34,3 +36,30 @@ func TestPipelineProcessor(t *testing.T) {
 		t.Errorf("TestPipelineProcessor() = %d; want %d", result, expected)
 	}
 }
+
+func TestPipelineProcessor_ProcessAsync(t *testing.T) {
+	p := NewProcessor("", "async", true).
+		AddProcessor(setheader.NewProcessor("", "set a", "a", expression.NewConst(1))).
+		AddProcessor(delay.NewProcessor("", "wait", 50)).
+		AddProcessor(fn.NewProcessor("", "calc", func(e *exchange.Exchange) {
+			a, _ := e.Message().Header("a")
+			e.Message().Body = a.(int) + 1
+		}))
+
+	e := exchange.NewExchange(nil)
+	start := time.Now()
+	done := make(chan struct{})
+
+	p.ProcessAsync(e, func() {
+		close(done)
+	})
+
+	if returned := time.Since(start); returned >= 50*time.Millisecond {
+		t.Fatalf("TestPipelineProcessor_ProcessAsync() returned after %s; want immediate return", returned)
+	}
+
+	<-done
+	if e.Message().Body != 2 {
+		t.Errorf("TestPipelineProcessor_ProcessAsync() = %v; want %d", e.Message().Body, 2)
+	}
+}
@@ -0,0 +1,178 @@
+package saga
+
+import (
+	"errors"
+	"fmt"
+	"github.com/paveldanilin/go-camel/internal/processor"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"time"
+)
+
+type Propagation string
+
+const (
+	// PropagationRequired joins the active saga or creates a new one.
+	PropagationRequired Propagation = "required"
+	// PropagationRequiresNew always creates a new saga, the active one is resumed after the block.
+	PropagationRequiresNew Propagation = "requiresNew"
+	// PropagationMandatory joins the active saga, fails if there is no one.
+	PropagationMandatory Propagation = "mandatory"
+	// PropagationSupports joins the active saga if any.
+	PropagationSupports Propagation = "supports"
+	// PropagationNever fails if there is an active saga.
+	PropagationNever Propagation = "never"
+)
+
+// sagaProcessor processes the steps within a saga. The saga created by the processor is completed
+// once the steps succeed and compensated once they fail, the joined saga is finished by its creator.
+type sagaProcessor struct {
+	routeName    string
+	name         string
+	processor    api.Processor
+	service      api.SagaService
+	propagation  Propagation
+	timeout      time.Duration
+	compensation api.Processor
+	completion   api.Processor
+}
+
+func NewProcessor(routeName, name string, processor api.Processor, service api.SagaService) *sagaProcessor {
+	return &sagaProcessor{
+		routeName:   routeName,
+		name:        name,
+		processor:   processor,
+		service:     service,
+		propagation: PropagationRequired,
+	}
+}
+
+func (p *sagaProcessor) Name() string {
+	return p.name
+}
+
+func (p *sagaProcessor) RouteName() string {
+	return p.routeName
+}
+
+func (p *sagaProcessor) SetPropagation(propagation Propagation) *sagaProcessor {
+	p.propagation = propagation
+	return p
+}
+
+// SetTimeout sets the timeout of the created saga, the saga is compensated once it elapses (zero - no timeout).
+func (p *sagaProcessor) SetTimeout(timeout time.Duration) *sagaProcessor {
+	p.timeout = timeout
+	return p
+}
+
+// SetCompensation sets the action processed once the saga is compensated.
+func (p *sagaProcessor) SetCompensation(compensation api.Processor) *sagaProcessor {
+	p.compensation = compensation
+	return p
+}
+
+// SetCompletion sets the action processed once the saga is completed.
+func (p *sagaProcessor) SetCompletion(completion api.Processor) *sagaProcessor {
+	p.completion = completion
+	return p
+}
+
+func (p *sagaProcessor) Process(e *exchange.Exchange) {
+	api.AsyncProcessorFunc(p.ProcessAsync).Process(e)
+}
+
+func (p *sagaProcessor) ProcessAsync(e *exchange.Exchange, done func()) {
+	previousId, hasPrevious := e.Message().Header(exchange.CamelHeaderSagaId)
+	var active api.SagaCoordinator
+	if id, isString := previousId.(string); isString {
+		active, _ = p.service.Saga(id)
+	}
+
+	saga, owner, err := p.resolveSaga(active)
+	if err != nil {
+		e.SetError(fmt.Errorf("saga: %w", err))
+		done()
+		return
+	}
+	if saga == nil {
+		processor.InvokeAsync(p.processor, e, done)
+		return
+	}
+
+	e.Message().SetHeader(exchange.CamelHeaderSagaId, saga.Id())
+	restoreHeader := func() {
+		if !owner {
+			return
+		}
+		if hasPrevious {
+			e.Message().SetHeader(exchange.CamelHeaderSagaId, previousId)
+		} else {
+			e.Message().RemoveHeader(exchange.CamelHeaderSagaId)
+		}
+	}
+
+	if p.compensation != nil || p.completion != nil {
+		if err := saga.BeginStep(e, api.SagaStep{Compensation: p.compensation, Completion: p.completion}); err != nil {
+			if owner {
+				_ = saga.Compensate()
+			}
+			restoreHeader()
+			e.SetError(fmt.Errorf("saga: %w", err))
+			done()
+			return
+		}
+	}
+
+	processor.InvokeAsync(p.processor, e, func() {
+		if owner {
+			p.finish(e, saga)
+		}
+		restoreHeader()
+		done()
+	})
+}
+
+// resolveSaga returns the saga the steps are processed within (nil - no saga) and whether it is created.
+func (p *sagaProcessor) resolveSaga(active api.SagaCoordinator) (api.SagaCoordinator, bool, error) {
+	switch p.propagation {
+	case PropagationRequired, "":
+		if active != nil {
+			return active, false, nil
+		}
+	case PropagationRequiresNew:
+	case PropagationMandatory:
+		if active == nil {
+			return nil, false, errors.New("mandatory propagation: no active saga")
+		}
+		return active, false, nil
+	case PropagationSupports:
+		return active, false, nil
+	case PropagationNever:
+		if active != nil {
+			return nil, false, fmt.Errorf("never propagation: saga %s is active", active.Id())
+		}
+		return nil, false, nil
+	default:
+		return nil, false, fmt.Errorf("unknown propagation: %s", p.propagation)
+	}
+
+	saga, err := p.service.NewSaga(p.timeout)
+	if err != nil {
+		return nil, false, err
+	}
+	return saga, true, nil
+}
+
+// finish compensates the saga if the exchange failed, completes it otherwise.
+func (p *sagaProcessor) finish(e *exchange.Exchange, saga api.SagaCoordinator) {
+	if e.IsError() {
+		if err := saga.Compensate(); err != nil {
+			e.SetError(errors.Join(e.Error(), fmt.Errorf("saga: %w", err)))
+		}
+		return
+	}
+	if err := saga.Complete(); err != nil {
+		e.SetError(fmt.Errorf("saga: %w", err))
+	}
+}
@@ -0,0 +1,112 @@
+package saga
+
+import (
+	"errors"
+	"github.com/paveldanilin/go-camel/internal/eip/fn"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/saga"
+	"testing"
+)
+
+func TestSagaProcessor(t *testing.T) {
+	tests := []struct {
+		name             string
+		fail             bool
+		wantCompensation int
+		wantCompletion   int
+	}{
+		{name: "completed", wantCompletion: 1},
+		{name: "compensated", fail: true, wantCompensation: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compensations, completions := 0, 0
+			body := fn.NewProcessor("test", "body", func(e *exchange.Exchange) {
+				if tt.fail {
+					e.SetError(errors.New("failed"))
+				}
+			})
+			p := NewProcessor("test", "saga", body, saga.NewInMemoryService()).
+				SetCompensation(fn.NewProcessor("test", "compensation", func(e *exchange.Exchange) { compensations++ })).
+				SetCompletion(fn.NewProcessor("test", "completion", func(e *exchange.Exchange) { completions++ }))
+			e := exchange.NewExchange(nil)
+
+			p.Process(e)
+
+			if e.IsError() != tt.fail {
+				t.Fatalf("TestSagaProcessor() = error %v; want error %v", e.Error(), tt.fail)
+			}
+			if compensations != tt.wantCompensation || completions != tt.wantCompletion {
+				t.Fatalf("TestSagaProcessor() = %d compensations, %d completions; want %d, %d",
+					compensations, completions, tt.wantCompensation, tt.wantCompletion)
+			}
+			if _, hasSaga := e.Message().Header(exchange.CamelHeaderSagaId); hasSaga {
+				t.Fatalf("TestSagaProcessor(): expected saga header to be removed")
+			}
+		})
+	}
+}
+
+func TestSagaProcessor_Propagation(t *testing.T) {
+	tests := []struct {
+		propagation Propagation
+		active      bool
+		wantError   bool
+		wantJoined  bool
+		wantSaga    bool
+	}{
+		{propagation: PropagationRequired, active: true, wantJoined: true, wantSaga: true},
+		{propagation: PropagationRequired, wantSaga: true},
+		{propagation: PropagationRequiresNew, active: true, wantSaga: true},
+		{propagation: PropagationMandatory, active: true, wantJoined: true, wantSaga: true},
+		{propagation: PropagationMandatory, wantError: true},
+		{propagation: PropagationSupports, active: true, wantJoined: true, wantSaga: true},
+		{propagation: PropagationSupports},
+		{propagation: PropagationNever, active: true, wantError: true},
+		{propagation: PropagationNever},
+	}
+	for _, tt := range tests {
+		name := string(tt.propagation)
+		if tt.active {
+			name += " active"
+		}
+		t.Run(name, func(t *testing.T) {
+			service := saga.NewInMemoryService()
+			e := exchange.NewExchange(nil)
+
+			var active api.SagaCoordinator
+			if tt.active {
+				active, _ = service.NewSaga(0)
+				e.Message().SetHeader(exchange.CamelHeaderSagaId, active.Id())
+			}
+
+			var sagaId any
+			var hasSaga bool
+			body := fn.NewProcessor("test", "body", func(e *exchange.Exchange) {
+				sagaId, hasSaga = e.Message().Header(exchange.CamelHeaderSagaId)
+			})
+			p := NewProcessor("test", "saga", body, service).SetPropagation(tt.propagation)
+
+			p.Process(e)
+
+			if e.IsError() != tt.wantError {
+				t.Fatalf("TestSagaProcessor_Propagation() = error %v; want error %v", e.Error(), tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if hasSaga != tt.wantSaga {
+				t.Fatalf("TestSagaProcessor_Propagation() = saga %v; want saga %v", hasSaga, tt.wantSaga)
+			}
+			if joined := active != nil && sagaId == active.Id(); joined != tt.wantJoined {
+				t.Fatalf("TestSagaProcessor_Propagation() = joined %v; want joined %v", joined, tt.wantJoined)
+			}
+			if tt.active {
+				if id, _ := e.Message().Header(exchange.CamelHeaderSagaId); id != active.Id() {
+					t.Fatalf("TestSagaProcessor_Propagation(): expected active saga %s to be restored, but got %v", active.Id(), id)
+				}
+			}
+		})
+	}
+}
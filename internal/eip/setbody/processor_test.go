@@ -31,3 +31,16 @@ func TestSetBodyProcessor(t *testing.T) {
 		t.Errorf("TestSetBodyProcessor() = %d; want %d", result, expected)
 	}
 }
+
+func BenchmarkSetBodyProcessor(b *testing.B) {
+	p := NewProcessor("", "set body", expression.MustSimple("header.a * header.b"))
+
+	e := exchange.NewExchange(nil)
+	e.Message().SetHeader("a", 2)
+	e.Message().SetHeader("b", 3)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		p.Process(e)
+	}
+}
@@ -0,0 +1,31 @@
+package setpattern
+
+import (
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+)
+
+type setPatternProcessor struct {
+	routeName string
+	name      string
+	pattern   exchange.ExchangePattern
+}
+
+func NewProcessor(routeName, name string, pattern exchange.ExchangePattern) *setPatternProcessor {
+	return &setPatternProcessor{
+		routeName: routeName,
+		name:      name,
+		pattern:   pattern,
+	}
+}
+
+func (p *setPatternProcessor) Name() string {
+	return p.name
+}
+
+func (p *setPatternProcessor) RouteName() string {
+	return p.routeName
+}
+
+func (p *setPatternProcessor) Process(e *exchange.Exchange) {
+	e.SetPattern(p.pattern)
+}
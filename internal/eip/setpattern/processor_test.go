@@ -0,0 +1,21 @@
+package setpattern
+
+import (
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"testing"
+)
+
+func TestSetPatternProcessor(t *testing.T) {
+	p := NewProcessor("", "", exchange.InOut)
+
+	e := exchange.NewExchange(nil)
+	if e.Pattern() != exchange.InOnly {
+		t.Fatalf("TestSetPatternProcessor() = expected default pattern %s, but got %s", exchange.InOnly, e.Pattern())
+	}
+
+	p.Process(e)
+
+	if !e.IsInOut() {
+		t.Fatalf("TestSetPatternProcessor() = expected pattern %s, but got %s", exchange.InOut, e.Pattern())
+	}
+}
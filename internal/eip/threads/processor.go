@@ -0,0 +1,55 @@
+package threads
+
+import (
+	"fmt"
+	"github.com/paveldanilin/go-camel/internal/processor"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+)
+
+// threadsProcessor hands the exchange over to the executor service which processes the rest of the route.
+type threadsProcessor struct {
+	routeName       string
+	name            string
+	executorService api.ExecutorService
+	next            api.Processor
+}
+
+func NewProcessor(routeName, name string, executorService api.ExecutorService, next api.Processor) *threadsProcessor {
+	return &threadsProcessor{
+		routeName:       routeName,
+		name:            name,
+		executorService: executorService,
+		next:            next,
+	}
+}
+
+func (p *threadsProcessor) Name() string {
+	return p.name
+}
+
+func (p *threadsProcessor) RouteName() string {
+	return p.routeName
+}
+
+// Process waits until the rest of the route is processed by the executor service.
+func (p *threadsProcessor) Process(e *exchange.Exchange) {
+	api.AsyncProcessorFunc(p.ProcessAsync).Process(e)
+}
+
+// ProcessAsync submits the rest of the route to the executor service and releases the caller goroutine,
+// done is called by the executor service goroutine.
+func (p *threadsProcessor) ProcessAsync(e *exchange.Exchange, done func()) {
+	if p.next == nil {
+		done()
+		return
+	}
+
+	err := p.executorService.Submit(func() {
+		processor.InvokeAsync(p.next, e, done)
+	})
+	if err != nil {
+		e.SetError(fmt.Errorf("threads: %w", err))
+		done()
+	}
+}
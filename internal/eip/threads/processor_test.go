@@ -0,0 +1,53 @@
+package threads
+
+import (
+	"errors"
+	"github.com/paveldanilin/go-camel/internal/eip/fn"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"testing"
+)
+
+// goroutineExecutor runs every task in a new goroutine or rejects it.
+type goroutineExecutor struct {
+	err error
+}
+
+func (ex goroutineExecutor) Submit(task func()) error {
+	if ex.err != nil {
+		return ex.err
+	}
+	go task()
+	return nil
+}
+
+func TestThreadsProcessor(t *testing.T) {
+	next := fn.NewProcessor("", "", func(e *exchange.Exchange) {
+		e.Message().Body = "processed"
+	})
+	p := NewProcessor("", "", goroutineExecutor{}, next)
+
+	e := exchange.NewExchange(nil)
+	p.Process(e)
+
+	if e.Message().Body != "processed" {
+		t.Fatalf("TestThreadsProcessor() = %v; want %s", e.Message().Body, "processed")
+	}
+}
+
+func TestThreadsProcessor_Rejected(t *testing.T) {
+	rejectedErr := errors.New("rejected")
+	next := fn.NewProcessor("", "", func(e *exchange.Exchange) {
+		e.Message().Body = "processed"
+	})
+	p := NewProcessor("", "", goroutineExecutor{err: rejectedErr}, next)
+
+	e := exchange.NewExchange(nil)
+	p.Process(e)
+
+	if !errors.Is(e.Error(), rejectedErr) {
+		t.Fatalf("TestThreadsProcessor_Rejected() = %v; want %v", e.Error(), rejectedErr)
+	}
+	if e.Message().Body != nil {
+		t.Fatalf("TestThreadsProcessor_Rejected() = %v; want nil body", e.Message().Body)
+	}
+}
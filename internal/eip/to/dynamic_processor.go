@@ -9,7 +9,7 @@ import (
 )
 
 type endpointRegistry interface {
-	Endpoint(uri string) api.Endpoint
+	ResolveEndpoint(uri string) (api.Endpoint, error)
 }
 
 type dynamicToProcessor struct {
@@ -51,6 +51,22 @@ func (p *dynamicToProcessor) RouteName() string {
 }
 
 func (p *dynamicToProcessor) Process(e *exchange.Exchange) {
+	if producer := p.resolveProducer(e); producer != nil {
+		producer.Process(e)
+	}
+}
+
+func (p *dynamicToProcessor) ProcessAsync(e *exchange.Exchange, done func()) {
+	producer := p.resolveProducer(e)
+	if producer == nil {
+		done()
+		return
+	}
+	api.ToAsync(producer).ProcessAsync(e, done)
+}
+
+// resolveProducer creates the producer of the resolved endpoint, returns nil and sets the exchange error on failure.
+func (p *dynamicToProcessor) resolveProducer(e *exchange.Exchange) api.Producer {
 	// Prepare data for dynamic resolving
 	// data[] = {
 	//	'id':		 	exchange.Message's id
@@ -79,23 +95,22 @@ func (p *dynamicToProcessor) Process(e *exchange.Exchange) {
 	uri, err := p.uriTpl.Render(data)
 	if err != nil {
 		e.SetError(fmt.Errorf("failed to resolve dynamic uri '%s': %w", p.uriTpl.Template(), err))
-		return
+		return nil
 	}
 
-	// TODO: cache [URI]=Producer?
-	// Resolve endpoint
-	endpoint := p.endpointRegistry.Endpoint(uri)
-	if endpoint == nil {
-		e.SetError(fmt.Errorf("endpoint not found for uri '%s'", uri))
-		return
+	// Resolve endpoint (endpoints are cached by the registry)
+	endpoint, err := p.endpointRegistry.ResolveEndpoint(uri)
+	if err != nil {
+		e.SetError(fmt.Errorf("failed to resolve endpoint for uri '%s': %w", uri, err))
+		return nil
 	}
 
 	// Create producer
 	producer, err := endpoint.CreateProducer()
 	if err != nil {
 		e.SetError(err)
-		return
+		return nil
 	}
 
-	producer.Process(e)
+	return producer
 }
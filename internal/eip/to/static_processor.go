@@ -30,3 +30,7 @@ func (p *staticToProcessor) RouteName() string {
 func (p *staticToProcessor) Process(e *exchange.Exchange) {
 	p.producer.Process(e)
 }
+
+func (p *staticToProcessor) ProcessAsync(e *exchange.Exchange, done func()) {
+	api.ToAsync(p.producer).ProcessAsync(e, done)
+}
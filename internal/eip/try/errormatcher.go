@@ -2,14 +2,19 @@ package try
 
 import (
 	"errors"
+	"github.com/paveldanilin/go-camel/internal/expression"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"reflect"
 	"regexp"
 	"strings"
 )
 
-type ErrorMatcher func(error) bool
+// ErrorMatcher reports whether the error of the failed exchange is matched.
+type ErrorMatcher func(e *exchange.Exchange, err error) bool
 
 func ErrorEquals(str string) ErrorMatcher {
-	return func(err error) bool {
+	return func(_ *exchange.Exchange, err error) bool {
 		if err == nil {
 			return false
 		}
@@ -21,7 +26,7 @@ func ErrorEquals(str string) ErrorMatcher {
 func ErrorContains(str string) ErrorMatcher {
 	substrLower := strings.ToLower(str)
 
-	return func(err error) bool {
+	return func(_ *exchange.Exchange, err error) bool {
 		if err == nil {
 			return false
 		}
@@ -32,16 +37,52 @@ func ErrorContains(str string) ErrorMatcher {
 	}
 }
 
-func ErrorIs(target string) ErrorMatcher {
-	return func(err error) bool {
-		return errors.Is(err, errors.New(target))
+// ErrorIs matches the errors that wrap the target (see errors.Is).
+func ErrorIs(target error) ErrorMatcher {
+	return func(_ *exchange.Exchange, err error) bool {
+		return err != nil && errors.Is(err, target)
+	}
+}
+
+// ErrorIsMessage matches the errors that wrap an error with the given message,
+// it is used when the target error value is not available (e.g. the route is loaded from a document).
+func ErrorIsMessage(target string) ErrorMatcher {
+	return func(_ *exchange.Exchange, err error) bool {
+		for _, wrapped := range errs.Chain(err) {
+			if wrapped.Error() == target {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ErrorAs matches the errors that wrap an error of the given type (see errors.As).
+func ErrorAs(targetType reflect.Type) ErrorMatcher {
+	return func(_ *exchange.Exchange, err error) bool {
+		if err == nil {
+			return false
+		}
+		return errors.As(err, reflect.New(targetType).Interface())
+	}
+}
+
+// ErrorPredicate matches the error if the predicate is true for the failed exchange,
+// the predicate evaluation error is treated as mismatch.
+func ErrorPredicate(predicate expression.Predicate) ErrorMatcher {
+	return func(e *exchange.Exchange, err error) bool {
+		if err == nil {
+			return false
+		}
+		matched, predicateErr := predicate.Test(e)
+		return predicateErr == nil && matched
 	}
 }
 
 func ErrorMatches(pattern string) ErrorMatcher {
 	errRegex := regexp.MustCompile(pattern)
 
-	return func(err error) bool {
+	return func(_ *exchange.Exchange, err error) bool {
 		if err == nil {
 			return false
 		}
@@ -50,7 +91,35 @@ func ErrorMatches(pattern string) ErrorMatcher {
 }
 
 func AnyError() ErrorMatcher {
-	return func(err error) bool {
+	return func(_ *exchange.Exchange, err error) bool {
+		return err != nil
+	}
+}
+
+func AnyOf(matchers ...ErrorMatcher) ErrorMatcher {
+	return func(e *exchange.Exchange, err error) bool {
+		for _, matcher := range matchers {
+			if matcher(e, err) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func AllOf(matchers ...ErrorMatcher) ErrorMatcher {
+	return func(e *exchange.Exchange, err error) bool {
+		for _, matcher := range matchers {
+			if !matcher(e, err) {
+				return false
+			}
+		}
 		return err != nil
 	}
 }
+
+func Not(matcher ErrorMatcher) ErrorMatcher {
+	return func(e *exchange.Exchange, err error) bool {
+		return err != nil && !matcher(e, err)
+	}
+}
@@ -0,0 +1,57 @@
+package try
+
+import (
+	"errors"
+	"fmt"
+	"github.com/paveldanilin/go-camel/internal/expression"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"reflect"
+	"testing"
+)
+
+type codeError struct {
+	code int
+}
+
+func (e *codeError) Error() string {
+	return fmt.Sprintf("code %d", e.code)
+}
+
+func TestErrorMatchers(t *testing.T) {
+	sentinel := errors.New("not found")
+	wrapped := fmt.Errorf("lookup: %w", sentinel)
+	typed := fmt.Errorf("call: %w", &codeError{code: 404})
+
+	e := exchange.NewExchange(nil)
+	e.Message().SetHeader("code", 404)
+
+	tests := []struct {
+		name    string
+		matcher ErrorMatcher
+		err     error
+		want    bool
+	}{
+		{name: "is", matcher: ErrorIs(sentinel), err: wrapped, want: true},
+		{name: "is other value", matcher: ErrorIs(errors.New("not found")), err: wrapped, want: false},
+		{name: "is message", matcher: ErrorIsMessage("not found"), err: wrapped, want: true},
+		{name: "is message joined", matcher: ErrorIsMessage("not found"), err: errors.Join(typed, wrapped), want: true},
+		{name: "is message multi", matcher: ErrorIsMessage("code 404"), err: &errs.MultiError{Errors: []error{wrapped, typed}}, want: true},
+		{name: "is message nil error", matcher: ErrorIsMessage("not found"), err: nil, want: false},
+		{name: "as", matcher: ErrorAs(reflect.TypeFor[*codeError]()), err: typed, want: true},
+		{name: "as mismatch", matcher: ErrorAs(reflect.TypeFor[*codeError]()), err: wrapped, want: false},
+		{name: "predicate", matcher: ErrorPredicate(expression.NewPredicateFromExpression(expression.MustSimple("header.code == 404"))), err: typed, want: true},
+		{name: "predicate nil error", matcher: ErrorPredicate(expression.NewPredicateFromExpression(expression.MustSimple("true"))), err: nil, want: false},
+		{name: "any of", matcher: AnyOf(ErrorIs(sentinel), ErrorContains("code")), err: typed, want: true},
+		{name: "all of", matcher: AllOf(ErrorIs(sentinel), ErrorContains("code")), err: typed, want: false},
+		{name: "not", matcher: Not(ErrorIs(sentinel)), err: typed, want: true},
+		{name: "not nil error", matcher: Not(ErrorIs(sentinel)), err: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.matcher(e, tt.err); got != tt.want {
+				t.Fatalf("TestErrorMatchers() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
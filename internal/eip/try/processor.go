@@ -64,34 +64,104 @@ func (p *tryProcessor) Process(e *exchange.Exchange) {
 			originalErr = e.Error()
 			break
 		}
+		if e.IsRouteStopped() {
+			break
+		}
 	}
 
 	// Catch-block
 	caught := false
-	if originalErr != nil && len(p.catchClauses) > 0 {
-		for _, c := range p.catchClauses {
-			if c.errorMatcher(originalErr) {
-				// Execute handler
-				processor.Invoke(c.handler, e)
-				caught = true
-				// Clear error on success handling (Camel-like style).
-				e.SetError(nil)
-				// First match only
-				break
-			}
-		}
+	if handler := p.catchHandler(e, originalErr); handler != nil {
+		// Execute handler
+		processor.Invoke(handler, e)
+		caught = true
+		// Clear error on success handling (Camel-like style).
+		e.SetError(nil)
 	}
 
 	// Finally-block
-	if len(p.finallyProcessors) > 0 {
-		for _, pf := range p.finallyProcessors {
-			processor.Invoke(pf, e)
+	for _, pf := range p.finallyProcessors {
+		processor.Invoke(pf, e)
+	}
+
+	p.completeError(e, originalErr, caught)
+}
+
+// ProcessAsync runs try, catch and finally blocks by means of completion callbacks,
+// so asynchronous steps inside the blocks do not hold the caller goroutine.
+func (p *tryProcessor) ProcessAsync(e *exchange.Exchange, done func()) {
+	p.tryNext(e, 0, func(originalErr error) {
+		finally := func(caught bool) {
+			p.finallyNext(e, 0, func() {
+				p.completeError(e, originalErr, caught)
+				done()
+			})
 		}
 
-		// In case of error/panic in finally , combines with originalErr (if any)
-		if e.IsError() && originalErr != nil && !caught {
-			e.SetError(fmt.Errorf("original error: %w; finally error: %v", originalErr, e.Error()))
+		handler := p.catchHandler(e, originalErr)
+		if handler == nil {
+			finally(false)
+			return
+		}
+		processor.InvokeAsync(handler, e, func() {
+			// Clear error on success handling (Camel-like style).
+			e.SetError(nil)
+			finally(true)
+		})
+	})
+}
+
+func (p *tryProcessor) tryNext(e *exchange.Exchange, index int, done func(originalErr error)) {
+	if index >= len(p.processors) {
+		done(nil)
+		return
+	}
+
+	processor.InvokeAsync(p.processors[index], e, func() {
+		if e.IsError() {
+			done(e.Error())
+			return
+		}
+		if e.IsRouteStopped() {
+			done(nil)
+			return
 		}
+		p.tryNext(e, index+1, done)
+	})
+}
+
+func (p *tryProcessor) finallyNext(e *exchange.Exchange, index int, done func()) {
+	if index >= len(p.finallyProcessors) {
+		done()
+		return
+	}
+
+	processor.InvokeAsync(p.finallyProcessors[index], e, func() {
+		p.finallyNext(e, index+1, done)
+	})
+}
+
+// catchHandler returns the handler of the first catch clause that matches the error (nil if no one matches),
+// the matched error is exposed to the handler by means of exchange.CamelPropertyExceptionCaught.
+func (p *tryProcessor) catchHandler(e *exchange.Exchange, err error) api.Processor {
+	if err == nil {
+		return nil
+	}
+	for _, c := range p.catchClauses {
+		if c.errorMatcher(e, err) {
+			e.SetProperty(exchange.CamelPropertyExceptionCaught, err)
+			e.AddErrorHistory(err)
+			return c.handler
+		}
+	}
+	return nil
+}
+
+// completeError sets the resulting error of the try processor after the finally-block.
+func (p *tryProcessor) completeError(e *exchange.Exchange, originalErr error, caught bool) {
+	// In case of error/panic in finally , combines with originalErr (if any)
+	if len(p.finallyProcessors) > 0 && e.IsError() && originalErr != nil && !caught {
+		e.SetError(fmt.Errorf("original error: %w; finally error: %v", originalErr, e.Error()))
 	}
 
 	// Restore originalErr if catch-block does not catch error
@@ -7,6 +7,7 @@ import (
 	"github.com/paveldanilin/go-camel/internal/eip/seterror"
 	"github.com/paveldanilin/go-camel/internal/eip/setheader"
 	"github.com/paveldanilin/go-camel/internal/expression"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
 	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
 	"testing"
 )
@@ -37,3 +38,55 @@ func TestDoTryProcessor_Error(t *testing.T) {
 		t.Errorf("TestDoTryProcessor_Error() = %v; want %s", e.Message().Body, expected)
 	}
 }
+
+func TestDoTryProcessor_ProcessAsync(t *testing.T) {
+	tryBlock := NewProcessor("", "async section").
+		AddProcessor(api.AsyncProcessorFunc(func(e *exchange.Exchange, done func()) {
+			go func() {
+				e.SetError(errors.New("remote call failed"))
+				done()
+			}()
+		})).
+		AddProcessor(setbody.NewProcessor("", "not reachable", expression.NewConst("NOT REACHABLE"))).
+		AddCatch(ErrorContains("remote call failed"), setheader.NewProcessor("", "", "ERROR", expression.MustSimple("error"))).
+		AddFinally(setbody.NewProcessor("", "", expression.NewConst("RESULT")))
+
+	e := exchange.NewExchange(nil)
+	done := make(chan struct{})
+
+	tryBlock.ProcessAsync(e, func() {
+		close(done)
+	})
+	<-done
+
+	if e.IsError() {
+		t.Fatalf("TestDoTryProcessor_ProcessAsync() = %v; want no error", e.Error())
+	}
+	if _, hasHeader := e.Message().Header("ERROR"); !hasHeader {
+		t.Errorf("TestDoTryProcessor_ProcessAsync(): expected error to be caught")
+	}
+	if e.Message().Body != "RESULT" {
+		t.Errorf("TestDoTryProcessor_ProcessAsync() = %v; want %s", e.Message().Body, "RESULT")
+	}
+}
+
+func TestDoTryProcessor_ProcessAsyncPanic(t *testing.T) {
+	tryBlock := NewProcessor("", "panic section").
+		AddProcessor(api.AsyncProcessorFunc(func(e *exchange.Exchange, done func()) {
+			panic("boom")
+		}))
+
+	e := exchange.NewExchange(nil)
+	completed := 0
+
+	tryBlock.ProcessAsync(e, func() {
+		completed++
+	})
+
+	if completed != 1 {
+		t.Fatalf("TestDoTryProcessor_ProcessAsyncPanic(): done called %d times; want 1", completed)
+	}
+	if e.Error() == nil || e.Error().Error() != "boom" {
+		t.Errorf("TestDoTryProcessor_ProcessAsyncPanic() = %v; want %s", e.Error(), "boom")
+	}
+}
@@ -0,0 +1,1015 @@
+package expression
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// JSONPathResult defines the result of the JSONPath expression.
+type JSONPathResult string
+
+const (
+	// JSONPathResultAuto returns the value for the definite path (e.g. $.order.id) and the list of values otherwise.
+	JSONPathResultAuto JSONPathResult = ""
+	// JSONPathResultSingle returns the first matched value.
+	JSONPathResultSingle JSONPathResult = "single"
+	// JSONPathResultList returns the list of the matched values.
+	JSONPathResultList JSONPathResult = "list"
+)
+
+// ErrJSONPathNotFound is returned if the path does not match the body.
+var ErrJSONPathNotFound = errors.New("path not found")
+
+// jsonPath is https://goessner.net/articles/JsonPath/ evaluated against the Message body,
+// which is either JSON ([]byte, string, json.RawMessage) or already unmarshalled value (maps, slices, structs).
+// The JSON numbers are decoded as float64, the struct fields are matched by the json tag or by the name.
+//
+// Syntax:
+//
+//	$				the root (the body)
+//	.name, ['name']	the member, ['a','b'] - several members
+//	.*, [*]			all members or elements
+//	..name, ..*		the recursive descent
+//	[0], [-1], [0,2]	the elements by index (a negative index counts from the end)
+//	[start:end:step]	the slice
+//	[?(filter)]		the elements matching the filter, e.g. [?(@.qty > 1 && @.sku =~ /^A-/i)]
+//
+// Filter operators: ==, !=, <, <=, >, >=, =~ (regex), &&, ||, !, (...); @.path alone tests the existence.
+//
+// As a Predicate the expression is TRUE if the definite path refers to TRUE or to any other non-nil value,
+// or if the indefinite path matches anything. A missing path is FALSE.
+type jsonPath struct {
+	raw                string
+	segments           []jsonPathSegment
+	definite           bool
+	suppressExceptions bool
+	result             JSONPathResult
+}
+
+func NewJSONPath(path string) (*jsonPath, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpath '%s': %w", path, err)
+	}
+	return &jsonPath{
+		raw:      path,
+		segments: segments,
+		definite: isDefiniteJSONPath(segments),
+	}, nil
+}
+
+func MustJSONPath(path string) *jsonPath {
+	p, err := NewJSONPath(path)
+	if err != nil {
+		panic(fmt.Errorf("camel: expression: %w", err))
+	}
+	return p
+}
+
+// SetSuppressExceptions makes Eval return nil (or the empty list) instead of ErrJSONPathNotFound.
+func (p *jsonPath) SetSuppressExceptions(suppressExceptions bool) *jsonPath {
+	p.suppressExceptions = suppressExceptions
+	return p
+}
+
+func (p *jsonPath) SetResult(result JSONPathResult) *jsonPath {
+	p.result = result
+	return p
+}
+
+func (p *jsonPath) Eval(e *exchange.Exchange) (any, error) {
+	doc, err := jsonPathDocument(e.Message().Body)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpath '%s': %w", p.raw, err)
+	}
+
+	matches, err := p.find(doc)
+	if err == nil && len(matches) == 0 && p.result == JSONPathResultSingle {
+		err = fmt.Errorf("jsonpath '%s': %w", p.raw, ErrJSONPathNotFound)
+	}
+	if err != nil {
+		if !p.suppressExceptions {
+			return nil, err
+		}
+		matches = nil
+	}
+
+	switch p.result {
+	case JSONPathResultList:
+		if matches == nil {
+			return []any{}, nil
+		}
+		return matches, nil
+	case JSONPathResultSingle:
+		if len(matches) == 0 {
+			return nil, nil
+		}
+		return matches[0], nil
+	}
+	if p.definite {
+		if len(matches) == 0 {
+			return nil, nil
+		}
+		return matches[0], nil
+	}
+	if matches == nil {
+		return []any{}, nil
+	}
+	return matches, nil
+}
+
+func (p *jsonPath) Test(e *exchange.Exchange) (bool, error) {
+	doc, err := jsonPathDocument(e.Message().Body)
+	if err != nil {
+		return false, fmt.Errorf("jsonpath '%s': %w", p.raw, err)
+	}
+
+	matches, err := p.find(doc)
+	if err != nil || len(matches) == 0 {
+		return false, nil
+	}
+	if p.definite || p.result == JSONPathResultSingle {
+		if b, isBool := matches[0].(bool); isBool {
+			return b, nil
+		}
+		return matches[0] != nil, nil
+	}
+	return true, nil
+}
+
+func (p *jsonPath) String() string {
+	return p.raw
+}
+
+// find returns the values matched by the path, a definite path fails with ErrJSONPathNotFound.
+func (p *jsonPath) find(root any) ([]any, error) {
+	matches, missing := evalJSONPath(p.segments, root, root)
+	if missing >= 0 && p.definite {
+		return nil, fmt.Errorf("jsonpath '%s': %w: %s", p.raw, ErrJSONPathNotFound, jsonPathPrefix(p.segments[:missing+1]))
+	}
+	return matches, nil
+}
+
+// jsonPathDocument decodes the JSON body, other bodies are returned as is.
+func jsonPathDocument(body any) (any, error) {
+	var data []byte
+	switch x := body.(type) {
+	case []byte:
+		data = x
+	case json.RawMessage:
+		data = x
+	case string:
+		data = []byte(x)
+	default:
+		return body, nil
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON body: %w", err)
+	}
+	return doc, nil
+}
+
+// evalJSONPath applies the segments to the value, missing is the index of the segment that matched nothing (-1 if none).
+func evalJSONPath(segments []jsonPathSegment, value, root any) (matches []any, missing int) {
+	current := []any{value}
+	for i, segment := range segments {
+		var next []any
+		for _, v := range current {
+			if segment.recursive {
+				for _, d := range jsonPathDescendants(v, nil) {
+					next = segment.selector.selectFrom(d, root, next)
+				}
+			} else {
+				next = segment.selector.selectFrom(v, root, next)
+			}
+		}
+		if len(next) == 0 {
+			return nil, i
+		}
+		current = next
+	}
+	return current, -1
+}
+
+func isDefiniteJSONPath(segments []jsonPathSegment) bool {
+	for _, segment := range segments {
+		if segment.recursive {
+			return false
+		}
+		switch s := segment.selector.(type) {
+		case jsonPathNames:
+			if len(s) != 1 {
+				return false
+			}
+		case jsonPathIndexes:
+			if len(s) != 1 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func jsonPathPrefix(segments []jsonPathSegment) string {
+	var sb strings.Builder
+	sb.WriteString("$")
+	for _, segment := range segments {
+		sb.WriteString(segment.raw)
+	}
+	return sb.String()
+}
+
+type jsonPathSegment struct {
+	raw       string
+	recursive bool
+	selector  jsonPathSelector
+}
+
+type jsonPathSelector interface {
+	// selectFrom appends the selected children of the value to out.
+	selectFrom(value, root any, out []any) []any
+}
+
+type jsonPathNames []string
+
+func (s jsonPathNames) selectFrom(value, _ any, out []any) []any {
+	for _, name := range s {
+		if v, exists := jsonPathMember(value, name); exists {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+type jsonPathWildcard struct{}
+
+func (jsonPathWildcard) selectFrom(value, _ any, out []any) []any {
+	return append(out, jsonPathChildren(value)...)
+}
+
+type jsonPathIndexes []int
+
+func (s jsonPathIndexes) selectFrom(value, _ any, out []any) []any {
+	elements, isList := jsonPathElements(value)
+	if !isList {
+		return out
+	}
+	for _, index := range s {
+		if index < 0 {
+			index += len(elements)
+		}
+		if index >= 0 && index < len(elements) {
+			out = append(out, elements[index])
+		}
+	}
+	return out
+}
+
+type jsonPathSlice struct {
+	start, end, step *int
+}
+
+func (s jsonPathSlice) selectFrom(value, _ any, out []any) []any {
+	elements, isList := jsonPathElements(value)
+	if !isList {
+		return out
+	}
+	n := len(elements)
+	step := 1
+	if s.step != nil {
+		step = *s.step
+	}
+	if step == 0 {
+		return out
+	}
+
+	bound := func(i *int, def int) int {
+		if i == nil {
+			return def
+		}
+		v := *i
+		if v < 0 {
+			v += n
+		}
+		return v
+	}
+	if step > 0 {
+		start, end := max(bound(s.start, 0), 0), min(bound(s.end, n), n)
+		for i := start; i < end; i += step {
+			out = append(out, elements[i])
+		}
+		return out
+	}
+	start, end := min(bound(s.start, n-1), n-1), max(bound(s.end, -n-1), -1)
+	for i := start; i > end; i += step {
+		out = append(out, elements[i])
+	}
+	return out
+}
+
+type jsonPathFilterSelector struct {
+	filter jsonPathFilter
+}
+
+func (s jsonPathFilterSelector) selectFrom(value, root any, out []any) []any {
+	for _, child := range jsonPathChildren(value) {
+		if s.filter.test(child, root) {
+			out = append(out, child)
+		}
+	}
+	return out
+}
+
+type jsonPathFilter interface {
+	test(current, root any) bool
+}
+
+type jsonPathOr struct{ left, right jsonPathFilter }
+
+func (f jsonPathOr) test(current, root any) bool {
+	return f.left.test(current, root) || f.right.test(current, root)
+}
+
+type jsonPathAnd struct{ left, right jsonPathFilter }
+
+func (f jsonPathAnd) test(current, root any) bool {
+	return f.left.test(current, root) && f.right.test(current, root)
+}
+
+type jsonPathNot struct{ filter jsonPathFilter }
+
+func (f jsonPathNot) test(current, root any) bool {
+	return !f.filter.test(current, root)
+}
+
+// jsonPathExists tests the existence of the path.
+type jsonPathExists struct{ operand jsonPathOperand }
+
+func (f jsonPathExists) test(current, root any) bool {
+	_, exists := f.operand.resolve(current, root)
+	return exists
+}
+
+type jsonPathComparison struct {
+	left, right jsonPathOperand
+	op          string
+	regex       *regexp.Regexp
+}
+
+func (f jsonPathComparison) test(current, root any) bool {
+	left, exists := f.left.resolve(current, root)
+	if !exists {
+		return false
+	}
+	if f.op == "=~" {
+		s, isString := left.(string)
+		return isString && f.regex.MatchString(s)
+	}
+	right, exists := f.right.resolve(current, root)
+	if !exists {
+		return false
+	}
+
+	switch f.op {
+	case "==":
+		return jsonPathEqual(left, right)
+	case "!=":
+		return !jsonPathEqual(left, right)
+	}
+
+	var cmp int
+	if l, isNumber := jsonPathNumber(left); isNumber {
+		r, isNumber := jsonPathNumber(right)
+		if !isNumber {
+			return false
+		}
+		cmp = compareFloat(l, r)
+	} else if l, isString := left.(string); isString {
+		r, isString := right.(string)
+		if !isString {
+			return false
+		}
+		cmp = strings.Compare(l, r)
+	} else {
+		return false
+	}
+
+	switch f.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// jsonPathOperand is either the literal or the path relative to the current value (@) or to the root ($).
+type jsonPathOperand struct {
+	value    any
+	segments []jsonPathSegment
+	isPath   bool
+	fromRoot bool
+	definite bool
+}
+
+func (o jsonPathOperand) resolve(current, root any) (any, bool) {
+	if !o.isPath {
+		return o.value, true
+	}
+	value := current
+	if o.fromRoot {
+		value = root
+	}
+	matches, missing := evalJSONPath(o.segments, value, root)
+	if missing >= 0 {
+		return nil, false
+	}
+	if o.definite {
+		return matches[0], true
+	}
+	return matches, true
+}
+
+func jsonPathEqual(a, b any) bool {
+	if x, isNumber := jsonPathNumber(a); isNumber {
+		y, isNumber := jsonPathNumber(b)
+		return isNumber && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func jsonPathNumber(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case int:
+		return float64(x), true
+	case json.Number:
+		f, err := x.Float64()
+		return f, err == nil
+	case nil, string, bool:
+		return 0, false
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func jsonPathIndirect(value any) reflect.Value {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+// jsonPathMember returns the map value or the struct field (matched by the json tag or by the name).
+func jsonPathMember(value any, name string) (any, bool) {
+	if m, isMap := value.(map[string]any); isMap {
+		v, exists := m[name]
+		return v, exists
+	}
+
+	rv := jsonPathIndirect(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		v := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+		if !v.IsValid() {
+			return nil, false
+		}
+		return v.Interface(), true
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if fieldName, exported := jsonPathFieldName(t.Field(i)); exported && fieldName == name {
+				return rv.Field(i).Interface(), true
+			}
+		}
+		if f, found := t.FieldByName(name); found && f.IsExported() {
+			return rv.FieldByIndex(f.Index).Interface(), true
+		}
+	}
+	return nil, false
+}
+
+func jsonPathFieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	tagName, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	switch tagName {
+	case "-":
+		return "", false
+	case "":
+		return f.Name, true
+	}
+	return tagName, true
+}
+
+func jsonPathElements(value any) ([]any, bool) {
+	if list, isList := value.([]any); isList {
+		return list, true
+	}
+
+	rv := jsonPathIndirect(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	elements := make([]any, rv.Len())
+	for i := range elements {
+		elements[i] = rv.Index(i).Interface()
+	}
+	return elements, true
+}
+
+// jsonPathChildren returns the elements of the list or the member values of the object (ordered by the member name).
+func jsonPathChildren(value any) []any {
+	if elements, isList := jsonPathElements(value); isList {
+		return elements
+	}
+	if m, isMap := value.(map[string]any); isMap {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		children := make([]any, len(keys))
+		for i, k := range keys {
+			children[i] = m[k]
+		}
+		return children
+	}
+
+	rv := jsonPathIndirect(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		children := make([]any, len(keys))
+		for i, k := range keys {
+			children[i] = rv.MapIndex(k).Interface()
+		}
+		return children
+	case reflect.Struct:
+		var children []any
+		for i := 0; i < rv.NumField(); i++ {
+			if _, exported := jsonPathFieldName(rv.Type().Field(i)); exported {
+				children = append(children, rv.Field(i).Interface())
+			}
+		}
+		return children
+	}
+	return nil
+}
+
+// jsonPathDescendants appends the value and all its descendants to out.
+func jsonPathDescendants(value any, out []any) []any {
+	out = append(out, value)
+	for _, child := range jsonPathChildren(value) {
+		out = jsonPathDescendants(child, out)
+	}
+	return out
+}
+
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	p := &jsonPathParser{s: strings.TrimSpace(path)}
+	if !p.consume("$") {
+		return nil, errors.New("path must start with '$'")
+	}
+	segments, err := p.parseSegments(false)
+	if err != nil {
+		return nil, err
+	}
+	return segments, nil
+}
+
+type jsonPathParser struct {
+	s   string
+	pos int
+}
+
+func (p *jsonPathParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("at %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *jsonPathParser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *jsonPathParser) consume(prefix string) bool {
+	if strings.HasPrefix(p.s[p.pos:], prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+	return false
+}
+
+func (p *jsonPathParser) skipSpaces() {
+	for !p.eof() && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// parseSegments parses the segments until the end of the path, in the filter it stops at the first unknown char.
+func (p *jsonPathParser) parseSegments(inFilter bool) ([]jsonPathSegment, error) {
+	var segments []jsonPathSegment
+	for !p.eof() {
+		start := p.pos
+		segment := jsonPathSegment{}
+		var err error
+		switch {
+		case p.consume(".."):
+			segment.recursive = true
+			if p.consume("[") {
+				segment.selector, err = p.parseBracket()
+			} else {
+				segment.selector, err = p.parseDotName()
+			}
+		case p.consume("."):
+			segment.selector, err = p.parseDotName()
+		case p.consume("["):
+			segment.selector, err = p.parseBracket()
+		default:
+			if inFilter {
+				return segments, nil
+			}
+			return nil, p.errorf("unexpected '%c'", p.s[p.pos])
+		}
+		if err != nil {
+			return nil, err
+		}
+		segment.raw = p.s[start:p.pos]
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+func (p *jsonPathParser) parseDotName() (jsonPathSelector, error) {
+	if p.consume("*") {
+		return jsonPathWildcard{}, nil
+	}
+	start := p.pos
+	for !p.eof() {
+		r := rune(p.s[p.pos])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' && r < 0x80 {
+			break
+		}
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, p.errorf("member name expected")
+	}
+	return jsonPathNames{p.s[start:p.pos]}, nil
+}
+
+func (p *jsonPathParser) parseBracket() (jsonPathSelector, error) {
+	p.skipSpaces()
+	if p.eof() {
+		return nil, p.errorf("unclosed '['")
+	}
+
+	var selector jsonPathSelector
+	switch c := p.s[p.pos]; {
+	case c == '*':
+		p.pos++
+		selector = jsonPathWildcard{}
+	case c == '?':
+		p.pos++
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		selector = jsonPathFilterSelector{filter: filter}
+	case c == '\'' || c == '"':
+		var names jsonPathNames
+		for {
+			name, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, name)
+			p.skipSpaces()
+			if !p.consume(",") {
+				break
+			}
+			p.skipSpaces()
+		}
+		selector = names
+	default:
+		end := strings.IndexByte(p.s[p.pos:], ']')
+		if end < 0 {
+			return nil, p.errorf("unclosed '['")
+		}
+		raw := p.s[p.pos : p.pos+end]
+		var err error
+		if strings.Contains(raw, ":") {
+			selector, err = parseJSONPathSlice(raw)
+		} else {
+			selector, err = parseJSONPathIndexes(raw)
+		}
+		if err != nil {
+			return nil, p.errorf("%s", err)
+		}
+		p.pos += end
+	}
+
+	p.skipSpaces()
+	if !p.consume("]") {
+		return nil, p.errorf("']' expected")
+	}
+	return selector, nil
+}
+
+func parseJSONPathIndexes(raw string) (jsonPathIndexes, error) {
+	var indexes jsonPathIndexes
+	for _, part := range strings.Split(raw, ",") {
+		index, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid index: %s", part)
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
+
+func parseJSONPathSlice(raw string) (jsonPathSlice, error) {
+	parts := strings.Split(raw, ":")
+	if len(parts) > 3 {
+		return jsonPathSlice{}, fmt.Errorf("invalid slice: %s", raw)
+	}
+	bounds := make([]*int, 3)
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		v, err := strconv.Atoi(part)
+		if err != nil {
+			return jsonPathSlice{}, fmt.Errorf("invalid slice: %s", raw)
+		}
+		bounds[i] = &v
+	}
+	return jsonPathSlice{start: bounds[0], end: bounds[1], step: bounds[2]}, nil
+}
+
+func (p *jsonPathParser) parseOr() (jsonPathFilter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		if !p.consume("||") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = jsonPathOr{left: left, right: right}
+	}
+}
+
+func (p *jsonPathParser) parseAnd() (jsonPathFilter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		if !p.consume("&&") {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = jsonPathAnd{left: left, right: right}
+	}
+}
+
+func (p *jsonPathParser) parseUnary() (jsonPathFilter, error) {
+	p.skipSpaces()
+	if p.consume("!") {
+		filter, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return jsonPathNot{filter: filter}, nil
+	}
+	if p.consume("(") {
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if !p.consume(")") {
+			return nil, p.errorf("')' expected")
+		}
+		return filter, nil
+	}
+	return p.parseComparison()
+}
+
+var jsonPathOperators = []string{"==", "!=", "<=", ">=", "=~", "<", ">"}
+
+func (p *jsonPathParser) parseComparison() (jsonPathFilter, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	op := ""
+	for _, candidate := range jsonPathOperators {
+		if p.consume(candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		if !left.isPath {
+			return nil, p.errorf("operator expected")
+		}
+		return jsonPathExists{operand: left}, nil
+	}
+
+	p.skipSpaces()
+	if op == "=~" {
+		regex, err := p.parseRegex()
+		if err != nil {
+			return nil, err
+		}
+		return jsonPathComparison{left: left, op: op, regex: regex}, nil
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return jsonPathComparison{left: left, right: right, op: op}, nil
+}
+
+func (p *jsonPathParser) parseOperand() (jsonPathOperand, error) {
+	p.skipSpaces()
+	if p.eof() {
+		return jsonPathOperand{}, p.errorf("operand expected")
+	}
+
+	switch c := p.s[p.pos]; {
+	case c == '@' || c == '$':
+		p.pos++
+		segments, err := p.parseSegments(true)
+		if err != nil {
+			return jsonPathOperand{}, err
+		}
+		return jsonPathOperand{
+			segments: segments,
+			isPath:   true,
+			fromRoot: c == '$',
+			definite: isDefiniteJSONPath(segments),
+		}, nil
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return jsonPathOperand{}, err
+		}
+		return jsonPathOperand{value: s}, nil
+	case p.consume("true"):
+		return jsonPathOperand{value: true}, nil
+	case p.consume("false"):
+		return jsonPathOperand{value: false}, nil
+	case p.consume("null"):
+		return jsonPathOperand{value: nil}, nil
+	}
+
+	start := p.pos
+	for !p.eof() && strings.IndexByte("+-.0123456789eE", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	number, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+	if err != nil {
+		p.pos = start
+		return jsonPathOperand{}, p.errorf("operand expected")
+	}
+	return jsonPathOperand{value: number}, nil
+}
+
+func (p *jsonPathParser) parseString() (string, error) {
+	quote := p.s[p.pos]
+	p.pos++
+	var sb strings.Builder
+	for !p.eof() {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case quote:
+			return sb.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", p.errorf("unclosed string")
+			}
+			sb.WriteByte(p.s[p.pos])
+			p.pos++
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unclosed string")
+}
+
+// parseRegex parses /pattern/flags (the flags are the regexp flags, e.g. i) or the string pattern.
+func (p *jsonPathParser) parseRegex() (*regexp.Regexp, error) {
+	if p.eof() {
+		return nil, p.errorf("regex expected")
+	}
+
+	var pattern string
+	switch p.s[p.pos] {
+	case '\'', '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		pattern = s
+	case '/':
+		p.pos++
+		var sb strings.Builder
+		for {
+			if p.eof() {
+				return nil, p.errorf("unclosed regex")
+			}
+			c := p.s[p.pos]
+			p.pos++
+			if c == '/' {
+				break
+			}
+			if c == '\\' && !p.eof() && p.s[p.pos] == '/' {
+				c = '/'
+				p.pos++
+			} else if c == '\\' && !p.eof() {
+				sb.WriteByte(c)
+				c = p.s[p.pos]
+				p.pos++
+			}
+			sb.WriteByte(c)
+		}
+		pattern = sb.String()
+		start := p.pos
+		for !p.eof() && unicode.IsLetter(rune(p.s[p.pos])) {
+			p.pos++
+		}
+		if flags := p.s[start:p.pos]; flags != "" {
+			pattern = "(?" + flags + ")" + pattern
+		}
+	default:
+		return nil, p.errorf("regex expected")
+	}
+
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, p.errorf("%s", err)
+	}
+	return regex, nil
+}
@@ -0,0 +1,153 @@
+package expression
+
+import (
+	"errors"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"reflect"
+	"testing"
+)
+
+const jsonPathTestOrder = `{
+	"order": {
+		"id": "A-1",
+		"express": true,
+		"customer": {"name": "Ann", "vip": false},
+		"lines": [
+			{"sku": "A-100", "qty": 1, "price": 10.5},
+			{"sku": "b-200", "qty": 3, "price": 2},
+			{"sku": "A-300", "qty": 5, "price": 7, "gift": true}
+		]
+	}
+}`
+
+type jsonPathTestLine struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+type jsonPathTestOrderStruct struct {
+	ID    string `json:"id"`
+	Lines []jsonPathTestLine
+}
+
+func TestJSONPath_Eval(t *testing.T) {
+	tests := []struct {
+		path     string
+		body     any
+		expected any
+	}{
+		{"$.order.id", jsonPathTestOrder, "A-1"},
+		{"$['order']['customer'].name", jsonPathTestOrder, "Ann"},
+		{"$.order.lines[-1].sku", jsonPathTestOrder, "A-300"},
+		{"$.order.lines[1].qty", []byte(jsonPathTestOrder), float64(3)},
+		{"$.order.lines[*].sku", jsonPathTestOrder, []any{"A-100", "b-200", "A-300"}},
+		{"$.order.lines[0:2].sku", jsonPathTestOrder, []any{"A-100", "b-200"}},
+		{"$.order.lines[::-1].qty", jsonPathTestOrder, []any{float64(5), float64(3), float64(1)}},
+		{"$.order.lines[0,2].sku", jsonPathTestOrder, []any{"A-100", "A-300"}},
+		{"$.order.lines[?(@.qty > 1)].sku", jsonPathTestOrder, []any{"b-200", "A-300"}},
+		{"$.order.lines[?(@.qty > 1 && @.sku =~ /^a-/i)].sku", jsonPathTestOrder, []any{"A-300"}},
+		{"$.order.lines[?(@.gift)].sku", jsonPathTestOrder, []any{"A-300"}},
+		{"$.order.lines[?(!(@.price < 5) || @.sku == 'b-200')].sku", jsonPathTestOrder, []any{"A-100", "b-200", "A-300"}},
+		{"$.order.lines[?(@.qty == $.order.lines[1].qty)].sku", jsonPathTestOrder, []any{"b-200"}},
+		{"$..sku", jsonPathTestOrder, []any{"A-100", "b-200", "A-300"}},
+		{"$.order.lines[?(@.qty > 10)].sku", jsonPathTestOrder, []any{}},
+		{"$.id", map[string]any{"id": 1}, 1},
+		{"$.Lines[?(@.qty >= 2)].sku", &jsonPathTestOrderStruct{ID: "B-1", Lines: []jsonPathTestLine{{"X", 1}, {"Y", 2}}}, []any{"Y"}},
+		{"$.id", jsonPathTestOrderStruct{ID: "B-1"}, "B-1"},
+	}
+
+	for _, tt := range tests {
+		e := exchange.NewExchange(nil)
+		e.Message().Body = tt.body
+
+		result, err := MustJSONPath(tt.path).Eval(e)
+		if err != nil {
+			t.Errorf("TestJSONPath_Eval(%s): unexpected error: %s", tt.path, err)
+			continue
+		}
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("TestJSONPath_Eval(%s) = %#v; want %#v", tt.path, result, tt.expected)
+		}
+	}
+}
+
+func TestJSONPath_Options(t *testing.T) {
+	e := exchange.NewExchange(nil)
+	e.Message().Body = jsonPathTestOrder
+
+	if _, err := MustJSONPath("$.order.missing.id").Eval(e); !errors.Is(err, ErrJSONPathNotFound) {
+		t.Errorf("TestJSONPath_Options(): expected ErrJSONPathNotFound, got %v", err)
+	}
+	if result, err := MustJSONPath("$.order.missing.id").SetSuppressExceptions(true).Eval(e); err != nil || result != nil {
+		t.Errorf("TestJSONPath_Options() = %v, %v; want nil, nil", result, err)
+	}
+
+	single, err := MustJSONPath("$.order.lines[?(@.qty > 1)].sku").SetResult(JSONPathResultSingle).Eval(e)
+	if err != nil || single != "b-200" {
+		t.Errorf("TestJSONPath_Options() = %v, %v; want b-200", single, err)
+	}
+	if _, err := MustJSONPath("$.order.lines[?(@.qty > 10)]").SetResult(JSONPathResultSingle).Eval(e); !errors.Is(err, ErrJSONPathNotFound) {
+		t.Errorf("TestJSONPath_Options(): expected ErrJSONPathNotFound, got %v", err)
+	}
+
+	list, err := MustJSONPath("$.order.id").SetResult(JSONPathResultList).Eval(e)
+	if err != nil || !reflect.DeepEqual(list, []any{"A-1"}) {
+		t.Errorf("TestJSONPath_Options() = %v, %v; want [A-1]", list, err)
+	}
+	list, err = MustJSONPath("$.order.missing").SetResult(JSONPathResultList).SetSuppressExceptions(true).Eval(e)
+	if err != nil || !reflect.DeepEqual(list, []any{}) {
+		t.Errorf("TestJSONPath_Options() = %v, %v; want []", list, err)
+	}
+}
+
+func TestJSONPath_Predicate(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"$.order.express", true},
+		{"$.order.customer.vip", false},
+		{"$.order.id", true},
+		{"$.order.missing", false},
+		{"$.order.lines[?(@.qty > 1)]", true},
+		{"$.order.lines[?(@.qty > 10)]", false},
+	}
+
+	e := exchange.NewExchange(nil)
+	e.Message().Body = jsonPathTestOrder
+
+	for _, tt := range tests {
+		result, err := NewPredicateFromExpression(MustJSONPath(tt.path)).Test(e)
+		if err != nil {
+			t.Errorf("TestJSONPath_Predicate(%s): unexpected error: %s", tt.path, err)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("TestJSONPath_Predicate(%s) = %v; want %v", tt.path, result, tt.expected)
+		}
+	}
+
+	e.Message().Body = "{not json"
+	if _, err := MustJSONPath("$.order").Test(e); err == nil {
+		t.Error("TestJSONPath_Predicate(): expected error for invalid JSON body")
+	}
+}
+
+func TestJSONPath_ParseErrors(t *testing.T) {
+	tests := []string{
+		"order.id",
+		"$.",
+		"$.order[",
+		"$.order[abc]",
+		"$.lines[?(@.qty >)]",
+		"$.lines[?(@.sku =~ /[/)]",
+		"$.lines[?(@.qty > 1]",
+		"$['order",
+	}
+
+	for _, tt := range tests {
+		if _, err := NewJSONPath(tt); err == nil {
+			t.Errorf("TestJSONPath_ParseErrors(%q) = nil; want error", tt)
+		}
+	}
+}
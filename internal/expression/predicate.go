@@ -16,7 +16,12 @@ func (prd PredicateFunc) Test(e *exchange.Exchange) (bool, error) {
 	return prd(e)
 }
 
+// NewPredicateFromExpression converts the result of the expression to bool,
+// the expression which is Predicate itself (e.g. jsonPath) is tested as is.
 func NewPredicateFromExpression(expr Expression) PredicateFunc {
+	if predicate, isPredicate := expr.(Predicate); isPredicate {
+		return predicate.Test
+	}
 	return func(e *exchange.Exchange) (bool, error) {
 		v, err := expr.Eval(e)
 		if err != nil {
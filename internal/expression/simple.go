@@ -1,52 +1,87 @@
 package expression
 
 import (
+	"errors"
 	"fmt"
 	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
 	"github.com/expr-lang/expr/vm"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
 	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
-	"strconv"
+	"sync"
 )
 
-var simpleExpressionEnv map[string]any
-
-func init() {
-	toStringFn := func(i int) string {
-		return strconv.Itoa(i)
-	}
-
-	simpleExpressionEnv = map[string]any{
-		"toString": toStringFn,
-	}
-}
-
 // simple is a wrapper for https://expr-lang.org/docs/getting-started
-// Variables (see Exchange.asMap):
+// Variables (see simpleEnv):
 //
 //	 body:			the Message body
 //	 header:		the Message headers (header.foo refers to the Exchange header 'foo')
 //	 error:			the Exchange error
+//	 exception:		the Exchange error or the caught one (see exchange.CamelPropertyExceptionCaught),
+//					errs.StepError if the error is wrapped into it (exception.step, exception.stack,...)
+//	 exceptionChain:	the exception followed by the errors it wraps (see errs.Chain)
+//	 errorHistory:	the errors caught before (see Exchange.ErrorHistory)
 //	 property:		the Exchange properties (property.foo refers to the Exchange property 'foo')
 //	 id:				the Message id
 //		exchangeId:		the Exchange id
+//
+// Functions: see SimpleContext.
+//
+// The expression is compiled against the typed environment, thus unknown variables and functions
+// as well as the type errors (e.g. wrong function arguments) are reported at compile time.
 type simple struct {
 	raw     string
 	program *vm.Program
+	ctx     *SimpleContext
+	// usesException is TRUE if the expression refers to exception or exceptionChain
+	usesException bool
+	// usesExceptionChain is TRUE if the expression refers to exceptionChain
+	usesExceptionChain bool
 }
 
+// simpleVMs are reused by the evaluations, the VM resets its state on every run.
+var simpleVMs = sync.Pool{New: func() any { return &vm.VM{} }}
+
 func NewSimple(e string) (*simple, error) {
-	program, err := expr.Compile(e,
-		expr.AllowUndefinedVariables(),
+	return NewSimpleWithContext(e, defaultSimpleContext)
+}
+
+// NewSimpleWithContext creates the simple expression that calls the functions of the given context
+// (nil - the context without env and converter). The functions registered after the expression is created
+// are not available to it.
+func NewSimpleWithContext(e string, ctx *SimpleContext) (*simple, error) {
+	if ctx == nil {
+		ctx = defaultSimpleContext
+	}
+
+	options := append([]expr.Option{
+		expr.Env(simpleEnv{}),
 		expr.Optimize(true),
-		expr.AsAny())
+		expr.AsAny(),
+	}, ctx.functionOptions()...)
+	program, err := expr.Compile(e, options...)
 	if err != nil {
 		return nil, err
 	}
 
-	return &simple{
+	s := &simple{
 		raw:     e,
 		program: program,
-	}, nil
+		ctx:     ctx,
+	}
+	ast.Find(program.Node(), func(node ast.Node) bool {
+		if ident, isIdent := node.(*ast.IdentifierNode); isIdent {
+			switch ident.Value {
+			case "exception":
+				s.usesException = true
+			case "exceptionChain":
+				s.usesException = true
+				s.usesExceptionChain = true
+			}
+		}
+		return false
+	})
+	return s, nil
 }
 
 func MustSimple(e string) *simple {
@@ -59,11 +94,30 @@ func MustSimple(e string) *simple {
 }
 
 func (e *simple) Eval(ex *exchange.Exchange) (any, error) {
-	env := ex.AsMap()
-	for k, v := range simpleExpressionEnv {
-		env[k] = v
+	env := e.ctx.acquireEnv(ex)
+	defer e.ctx.releaseEnv(env)
+
+	if e.usesException {
+		exception := ex.Error()
+		if exception == nil {
+			caught, _ := ex.Property(exchange.CamelPropertyExceptionCaught)
+			exception, _ = caught.(error)
+		}
+		var stepErr *errs.StepError
+		if errors.As(exception, &stepErr) {
+			exception = stepErr
+		}
+		if exception != nil {
+			env.Exception = exception
+		}
+		if e.usesExceptionChain {
+			env.ExceptionChain = errs.Chain(exception)
+		}
 	}
-	return expr.Run(e.program, env)
+
+	v := simpleVMs.Get().(*vm.VM)
+	defer simpleVMs.Put(v)
+	return v.Run(e.program, env)
 }
 
 func (e *simple) String() string {
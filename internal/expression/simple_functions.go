@@ -0,0 +1,482 @@
+package expression
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/builtin"
+	"github.com/google/uuid"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"math/rand/v2"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Converter converts the values to the named types (see camel.ConverterRegistry).
+type Converter interface {
+	Type(name string) (reflect.Type, bool)
+	Convert(value any, toType reflect.Type, params map[string]any) (any, error)
+}
+
+// SimpleContext provides the simple expressions with the runtime services and the registered functions.
+//
+// Functions:
+//
+//	uuid()							- random UUID string
+//	formatDate(t, layout)			- formats time.Time (e.g. now()) by means of the Go layout
+//	parseDate(value, layout)		- parses time.Time by means of the Go layout
+//	env(name[, default])			- the variable of the runtime env
+//	random([min,] max)				- random int in [min, max), random() returns non-negative int
+//	base64Encode(v), base64Decode(s)
+//	hexEncode(v), hexDecode(s)
+//	urlEncode(s), urlDecode(s)		- query escaping
+//	sha256(v)						- hex encoded SHA-256 digest
+//	hmacSha256(v, key)				- hex encoded HMAC SHA-256
+//	lookup(value, path)				- the value by path, e.g. lookup(body, 'items[0].price') (JSON strings are decoded)
+//	bodyPath(path)					- lookup(body, path)
+//	bodyAs(typeName)				- the body converted to the named type (see camel.ConverterRegistry)
+//	headerOr(name, default)			- the header or the default if the header is absent
+//	exchangeProperty(name[, default])	- the Exchange property
+//	regexMatch(s, pattern)			- TRUE if the pattern matches s
+//	regexReplace(s, pattern, repl)	- replaces the matches of the pattern (see regexp.ReplaceAllString)
+//
+// The expr-lang builtins (now(), date(), upper(), toJSON(),...) are available as well.
+type SimpleContext struct {
+	env       api.Env
+	converter Converter
+	// envs are the environments reused by the evaluations
+	envs sync.Pool
+
+	mu        sync.RWMutex
+	functions map[string]any
+}
+
+// defaultSimpleContext is used by the simple expressions created by NewSimple.
+var defaultSimpleContext = NewSimpleContext(nil, nil)
+
+// NewSimpleContext creates the context, env and converter are optional.
+func NewSimpleContext(env api.Env, converter Converter) *SimpleContext {
+	c := &SimpleContext{
+		env:       env,
+		converter: converter,
+		functions: map[string]any{},
+	}
+	c.envs.New = func() any {
+		return newSimpleEnv(c)
+	}
+	return c
+}
+
+// RegisterFunction registers the function available to the simple expressions by name,
+// the function may return an error as the last result. The arguments are type checked at compile time,
+// thus the function must be registered before the expressions calling it are created.
+func (c *SimpleContext) RegisterFunction(name string, fn any) error {
+	if name == "" {
+		return errors.New("expression function name must be not empty string")
+	}
+	if fn == nil || reflect.TypeOf(fn).Kind() != reflect.Func {
+		return fmt.Errorf("expression function '%s': expected func, but got %T", name, fn)
+	}
+	if isReservedSimpleName(name) {
+		return fmt.Errorf("expression function '%s': name is reserved", name)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.functions[name]; exists {
+		return fmt.Errorf("expression function '%s' already registered", name)
+	}
+	c.functions[name] = fn
+	return nil
+}
+
+// functionOptions returns the registered functions as the compiler options.
+func (c *SimpleContext) functionOptions() []expr.Option {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	options := make([]expr.Option, 0, len(c.functions))
+	for name, fn := range c.functions {
+		options = append(options, expr.Function(name, callFunction(fn), fn))
+	}
+	return options
+}
+
+// callFunction adapts the registered function to the expr-lang function, the arguments are already type checked.
+func callFunction(fn any) func(params ...any) (any, error) {
+	fv := reflect.ValueOf(fn)
+	ft := fv.Type()
+	returnsError := ft.NumOut() > 0 && ft.Out(ft.NumOut()-1) == reflect.TypeFor[error]()
+
+	return func(params ...any) (any, error) {
+		in := make([]reflect.Value, len(params))
+		for i, param := range params {
+			paramType := ft.In(min(i, ft.NumIn()-1))
+			if ft.IsVariadic() && i >= ft.NumIn()-1 {
+				paramType = paramType.Elem()
+			}
+			if param == nil {
+				in[i] = reflect.Zero(paramType)
+			} else {
+				in[i] = reflect.ValueOf(param)
+			}
+		}
+
+		out := fv.Call(in)
+		if returnsError {
+			if err, _ := out[len(out)-1].Interface().(error); err != nil {
+				return nil, err
+			}
+			out = out[:len(out)-1]
+		}
+		if len(out) == 0 {
+			return nil, nil
+		}
+		return out[0].Interface(), nil
+	}
+}
+
+// acquireEnv returns the environment populated from the exchange.
+func (c *SimpleContext) acquireEnv(ex *exchange.Exchange) *simpleEnv {
+	env := c.envs.Get().(*simpleEnv)
+	env.ex = ex
+	env.Id = ex.Message().Id()
+	env.ExchangeId = ex.Id()
+	env.Body = ex.Message().Body
+	env.Header = ex.Message().Headers().All()
+	env.Error = ex.Error()
+	env.Property = ex.Properties().All()
+	env.ErrorHistory = ex.ErrorHistory()
+	return env
+}
+
+// releaseEnv clears the references to the exchange and returns the environment to the pool.
+func (c *SimpleContext) releaseEnv(env *simpleEnv) {
+	env.ex = nil
+	env.Body = nil
+	env.Header = nil
+	env.Error = nil
+	env.Property = nil
+	env.Exception = nil
+	env.ExceptionChain = nil
+	env.ErrorHistory = nil
+	c.envs.Put(env)
+}
+
+// simpleEnv is the typed environment the simple expressions are compiled against,
+// it is the reusable view of the evaluated exchange.
+type simpleEnv struct {
+	Id             string         `expr:"id"`
+	ExchangeId     string         `expr:"exchangeId"`
+	Body           any            `expr:"body"`
+	Header         map[string]any `expr:"header"`
+	Error          error          `expr:"error"`
+	Property       map[string]any `expr:"property"`
+	Exception      any            `expr:"exception"`
+	ExceptionChain []error        `expr:"exceptionChain"`
+	ErrorHistory   []error        `expr:"errorHistory"`
+
+	ToString     func(i int) string                               `expr:"toString"`
+	UUID         func() string                                    `expr:"uuid"`
+	FormatDate   func(t time.Time, layout string) string          `expr:"formatDate"`
+	ParseDate    func(value, layout string) (time.Time, error)    `expr:"parseDate"`
+	Random       func(bounds ...int) (int, error)                 `expr:"random"`
+	Base64Encode func(v any) string                               `expr:"base64Encode"`
+	Base64Decode func(s string) (string, error)                   `expr:"base64Decode"`
+	HexEncode    func(v any) string                               `expr:"hexEncode"`
+	HexDecode    func(s string) (string, error)                   `expr:"hexDecode"`
+	URLEncode    func(s string) string                            `expr:"urlEncode"`
+	URLDecode    func(s string) (string, error)                   `expr:"urlDecode"`
+	SHA256       func(v any) string                               `expr:"sha256"`
+	HMACSHA256   func(v any, key string) string                   `expr:"hmacSha256"`
+	Lookup       func(value any, path string) (any, error)        `expr:"lookup"`
+	RegexMatch   func(s, pattern string) (bool, error)            `expr:"regexMatch"`
+	RegexReplace func(s, pattern, repl string) (string, error)    `expr:"regexReplace"`
+	Env          func(name string, defaultValue ...string) string `expr:"env"`
+	BodyPath     func(path string) (any, error)                   `expr:"bodyPath"`
+	BodyAs       func(typeName string) (any, error)               `expr:"bodyAs"`
+	HeaderOr     func(name string, defaultValue any) any          `expr:"headerOr"`
+	ExchangeProp func(name string, defaultValue ...any) any       `expr:"exchangeProperty"`
+
+	ex *exchange.Exchange
+}
+
+// newSimpleEnv creates the environment, the exchange bound functions refer to the exchange being evaluated.
+func newSimpleEnv(c *SimpleContext) *simpleEnv {
+	env := &simpleEnv{
+		ToString:     strconv.Itoa,
+		UUID:         uuid.NewString,
+		FormatDate:   func(t time.Time, layout string) string { return t.Format(layout) },
+		ParseDate:    func(value, layout string) (time.Time, error) { return time.Parse(layout, value) },
+		Random:       random,
+		Base64Encode: func(v any) string { return base64.StdEncoding.EncodeToString(bytesOf(v)) },
+		Base64Decode: func(s string) (string, error) {
+			data, err := base64.StdEncoding.DecodeString(s)
+			return string(data), err
+		},
+		HexEncode: func(v any) string { return hex.EncodeToString(bytesOf(v)) },
+		HexDecode: func(s string) (string, error) {
+			data, err := hex.DecodeString(s)
+			return string(data), err
+		},
+		URLEncode: url.QueryEscape,
+		URLDecode: url.QueryUnescape,
+		SHA256: func(v any) string {
+			sum := sha256.Sum256(bytesOf(v))
+			return hex.EncodeToString(sum[:])
+		},
+		HMACSHA256: func(v any, key string) string {
+			mac := hmac.New(sha256.New, []byte(key))
+			mac.Write(bytesOf(v))
+			return hex.EncodeToString(mac.Sum(nil))
+		},
+		Lookup: lookup,
+		RegexMatch: func(s, pattern string) (bool, error) {
+			re, err := compileRegex(pattern)
+			if err != nil {
+				return false, err
+			}
+			return re.MatchString(s), nil
+		},
+		RegexReplace: func(s, pattern, repl string) (string, error) {
+			re, err := compileRegex(pattern)
+			if err != nil {
+				return "", err
+			}
+			return re.ReplaceAllString(s, repl), nil
+		},
+	}
+
+	env.Env = func(name string, defaultValue ...string) string {
+		if c.env != nil {
+			if value, exists := c.env.LookupVar(name); exists {
+				return value
+			}
+		}
+		if len(defaultValue) > 0 {
+			return defaultValue[0]
+		}
+		return ""
+	}
+	env.BodyPath = func(path string) (any, error) {
+		return lookup(env.Body, path)
+	}
+	env.BodyAs = func(typeName string) (any, error) {
+		if c.converter == nil {
+			return nil, fmt.Errorf("bodyAs: no converter to convert body to '%s'", typeName)
+		}
+		targetType, exists := c.converter.Type(typeName)
+		if !exists {
+			return nil, fmt.Errorf("bodyAs: unknown type: %s", typeName)
+		}
+		return c.converter.Convert(env.Body, targetType, nil)
+	}
+	env.HeaderOr = func(name string, defaultValue any) any {
+		if value, exists := env.Header[name]; exists {
+			return value
+		}
+		return defaultValue
+	}
+	env.ExchangeProp = func(name string, defaultValue ...any) any {
+		if value, exists := env.Property[name]; exists {
+			return value
+		}
+		if len(defaultValue) > 0 {
+			return defaultValue[0]
+		}
+		return nil
+	}
+	return env
+}
+
+// simpleEnvNames are the variables and functions of simpleEnv.
+var simpleEnvNames = func() map[string]bool {
+	names := map[string]bool{}
+	t := reflect.TypeFor[simpleEnv]()
+	for i := 0; i < t.NumField(); i++ {
+		if name := t.Field(i).Tag.Get("expr"); name != "" {
+			names[name] = true
+		}
+	}
+	return names
+}()
+
+func isReservedSimpleName(name string) bool {
+	if _, isBuiltin := builtin.Index[name]; isBuiltin {
+		return true
+	}
+	return simpleEnvNames[name]
+}
+
+func random(bounds ...int) (int, error) {
+	switch len(bounds) {
+	case 0:
+		return rand.Int(), nil
+	case 1:
+		if bounds[0] <= 0 {
+			return 0, fmt.Errorf("random: max must be positive, got %d", bounds[0])
+		}
+		return rand.IntN(bounds[0]), nil
+	case 2:
+		if bounds[1] <= bounds[0] {
+			return 0, fmt.Errorf("random: max must be greater than min, got [%d, %d)", bounds[0], bounds[1])
+		}
+		return bounds[0] + rand.IntN(bounds[1]-bounds[0]), nil
+	}
+	return 0, fmt.Errorf("random: expected at most 2 arguments, got %d", len(bounds))
+}
+
+func bytesOf(v any) []byte {
+	switch x := v.(type) {
+	case []byte:
+		return x
+	case string:
+		return []byte(x)
+	case nil:
+		return nil
+	}
+	return []byte(fmt.Sprint(v))
+}
+
+// regexCacheSize bounds regexCache, the cache is reset once it is full (e.g. the patterns are built from the messages).
+const regexCacheSize = 256
+
+// regexCache keeps the compiled patterns of regexMatch and regexReplace.
+var regexCache = struct {
+	sync.RWMutex
+	patterns map[string]*regexp.Regexp
+}{patterns: make(map[string]*regexp.Regexp)}
+
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	regexCache.RLock()
+	re, cached := regexCache.patterns[pattern]
+	regexCache.RUnlock()
+	if cached {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	regexCache.Lock()
+	if len(regexCache.patterns) >= regexCacheSize {
+		regexCache.patterns = make(map[string]*regexp.Regexp)
+	}
+	regexCache.patterns[pattern] = re
+	regexCache.Unlock()
+	return re, nil
+}
+
+// lookup returns the value by path of keys (map keys or struct fields) and indexes, e.g. 'items[0].price'
+// (a negative index counts from the end). A JSON string or []byte is decoded first.
+// A missing key or index yields nil.
+func lookup(value any, path string) (any, error) {
+	switch x := value.(type) {
+	case []byte:
+		if err := json.Unmarshal(x, &value); err != nil {
+			return nil, fmt.Errorf("lookup: %w", err)
+		}
+	case string:
+		if trimmed := strings.TrimSpace(x); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			if err := json.Unmarshal([]byte(trimmed), &value); err != nil {
+				return nil, fmt.Errorf("lookup: %w", err)
+			}
+		}
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		key, rest, _ := strings.Cut(segment, "[")
+		if key != "" {
+			var err error
+			if value, err = lookupKey(value, key); err != nil {
+				return nil, fmt.Errorf("lookup '%s': %w", path, err)
+			}
+		}
+		for rest != "" {
+			indexStr, next, closed := strings.Cut(rest, "]")
+			index, err := strconv.Atoi(indexStr)
+			if !closed || err != nil {
+				return nil, fmt.Errorf("lookup '%s': invalid index: [%s", path, rest)
+			}
+			if value, err = lookupIndex(value, index); err != nil {
+				return nil, fmt.Errorf("lookup '%s': %w", path, err)
+			}
+			rest = strings.TrimPrefix(next, "[")
+		}
+		if value == nil {
+			return nil, nil
+		}
+	}
+	return value, nil
+}
+
+func lookupKey(value any, key string) (any, error) {
+	if m, isMap := value.(map[string]any); isMap {
+		return m[key], nil
+	}
+
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("key '%s': map key must be string, got %s", key, rv.Type().Key())
+		}
+		v := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()))
+		if !v.IsValid() {
+			return nil, nil
+		}
+		return v.Interface(), nil
+	case reflect.Struct:
+		f := rv.FieldByName(key)
+		if !f.IsValid() || !f.CanInterface() {
+			return nil, nil
+		}
+		return f.Interface(), nil
+	case reflect.Invalid:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("key '%s': expected map or struct, got %T", key, value)
+}
+
+func lookupIndex(value any, index int) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("index [%d]: expected slice, got %T", index, value)
+	}
+	if index < 0 {
+		index += rv.Len()
+	}
+	if index < 0 || index >= rv.Len() {
+		return nil, nil
+	}
+	return rv.Index(index).Interface(), nil
+}
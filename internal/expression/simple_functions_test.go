@@ -0,0 +1,132 @@
+package expression
+
+import (
+	"github.com/paveldanilin/go-camel/pkg/camel/env"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSimple_Functions(t *testing.T) {
+	ctx := NewSimpleContext(env.NewMapEnv(map[string]string{"REGION": "eu"}), nil)
+	if err := ctx.RegisterFunction("discount", func(total float64) float64 { return total * 0.9 }); err != nil {
+		t.Fatalf("TestSimple_Functions(): failed to register function: %s", err)
+	}
+
+	tests := []struct {
+		expression string
+		want       any
+	}{
+		{expression: "len(uuid())", want: 36},
+		{expression: "formatDate(parseDate('2024-03-01', '2006-01-02'), '02.01.2006')", want: "01.03.2024"},
+		{expression: "env('REGION')", want: "eu"},
+		{expression: "env('ZONE', 'a')", want: "a"},
+		{expression: "random(5, 6)", want: 5},
+		{expression: "base64Decode(base64Encode('go'))", want: "go"},
+		{expression: "hexEncode('go')", want: "676f"},
+		{expression: "hexDecode('676f')", want: "go"},
+		{expression: "urlEncode('a b&c')", want: "a+b%26c"},
+		{expression: "urlDecode('a+b%26c')", want: "a b&c"},
+		{expression: "sha256('abc')", want: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{expression: "len(hmacSha256('abc', 'key'))", want: 64},
+		{expression: "bodyPath('items[1].price')", want: 20.0},
+		{expression: "bodyPath('items[-1].name')", want: "b"},
+		{expression: "bodyPath('missing.key')", want: nil},
+		{expression: "lookup(header.meta, 'tags[0]')", want: "new"},
+		{expression: "headerOr('absent', 'default')", want: "default"},
+		{expression: "headerOr('source', 'default')", want: "web"},
+		{expression: "exchangeProperty('tenant')", want: "acme"},
+		{expression: "exchangeProperty('absent', 1)", want: 1},
+		{expression: "regexMatch(header.source, '^w')", want: true},
+		{expression: "regexReplace('a1b22', '[0-9]+', '#')", want: "a#b#"},
+		{expression: "discount(100.0)", want: 90.0},
+		{expression: "toString(1)", want: "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			se, err := NewSimpleWithContext(tt.expression, ctx)
+			if err != nil {
+				t.Fatalf("TestSimple_Functions(): failed to compile: %s", err)
+			}
+			e := exchange.NewExchange(nil)
+			e.Message().Body = `{"items": [{"name": "a", "price": 10}, {"name": "b", "price": 20}]}`
+			e.Message().SetHeader("source", "web")
+			e.Message().SetHeader("meta", map[string]any{"tags": []string{"new"}})
+			e.SetProperty("tenant", "acme")
+
+			got, err := se.Eval(e)
+			if err != nil {
+				t.Fatalf("TestSimple_Functions(): unexpected error: %s", err)
+			}
+			if got != tt.want {
+				t.Fatalf("TestSimple_Functions() = %v (%T); want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimple_Functions_Errors(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    string
+	}{
+		{expression: "base64Decode('%')", wantErr: "illegal base64"},
+		{expression: "regexMatch('a', '[')", wantErr: "missing closing ]"},
+		{expression: "bodyAs('int')", wantErr: "no converter"},
+		{expression: "parseDate('now', '2006')", wantErr: "cannot parse"},
+		{expression: "random(0)", wantErr: "max must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			se := MustSimple(tt.expression)
+
+			_, err := se.Eval(exchange.NewExchange(nil))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("TestSimple_Functions_Errors() = %v; want error containing '%s'", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSimple_RegexCache(t *testing.T) {
+	for i := 0; i < regexCacheSize*2; i++ {
+		if _, err := compileRegex("^order-" + strconv.Itoa(i) + "$"); err != nil {
+			t.Fatalf("TestSimple_RegexCache(): unexpected error: %s", err)
+		}
+	}
+
+	regexCache.RLock()
+	defer regexCache.RUnlock()
+	if len(regexCache.patterns) > regexCacheSize {
+		t.Fatalf("TestSimple_RegexCache(): expected at most %d cached patterns, got %d", regexCacheSize, len(regexCache.patterns))
+	}
+}
+
+func TestSimpleContext_RegisterFunction(t *testing.T) {
+	ctx := NewSimpleContext(nil, nil)
+
+	tests := []struct {
+		name string
+		fn   any
+	}{
+		{name: "", fn: func() {}},
+		{name: "notFunc", fn: 1},
+		{name: "now", fn: func() time.Time { return time.Time{} }},
+		{name: "sha256", fn: func() {}},
+		{name: "body", fn: func() {}},
+	}
+	for _, tt := range tests {
+		if err := ctx.RegisterFunction(tt.name, tt.fn); err == nil {
+			t.Fatalf("TestSimpleContext_RegisterFunction(%s): expected error", tt.name)
+		}
+	}
+
+	if err := ctx.RegisterFunction("answer", func() int { return 42 }); err != nil {
+		t.Fatalf("TestSimpleContext_RegisterFunction(): unexpected error: %s", err)
+	}
+	if err := ctx.RegisterFunction("answer", func() int { return 42 }); err == nil {
+		t.Fatalf("TestSimpleContext_RegisterFunction(): expected duplicate error")
+	}
+}
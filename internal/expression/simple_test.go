@@ -23,3 +23,56 @@ func TestSimple_Eval(t *testing.T) {
 		t.Error("TestSimple_Eval() = FALSE; want TRUE")
 	}
 }
+
+func TestSimple_CompileErrors(t *testing.T) {
+	tests := []string{
+		"unknown == 1",
+		"headers.a == 1",
+		"uuid(1)",
+		"formatDate('2024-01-01', 'yyyy')",
+		"exchangeId + 1",
+	}
+
+	for _, tt := range tests {
+		if _, err := NewSimple(tt); err == nil {
+			t.Errorf("TestSimple_CompileErrors(%q) = nil; want error", tt)
+		}
+	}
+}
+
+func TestSimple_EvalReusesEnv(t *testing.T) {
+	e := MustSimple("header.a")
+
+	for i := 0; i < 3; i++ {
+		m := exchange.NewExchange(nil)
+		if i != 1 {
+			m.Message().SetHeader("a", i)
+		}
+
+		ret, err := e.Eval(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 1 && ret != nil {
+			t.Errorf("TestSimple_EvalReusesEnv() = %v; want nil", ret)
+		}
+		if i != 1 && ret != i {
+			t.Errorf("TestSimple_EvalReusesEnv() = %v; want %d", ret, i)
+		}
+	}
+}
+
+func BenchmarkSimple_Eval(b *testing.B) {
+	e := MustSimple("header.total > 100 && property.tenant == 'acme'")
+
+	m := exchange.NewExchange(nil)
+	m.Message().SetHeader("total", 150)
+	m.SetProperty("tenant", "acme")
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := e.Eval(m); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
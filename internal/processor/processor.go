@@ -1,9 +1,12 @@
 package processor
 
 import (
-	"fmt"
 	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
 	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
 )
 
 // Invoke invokes processor with a panic recovery.
@@ -12,7 +15,7 @@ import (
 func Invoke(p api.Processor, e *exchange.Exchange) (panicked bool) {
 	defer func() {
 		if r := recover(); r != nil {
-			e.SetError(fmt.Errorf("%v", r))
+			e.SetError(&errs.PanicError{Value: r, Stack: debug.Stack()})
 			panicked = true
 		}
 	}()
@@ -20,3 +23,30 @@ func Invoke(p api.Processor, e *exchange.Exchange) (panicked bool) {
 	p.Process(e)
 	return false
 }
+
+// InvokeAsync invokes processor asynchronously with a panic recovery, done is called exactly once.
+// A panic raised before completion is recorded as the exchange error and completes the processing.
+// A panic raised after completion (e.g. by the continuation running inside the done callback)
+// does not belong to the processor and is propagated.
+func InvokeAsync(p api.Processor, e *exchange.Exchange, done func()) {
+	var once sync.Once
+	var completed atomic.Bool
+	complete := func() {
+		once.Do(func() {
+			completed.Store(true)
+			done()
+		})
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if completed.Load() {
+				panic(r)
+			}
+			e.SetError(&errs.PanicError{Value: r, Stack: debug.Stack()})
+			complete()
+		}
+	}()
+
+	api.ToAsync(p).ProcessAsync(e, complete)
+}
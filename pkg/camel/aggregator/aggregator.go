@@ -0,0 +1,121 @@
+// Package aggregator provides ready-made api.ExchangeAggregator strategies (multicast aggregation).
+//
+// Aggregators are called sequentially, thus they do not need to be safe for concurrent use.
+// An aggregation problem (e.g. a body of unexpected type) is set as the error of the aggregated exchange.
+package aggregator
+
+import (
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+)
+
+// FuncAggregator adapts a function to api.ExchangeAggregator.
+type FuncAggregator func(oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange
+
+func (f FuncAggregator) AggregateExchange(oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	return f(oldExchange, newExchange)
+}
+
+// IndexedFuncAggregator adapts a function that receives the aggregation index to api.IndexedExchangeAggregator.
+type IndexedFuncAggregator func(index int, oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange
+
+func (f IndexedFuncAggregator) AggregateExchange(oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	return f(0, oldExchange, newExchange)
+}
+
+func (f IndexedFuncAggregator) AggregateExchangeAt(index int, oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	return f(index, oldExchange, newExchange)
+}
+
+// completeWhen decorates the aggregator with the completion predicate.
+type completeWhen struct {
+	aggregator api.ExchangeAggregator
+	predicate  func(aggregated *exchange.Exchange) bool
+}
+
+// CompleteWhen returns the aggregator that signals completion once the predicate is true for the aggregated exchange.
+func CompleteWhen(aggregator api.ExchangeAggregator, predicate func(aggregated *exchange.Exchange) bool) api.CompletionAwareAggregator {
+	return &completeWhen{aggregator: aggregator, predicate: predicate}
+}
+
+// CompleteAfter returns the aggregator that signals completion after n exchanges are aggregated.
+// The number is kept in exchange.CamelPropertyAggregatedSize of the aggregated exchange,
+// thus the aggregator can be shared by the concurrent aggregations.
+func CompleteAfter(aggregator api.ExchangeAggregator, n int) api.CompletionAwareAggregator {
+	return CompleteWhen(&aggregatedSize{aggregator: aggregator}, func(aggregated *exchange.Exchange) bool {
+		size, _ := aggregated.Property(exchange.CamelPropertyAggregatedSize)
+		return size.(int) >= n
+	})
+}
+
+// aggregatedSize counts the exchanges aggregated into the aggregated exchange.
+type aggregatedSize struct {
+	aggregator api.ExchangeAggregator
+}
+
+func (a *aggregatedSize) AggregateExchange(oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	size := aggregatedSizeOf(oldExchange)
+	return setAggregatedSize(a.aggregator.AggregateExchange(oldExchange, newExchange), size+1)
+}
+
+func (a *aggregatedSize) AggregateExchangeAt(index int, oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	size := aggregatedSizeOf(oldExchange)
+	if indexed, isIndexed := a.aggregator.(api.IndexedExchangeAggregator); isIndexed {
+		return setAggregatedSize(indexed.AggregateExchangeAt(index, oldExchange, newExchange), size+1)
+	}
+	return setAggregatedSize(a.aggregator.AggregateExchange(oldExchange, newExchange), size+1)
+}
+
+func (a *aggregatedSize) IsComplete(aggregated *exchange.Exchange) bool {
+	completionAware, isCompletionAware := a.aggregator.(api.CompletionAwareAggregator)
+	return isCompletionAware && completionAware.IsComplete(aggregated)
+}
+
+// aggregatedSizeOf returns the number of the aggregated exchanges (zero - the aggregation has not started yet).
+func aggregatedSizeOf(aggregated *exchange.Exchange) int {
+	if aggregated == nil {
+		return 0
+	}
+	size, _ := aggregated.Property(exchange.CamelPropertyAggregatedSize)
+	n, _ := size.(int)
+	return n
+}
+
+func setAggregatedSize(aggregated *exchange.Exchange, size int) *exchange.Exchange {
+	aggregated.SetProperty(exchange.CamelPropertyAggregatedSize, size)
+	return aggregated
+}
+
+func (a *completeWhen) AggregateExchange(oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	return a.aggregator.AggregateExchange(oldExchange, newExchange)
+}
+
+func (a *completeWhen) AggregateExchangeAt(index int, oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	if indexed, isIndexed := a.aggregator.(api.IndexedExchangeAggregator); isIndexed {
+		return indexed.AggregateExchangeAt(index, oldExchange, newExchange)
+	}
+	return a.aggregator.AggregateExchange(oldExchange, newExchange)
+}
+
+func (a *completeWhen) IsComplete(aggregated *exchange.Exchange) bool {
+	if completionAware, isCompletionAware := a.aggregator.(api.CompletionAwareAggregator); isCompletionAware && completionAware.IsComplete(aggregated) {
+		return true
+	}
+	return a.predicate(aggregated)
+}
+
+// Aggregate calls AggregateExchangeAt if the aggregator implements api.IndexedExchangeAggregator,
+// otherwise AggregateExchange. Returns TRUE if the aggregator signals completion.
+func Aggregate(aggregator api.ExchangeAggregator, index int, oldExchange *exchange.Exchange, newExchange *exchange.Exchange) (*exchange.Exchange, bool) {
+	var aggregated *exchange.Exchange
+	if indexed, isIndexed := aggregator.(api.IndexedExchangeAggregator); isIndexed {
+		aggregated = indexed.AggregateExchangeAt(index, oldExchange, newExchange)
+	} else {
+		aggregated = aggregator.AggregateExchange(oldExchange, newExchange)
+	}
+
+	if completionAware, isCompletionAware := aggregator.(api.CompletionAwareAggregator); isCompletionAware {
+		return aggregated, completionAware.IsComplete(aggregated)
+	}
+	return aggregated, false
+}
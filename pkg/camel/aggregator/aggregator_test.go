@@ -0,0 +1,153 @@
+package aggregator
+
+import (
+	"bytes"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"reflect"
+	"testing"
+)
+
+func newExchanges(bodies ...any) []*exchange.Exchange {
+	exchanges := make([]*exchange.Exchange, len(bodies))
+	for i, body := range bodies {
+		exchanges[i] = exchange.NewExchange(nil)
+		exchanges[i].Message().Body = body
+	}
+	return exchanges
+}
+
+func aggregateAll(a api.ExchangeAggregator, exchanges ...*exchange.Exchange) *exchange.Exchange {
+	var aggregated *exchange.Exchange
+	for i, e := range exchanges {
+		var complete bool
+		aggregated, complete = Aggregate(a, i, aggregated, e)
+		if complete {
+			break
+		}
+	}
+	return aggregated
+}
+
+func TestStrategies(t *testing.T) {
+	tests := []struct {
+		name       string
+		aggregator api.ExchangeAggregator
+		bodies     []any
+		expected   any
+	}{
+		{"CollectBodies", NewCollectBodies(), []any{1, "a", nil}, []any{1, "a", nil}},
+		{"UseLatest", NewUseLatest(), []any{1, 2, 3}, 3},
+		{"UseOldest", NewUseOldest(), []any{1, 2, 3}, 1},
+		{"StringConcat", NewStringConcat(", "), []any{"a", []byte("b"), 3}, "a, b, 3"},
+		{"BytesConcat", NewBytesConcat([]byte("\n")), []any{[]byte("a"), "b"}, []byte("a\nb")},
+		{"MergeMaps", NewMergeMaps(), []any{map[string]any{"a": 1, "b": 1}, map[string]any{"b": 2}}, map[string]any{"a": 1, "b": 2}},
+		{"SumInt", NewSum(), []any{1, int64(2), uint8(3)}, int64(6)},
+		{"SumFloat", NewSum(), []any{1, 0.5}, 1.5},
+		{"GroupBy", MustGroupBy(expr.Simple("body % 2 == 0 ? 'even' : 'odd'")), []any{1, 2, 3}, map[any][]any{"odd": {1, 3}, "even": {2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggregated := aggregateAll(tt.aggregator, newExchanges(tt.bodies...)...)
+			if aggregated.IsError() {
+				t.Fatalf("TestStrategies(%s): unexpected error: %s", tt.name, aggregated.Error())
+			}
+			if b, isBytes := tt.expected.([]byte); isBytes {
+				if !bytes.Equal(aggregated.Message().Body.([]byte), b) {
+					t.Fatalf("TestStrategies(%s) = %q; want %q", tt.name, aggregated.Message().Body, b)
+				}
+				return
+			}
+			if !reflect.DeepEqual(aggregated.Message().Body, tt.expected) {
+				t.Fatalf("TestStrategies(%s) = %v; want %v", tt.name, aggregated.Message().Body, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStrategies_Error(t *testing.T) {
+	aggregated := aggregateAll(NewSum(), newExchanges(1, "two")...)
+	if !aggregated.IsError() {
+		t.Fatalf("TestStrategies_Error(): expected error for non-numeric body")
+	}
+
+	aggregated = aggregateAll(NewMergeMaps(), newExchanges(map[string]any{}, []int{1})...)
+	if !aggregated.IsError() {
+		t.Fatalf("TestStrategies_Error(): expected error for non-map body")
+	}
+
+	aggregated = aggregateAll(NewMergeMaps(), newExchanges([]int{1}, map[string]any{"a": 1}, map[string]any{"b": 2})...)
+	if !aggregated.IsError() {
+		t.Fatalf("TestStrategies_Error(): expected error for the rejected first body")
+	}
+
+	groupBy := MustGroupBy(expr.Func(func(e *exchange.Exchange) (any, error) {
+		if e.Message().Body == 1 {
+			return []int{}, nil
+		}
+		return "key", nil
+	}))
+	aggregated = aggregateAll(groupBy, newExchanges(1, 2, 3)...)
+	if !aggregated.IsError() {
+		t.Fatalf("TestStrategies_Error(): expected error for the rejected first key")
+	}
+}
+
+func TestMergeHeaders(t *testing.T) {
+	exchanges := newExchanges("first", "second")
+	exchanges[0].Message().SetHeader("a", 1)
+	exchanges[1].Message().SetHeader("b", 2)
+
+	aggregated := aggregateAll(NewMergeHeaders(), exchanges...)
+	if aggregated.Message().Body != "first" {
+		t.Fatalf("TestMergeHeaders() = %v; want body %s", aggregated.Message().Body, "first")
+	}
+	if !aggregated.Message().HasHeader("a") || !aggregated.Message().HasHeader("b") {
+		t.Fatalf("TestMergeHeaders() = %v; want headers a and b", aggregated.Message().Headers().All())
+	}
+}
+
+func TestCompleteAfter(t *testing.T) {
+	indexes := []int{}
+	a := CompleteAfter(IndexedFuncAggregator(func(index int, oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+		indexes = append(indexes, index)
+		return newExchange
+	}), 2)
+
+	aggregated := aggregateAll(a, newExchanges(1, 2, 3)...)
+
+	if aggregated.Message().Body != 2 {
+		t.Fatalf("TestCompleteAfter() = %v; want %d", aggregated.Message().Body, 2)
+	}
+	if !reflect.DeepEqual(indexes, []int{0, 1}) {
+		t.Fatalf("TestCompleteAfter() = aggregated indexes %v; want %v", indexes, []int{0, 1})
+	}
+}
+
+func TestFuncAggregator(t *testing.T) {
+	a := FuncAggregator(func(oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+		if oldExchange == nil {
+			return newExchange
+		}
+		oldExchange.Message().Body = oldExchange.Message().Body.(int) * newExchange.Message().Body.(int)
+		return oldExchange
+	})
+
+	aggregated := aggregateAll(a, newExchanges(2, 3, 4)...)
+	if aggregated.Message().Body != 24 {
+		t.Fatalf("TestFuncAggregator() = %v; want %d", aggregated.Message().Body, 24)
+	}
+}
+
+func TestCompleteAfter_Reuse(t *testing.T) {
+	a := CompleteAfter(NewCollectBodies(), 2)
+
+	for run := 0; run < 2; run++ {
+		aggregated := aggregateAll(a, newExchanges(1, 2, 3)...)
+		if !reflect.DeepEqual(aggregated.Message().Body, []any{1, 2}) {
+			t.Fatalf("TestCompleteAfter_Reuse() = %v; want %v (run %d)", aggregated.Message().Body, []any{1, 2}, run)
+		}
+	}
+}
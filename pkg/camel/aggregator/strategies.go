@@ -0,0 +1,321 @@
+package aggregator
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/paveldanilin/go-camel/internal/expression"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"reflect"
+	"strings"
+)
+
+// CollectBodies collects bodies into []any in the aggregation order.
+type CollectBodies struct{}
+
+func NewCollectBodies() *CollectBodies {
+	return &CollectBodies{}
+}
+
+func (a *CollectBodies) AggregateExchange(oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	if oldExchange == nil {
+		newExchange.Message().Body = []any{newExchange.Message().Body}
+		return newExchange
+	}
+	bodies, _ := oldExchange.Message().Body.([]any)
+	oldExchange.Message().Body = append(bodies, newExchange.Message().Body)
+	return oldExchange
+}
+
+// UseLatest keeps the latest exchange.
+type UseLatest struct{}
+
+func NewUseLatest() *UseLatest {
+	return &UseLatest{}
+}
+
+func (a *UseLatest) AggregateExchange(_ *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	return newExchange
+}
+
+// UseOldest keeps the first aggregated exchange.
+type UseOldest struct{}
+
+func NewUseOldest() *UseOldest {
+	return &UseOldest{}
+}
+
+func (a *UseOldest) AggregateExchange(oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	if oldExchange == nil {
+		return newExchange
+	}
+	return oldExchange
+}
+
+// StringConcat concatenates bodies converted to string with the delimiter.
+type StringConcat struct {
+	delimiter string
+}
+
+func NewStringConcat(delimiter string) *StringConcat {
+	return &StringConcat{delimiter: delimiter}
+}
+
+func (a *StringConcat) AggregateExchange(oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	body := bodyString(newExchange.Message().Body)
+	if oldExchange == nil {
+		newExchange.Message().Body = body
+		return newExchange
+	}
+
+	var sb strings.Builder
+	sb.WriteString(bodyString(oldExchange.Message().Body))
+	sb.WriteString(a.delimiter)
+	sb.WriteString(body)
+	oldExchange.Message().Body = sb.String()
+	return oldExchange
+}
+
+func bodyString(body any) string {
+	switch b := body.(type) {
+	case nil:
+		return ""
+	case string:
+		return b
+	case []byte:
+		return string(b)
+	default:
+		return fmt.Sprint(b)
+	}
+}
+
+// BytesConcat concatenates []byte (or string) bodies with the delimiter.
+type BytesConcat struct {
+	delimiter []byte
+}
+
+func NewBytesConcat(delimiter []byte) *BytesConcat {
+	return &BytesConcat{delimiter: delimiter}
+}
+
+func (a *BytesConcat) AggregateExchange(oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	body, err := bodyBytes(newExchange.Message().Body)
+	if oldExchange == nil {
+		if err != nil {
+			newExchange.SetError(fmt.Errorf("aggregator: bytes concat: %w", err))
+			return newExchange
+		}
+		newExchange.Message().Body = body
+		return newExchange
+	}
+	if err != nil {
+		oldExchange.SetError(fmt.Errorf("aggregator: bytes concat: %w", err))
+		return oldExchange
+	}
+
+	aggregated, _ := oldExchange.Message().Body.([]byte)
+	oldExchange.Message().Body = bytes.Join([][]byte{aggregated, body}, a.delimiter)
+	return oldExchange
+}
+
+func bodyBytes(body any) ([]byte, error) {
+	switch b := body.(type) {
+	case nil:
+		return []byte{}, nil
+	case []byte:
+		return b, nil
+	case string:
+		return []byte(b), nil
+	default:
+		return nil, fmt.Errorf("expected []byte or string body, but got %T", body)
+	}
+}
+
+// MergeMaps merges map[string]any bodies, the keys of later exchanges override the earlier ones.
+type MergeMaps struct{}
+
+func NewMergeMaps() *MergeMaps {
+	return &MergeMaps{}
+}
+
+func (a *MergeMaps) AggregateExchange(oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	body, isMap := newExchange.Message().Body.(map[string]any)
+	if oldExchange == nil {
+		if !isMap {
+			newExchange.SetError(fmt.Errorf("aggregator: merge maps: expected map[string]any body, but got %T", newExchange.Message().Body))
+			return newExchange
+		}
+		merged := make(map[string]any, len(body))
+		for k, v := range body {
+			merged[k] = v
+		}
+		newExchange.Message().Body = merged
+		return newExchange
+	}
+	if oldExchange.IsError() {
+		return oldExchange
+	}
+	merged, isMergedMap := oldExchange.Message().Body.(map[string]any)
+	if !isMergedMap {
+		oldExchange.SetError(fmt.Errorf("aggregator: merge maps: expected map[string]any aggregated body, but got %T", oldExchange.Message().Body))
+		return oldExchange
+	}
+	if !isMap {
+		oldExchange.SetError(fmt.Errorf("aggregator: merge maps: expected map[string]any body, but got %T", newExchange.Message().Body))
+		return oldExchange
+	}
+
+	for k, v := range body {
+		merged[k] = v
+	}
+	return oldExchange
+}
+
+// MergeHeaders keeps the body of the first exchange and merges headers of all exchanges,
+// the headers of later exchanges override the earlier ones.
+type MergeHeaders struct{}
+
+func NewMergeHeaders() *MergeHeaders {
+	return &MergeHeaders{}
+}
+
+func (a *MergeHeaders) AggregateExchange(oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	if oldExchange == nil {
+		return newExchange
+	}
+	for name, value := range newExchange.Message().Headers().All() {
+		oldExchange.Message().SetHeader(name, value)
+	}
+	return oldExchange
+}
+
+// GroupBy groups bodies into map[any][]any by the value of the expression evaluated against each exchange.
+type GroupBy struct {
+	key expression.Expression
+}
+
+// NewGroupBy creates GroupBy aggregator, supported expressions: expr.Simple, expr.Constant and expr.Func
+// (an inline function, bean references are not supported).
+func NewGroupBy(key expr.Definition) (*GroupBy, error) {
+	keyExpr, err := newExpression(key)
+	if err != nil {
+		return nil, fmt.Errorf("aggregator: group by: %w", err)
+	}
+	return &GroupBy{key: keyExpr}, nil
+}
+
+func MustGroupBy(key expr.Definition) *GroupBy {
+	a, err := NewGroupBy(key)
+	if err != nil {
+		panic(fmt.Errorf("camel: %w", err))
+	}
+	return a
+}
+
+func (a *GroupBy) AggregateExchange(oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	result := oldExchange
+	if result == nil {
+		result = newExchange
+	} else if oldExchange.IsError() {
+		return oldExchange
+	}
+
+	key, err := a.key.Eval(newExchange)
+	if err != nil {
+		result.SetError(fmt.Errorf("aggregator: group by: %w", err))
+		return result
+	}
+	if key != nil && !reflect.TypeOf(key).Comparable() {
+		result.SetError(fmt.Errorf("aggregator: group by: key of type %T is not comparable", key))
+		return result
+	}
+
+	body := newExchange.Message().Body
+	if oldExchange == nil {
+		newExchange.Message().Body = map[any][]any{key: {body}}
+		return newExchange
+	}
+
+	groups, isGroups := oldExchange.Message().Body.(map[any][]any)
+	if !isGroups {
+		oldExchange.SetError(fmt.Errorf("aggregator: group by: expected map[any][]any aggregated body, but got %T", oldExchange.Message().Body))
+		return oldExchange
+	}
+	groups[key] = append(groups[key], body)
+	return oldExchange
+}
+
+func newExpression(def expr.Definition) (expression.Expression, error) {
+	switch def.Kind {
+	case expr.SimpleKind:
+		simple, isString := def.Expression.(string)
+		if !isString {
+			return nil, fmt.Errorf("expected simple expression string, but got %T", def.Expression)
+		}
+		return expression.NewSimple(simple)
+	case expr.ConstantKind:
+		return expression.NewConst(def.Expression), nil
+	case expr.FuncKind:
+		if funcExpr, isFuncExpr := def.Expression.(func(e *exchange.Exchange) (any, error)); isFuncExpr {
+			return expression.NewFunc(funcExpr), nil
+		}
+		return nil, fmt.Errorf("expected function signature 'fn(*Exchange) (any, error)', but got %T", def.Expression)
+	}
+	return nil, fmt.Errorf("unsupported expression kind: %s", def.Kind)
+}
+
+// Sum sums numeric bodies, the result is int64 if all bodies are integers, otherwise float64.
+type Sum struct{}
+
+func NewSum() *Sum {
+	return &Sum{}
+}
+
+func (a *Sum) AggregateExchange(oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange {
+	if oldExchange == nil {
+		n, err := toNumber(newExchange.Message().Body)
+		if err != nil {
+			newExchange.SetError(fmt.Errorf("aggregator: sum: %w", err))
+			return newExchange
+		}
+		newExchange.Message().Body = n
+		return newExchange
+	}
+
+	n, err := toNumber(newExchange.Message().Body)
+	if err != nil {
+		oldExchange.SetError(fmt.Errorf("aggregator: sum: %w", err))
+		return oldExchange
+	}
+
+	switch sum := oldExchange.Message().Body.(type) {
+	case int64:
+		if i, isInt := n.(int64); isInt {
+			oldExchange.Message().Body = sum + i
+		} else {
+			oldExchange.Message().Body = float64(sum) + n.(float64)
+		}
+	case float64:
+		if i, isInt := n.(int64); isInt {
+			oldExchange.Message().Body = sum + float64(i)
+		} else {
+			oldExchange.Message().Body = sum + n.(float64)
+		}
+	}
+	return oldExchange
+}
+
+// toNumber converts integer kinds to int64 and float kinds to float64.
+func toNumber(v any) (any, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	default:
+		return nil, fmt.Errorf("expected numeric body, but got %T", v)
+	}
+}
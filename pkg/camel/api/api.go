@@ -4,12 +4,72 @@ import (
 	"context"
 	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
 	"github.com/paveldanilin/go-camel/pkg/camel/uri"
+	"sync"
+	"time"
 )
 
 type Processor interface {
 	Process(e *exchange.Exchange)
 }
 
+// AsyncProcessor processes the exchange without blocking the caller goroutine until the processing is completed.
+// The done callback must be called exactly once when the processing is completed (it may be called
+// synchronously, before ProcessAsync returns, or later from another goroutine).
+// Process must behave like ProcessAsync and wait for completion.
+type AsyncProcessor interface {
+	Processor
+	ProcessAsync(e *exchange.Exchange, done func())
+}
+
+// AsyncProcessorFunc adapts a function to AsyncProcessor, Process blocks until the done callback is called.
+type AsyncProcessorFunc func(e *exchange.Exchange, done func())
+
+func (f AsyncProcessorFunc) ProcessAsync(e *exchange.Exchange, done func()) {
+	f(e, done)
+}
+
+func (f AsyncProcessorFunc) Process(e *exchange.Exchange) {
+	completed := make(chan struct{})
+	var once sync.Once
+	f(e, func() {
+		once.Do(func() { close(completed) })
+	})
+	<-completed
+}
+
+// ToAsync returns the processor as AsyncProcessor.
+// A synchronous processor is adapted by calling Process and then done in the caller goroutine.
+func ToAsync(p Processor) AsyncProcessor {
+	if asyncProcessor, isAsync := p.(AsyncProcessor); isAsync {
+		return asyncProcessor
+	}
+	return syncToAsyncProcessor{delegate: p}
+}
+
+type syncToAsyncProcessor struct {
+	delegate Processor
+}
+
+func (p syncToAsyncProcessor) Process(e *exchange.Exchange) {
+	p.delegate.Process(e)
+}
+
+func (p syncToAsyncProcessor) ProcessAsync(e *exchange.Exchange, done func()) {
+	p.delegate.Process(e)
+	done()
+}
+
+// ExecutorService runs tasks by means of a bounded pool of goroutines.
+type ExecutorService interface {
+	// Submit submits the task for execution, returns an error if the task is rejected.
+	Submit(task func()) error
+}
+
+// ExecutorServiceProvider returns the ExecutorService by the profile name.
+type ExecutorServiceProvider interface {
+	ExecutorService(profile string) (ExecutorService, error)
+}
+
 type Consumer interface {
 	Start() error
 	Stop() error
@@ -19,6 +79,18 @@ type Producer interface {
 	Processor
 }
 
+// PollingConsumer is a consumer that is polled by the application code instead of pushing exchanges to a processor.
+type PollingConsumer interface {
+	Consumer
+	// Receive waits for the next exchange up to timeout, zero timeout means no waiting.
+	Receive(timeout time.Duration) (*exchange.Exchange, error)
+}
+
+// PollingEndpoint is implemented by endpoints that support polling consumers natively.
+type PollingEndpoint interface {
+	CreatePollingConsumer() (PollingConsumer, error)
+}
+
 type Endpoint interface {
 	Uri() *uri.URI
 	CreateConsumer(processor Processor) (Consumer, error)
@@ -60,10 +132,25 @@ type Logger interface {
 	Debug(ctx context.Context, msg string, args ...any)
 }
 
+// ExchangeAggregator combines exchanges into one, oldExchange is nil on the first call.
 type ExchangeAggregator interface {
 	AggregateExchange(oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange
 }
 
+// IndexedExchangeAggregator is implemented by aggregators that need the index of the aggregated exchange
+// (e.g. multicast output index), it is called instead of AggregateExchange.
+type IndexedExchangeAggregator interface {
+	ExchangeAggregator
+	AggregateExchangeAt(index int, oldExchange *exchange.Exchange, newExchange *exchange.Exchange) *exchange.Exchange
+}
+
+// CompletionAwareAggregator is implemented by aggregators that can signal the aggregation completion,
+// the remaining exchanges are not aggregated (e.g. multicast cancels the remaining outputs).
+type CompletionAwareAggregator interface {
+	ExchangeAggregator
+	IsComplete(aggregated *exchange.Exchange) bool
+}
+
 type ExchangeFactory interface {
 	NewExchange(c context.Context) *exchange.Exchange
 }
@@ -71,3 +158,55 @@ type ExchangeFactory interface {
 type Env interface {
 	LookupVar(name string) (string, bool)
 }
+
+// RouteController exposes a registered route to RoutePolicy implementations.
+type RouteController interface {
+	Name() string
+	From() string
+	// Suspend stops consuming from the route endpoint, exchanges sent to a suspended route are rejected.
+	// The consumer shared with other routes keeps running for them.
+	Suspend() error
+	// Resume restarts consuming from the route endpoint.
+	Resume() error
+	IsSuspended() bool
+}
+
+// RoutePolicy allows to hook into the route lifecycle and exchange processing.
+type RoutePolicy interface {
+	// OnInit is called once the route has been registered in the Runtime.
+	OnInit(r RouteController)
+	// OnStart is called after the route consumer has been started.
+	OnStart(r RouteController)
+	// OnStop is called before the route consumer is stopped.
+	OnStop(r RouteController)
+	// OnExchangeBegin is called before the route starts processing an exchange.
+	OnExchangeBegin(r RouteController, e *exchange.Exchange)
+	// OnExchangeDone is called after the route has processed an exchange.
+	OnExchangeDone(r RouteController, e *exchange.Exchange)
+}
+
+// SagaStep is the actions of a saga participant, the actions are processed with the copy of the exchange
+// the participant joined the saga with (nil - no action).
+type SagaStep struct {
+	Compensation Processor
+	Completion   Processor
+}
+
+// SagaCoordinator tracks the participants of a saga.
+type SagaCoordinator interface {
+	Id() string
+	// BeginStep registers the actions of the saga participant.
+	BeginStep(e *exchange.Exchange, step SagaStep) error
+	// Compensate processes the compensations of the participants in reverse order.
+	Compensate() error
+	// Complete processes the completions of the participants in order.
+	Complete() error
+}
+
+// SagaService creates and finds sagas (see Saga step).
+type SagaService interface {
+	// NewSaga creates a saga that is compensated once the timeout elapses (zero - no timeout).
+	NewSaga(timeout time.Duration) (SagaCoordinator, error)
+	// Saga returns the active saga by id.
+	Saga(id string) (SagaCoordinator, bool)
+}
@@ -8,17 +8,22 @@ import (
 	"github.com/paveldanilin/go-camel/internal/eip/convertheader"
 	"github.com/paveldanilin/go-camel/internal/eip/convertproperty"
 	"github.com/paveldanilin/go-camel/internal/eip/delay"
+	"github.com/paveldanilin/go-camel/internal/eip/errorhandler"
 	"github.com/paveldanilin/go-camel/internal/eip/fn"
 	"github.com/paveldanilin/go-camel/internal/eip/log"
 	"github.com/paveldanilin/go-camel/internal/eip/marshal"
 	"github.com/paveldanilin/go-camel/internal/eip/multicast"
+	"github.com/paveldanilin/go-camel/internal/eip/oncompletion"
 	"github.com/paveldanilin/go-camel/internal/eip/pipeline"
 	"github.com/paveldanilin/go-camel/internal/eip/removeheader"
 	"github.com/paveldanilin/go-camel/internal/eip/removeproperty"
+	"github.com/paveldanilin/go-camel/internal/eip/saga"
 	"github.com/paveldanilin/go-camel/internal/eip/setbody"
 	"github.com/paveldanilin/go-camel/internal/eip/seterror"
 	"github.com/paveldanilin/go-camel/internal/eip/setheader"
+	"github.com/paveldanilin/go-camel/internal/eip/setpattern"
 	"github.com/paveldanilin/go-camel/internal/eip/setproperty"
+	"github.com/paveldanilin/go-camel/internal/eip/threads"
 	"github.com/paveldanilin/go-camel/internal/eip/to"
 	"github.com/paveldanilin/go-camel/internal/eip/try"
 	"github.com/paveldanilin/go-camel/internal/eip/unmarshal"
@@ -30,34 +35,244 @@ import (
 	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
 	"github.com/paveldanilin/go-camel/pkg/camel/template"
 	"reflect"
-	"strings"
+	"regexp"
+	"sort"
+	"time"
 )
 
 type compilerConfig struct {
 	logger             api.Logger
 	env                api.Env
 	funcRegistry       FuncRegistry
+	beanRegistry       BeanRegistry
 	dataFormatRegistry DataFormatRegistry
 	converterRegistry  ConverterRegistry
+	componentRegistry  ComponentRegistry
+	executorRegistry   ExecutorRegistry
 	endpointRegistry   EndpointRegistry
 	preProcessor       func(e *exchange.Exchange)
 	postProcessor      func(e *exchange.Exchange)
+	// errorHandler applies to the steps being compiled (runtime or route error handler or the one of ErrorHandlerScope)
+	errorHandler *routestep.ErrorHandler
+	// deadLetter is the producer of the errorHandler dead letter endpoint
+	deadLetter api.Producer
+	// onExceptions are the runtime onException clauses, they are applied after the route ones
+	onExceptions []routestep.OnException
+	// onExceptionHandlers are the compiled onException clauses of the route, ordered by matcher specificity
+	onExceptionHandlers []errorhandler.OnException
+	sagaService         api.SagaService
+	// intercepts, interceptFroms and interceptSendTos are the runtime intercept clauses, they are applied before the route ones
+	intercepts       []routestep.Intercept
+	interceptFroms   []routestep.InterceptFrom
+	interceptSendTos []routestep.InterceptSendToEndpoint
+	// interceptor is processed before every step being compiled (see Route.Intercepts)
+	interceptor   api.Processor
+	simpleContext *expression.SimpleContext
+	// stepPaths are the paths of the route steps reported by StepError
+	stepPaths map[api.RouteStep]string
 }
 
 // compileRoute takes Route definition and returns runtime representation of the route.
 func compileRoute(c compilerConfig, routeDefinition *Route) (*route, error) {
+	c.stepPaths = map[api.RouteStep]string{}
+	collectStepPaths(routeDefinition.Name, routeDefinition.Steps, c.stepPaths)
+
+	intercepts, err := createIntercepts(c, routeDefinition)
+	if err != nil {
+		return nil, err
+	}
+	c = intercepts.apply(c)
+
+	// The route error handler overrides the runtime one
+	errorHandler := routeDefinition.ErrorHandler
+	if errorHandler == nil {
+		errorHandler = c.errorHandler
+	}
+	if errorHandler != nil {
+		if c, err = errorHandlerConfig(c, errorHandler); err != nil {
+			return nil, err
+		}
+	}
+
+	onExceptions := append(append([]routestep.OnException{}, routeDefinition.OnExceptions...), c.onExceptions...)
+
+	for i, onException := range onExceptions {
+		collectStepPaths(fmt.Sprintf("%s/onException[%d]", routeDefinition.Name, i), onException.Steps, c.stepPaths)
+	}
+	for i, onCompletion := range routeDefinition.OnCompletions {
+		collectStepPaths(fmt.Sprintf("%s/onCompletion[%d]", routeDefinition.Name, i), onCompletion.Steps, c.stepPaths)
+	}
+
+	if len(onExceptions) > 0 {
+		if c.onExceptionHandlers, err = createOnExceptionHandlers(c, routeDefinition.Name, onExceptions); err != nil {
+			return nil, err
+		}
+	}
+
 	producer, err := createProcessor(c, routeDefinition.Name, routeDefinition.Steps...)
 	if err != nil {
 		return nil, err
 	}
 
+	onCompletions, err := createOnCompletionProcessors(c, routeDefinition.Name, routeDefinition.OnCompletions)
+	if err != nil {
+		return nil, err
+	}
+
 	return &route{
-		name:     routeDefinition.Name,
-		from:     routeDefinition.From,
-		producer: producer,
+		name:                routeDefinition.Name,
+		from:                routeDefinition.From,
+		producer:            intercepts.interceptFrom(routeDefinition, producer),
+		onCompletions:       onCompletions,
+		keepOriginalMessage: usesOriginalMessage(c.errorHandler, routeDefinition.Steps),
 	}, nil
 }
 
+// createOnCompletionProcessors compiles the onCompletion clauses, the route error handling applies to their steps.
+func createOnCompletionProcessors(c compilerConfig, routeName string, onCompletions []routestep.OnCompletion) ([]api.Processor, error) {
+	processors := make([]api.Processor, 0, len(onCompletions))
+	for i, onCompletion := range onCompletions {
+		handler, err := createProcessor(c, routeName, onCompletion.Steps...)
+		if err != nil {
+			return nil, fmt.Errorf("onCompletion[%d]: %w", i, err)
+		}
+
+		mode := oncompletion.Mode(onCompletion.Mode)
+		if mode == "" {
+			mode = oncompletion.ModeAlways
+		}
+		p := oncompletion.NewProcessor(routeName, fmt.Sprintf("onCompletion[%d]", i), handler).
+			SetMode(mode).
+			SetParallel(onCompletion.Parallel)
+
+		if onCompletion.OnWhen.Kind != "" {
+			onWhen, err := createExpression(c, onCompletion.OnWhen)
+			if err != nil {
+				return nil, fmt.Errorf("onCompletion[%d]: %w", i, err)
+			}
+			p.SetOnWhen(expression.NewPredicateFromExpression(onWhen))
+		}
+		processors = append(processors, p)
+	}
+	return processors, nil
+}
+
+// errorHandlerConfig returns the config to compile the steps handled by the given error handler.
+func errorHandlerConfig(c compilerConfig, errorHandler *routestep.ErrorHandler) (compilerConfig, error) {
+	c.errorHandler = errorHandler
+	c.deadLetter = nil
+	if errorHandler.DeadLetterURI == "" {
+		return c, nil
+	}
+
+	endpoint, err := c.endpointRegistry.ResolveEndpoint(errorHandler.DeadLetterURI)
+	if err != nil {
+		return c, fmt.Errorf("failed to create dead letter channel: %w", err)
+	}
+	c.deadLetter, err = endpoint.CreateProducer()
+	if err != nil {
+		return c, fmt.Errorf("failed to create dead letter channel: %w", err)
+	}
+	return c, nil
+}
+
+// createOnExceptionHandlers compiles the onException clauses, the most specific matcher goes first,
+// the clauses with the same specificity keep the declaration order.
+// The handler steps are compiled without the error handler and the onException clauses.
+func createOnExceptionHandlers(c compilerConfig, routeName string, onExceptions []routestep.OnException) ([]errorhandler.OnException, error) {
+	handlerConfig := c
+	handlerConfig.errorHandler = nil
+	handlerConfig.deadLetter = nil
+	handlerConfig.onExceptionHandlers = nil
+
+	handlers := make([]errorhandler.OnException, len(onExceptions))
+	for i, onException := range onExceptions {
+		matcher, err := createErrMatcher(c, onException.ErrorMatcher)
+		if err != nil {
+			return nil, fmt.Errorf("onException[%d]: %w", i, err)
+		}
+		handlers[i] = errorhandler.OnException{
+			Matcher:   matcher,
+			Handled:   onException.Handled,
+			Continued: onException.Continued,
+		}
+		if len(onException.Steps) > 0 {
+			handler, err := createProcessor(handlerConfig, routeName, onException.Steps...)
+			if err != nil {
+				return nil, fmt.Errorf("onException[%d]: %w", i, err)
+			}
+			handlers[i].Handler = handler
+		}
+	}
+
+	order := make([]int, len(onExceptions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return errMatcherSpecificity(onExceptions[order[i]].ErrorMatcher) > errMatcherSpecificity(onExceptions[order[j]].ErrorMatcher)
+	})
+	sorted := make([]errorhandler.OnException, len(order))
+	for i, idx := range order {
+		sorted[i] = handlers[idx]
+	}
+	return sorted, nil
+}
+
+// errMatcherSpecificity ranks the matcher: exact matchers (value, type or message) go before pattern matchers,
+// any error goes last. AnyOf is as specific as its least specific operand, AllOf - as the most specific one.
+func errMatcherSpecificity(matcher errs.Matcher) int {
+	if matcher.IsAny() {
+		return 0
+	}
+	switch matcher.MatchMode {
+	case errs.MatchModeIs, errs.MatchModeAs, errs.MatchModeEquals:
+		return 2
+	case errs.MatchModeAnyOf:
+		specificity := 2
+		for _, operand := range matcher.Matchers {
+			specificity = min(specificity, errMatcherSpecificity(operand))
+		}
+		return specificity
+	case errs.MatchModeAllOf:
+		specificity := 0
+		for _, operand := range matcher.Matchers {
+			specificity = max(specificity, errMatcherSpecificity(operand))
+		}
+		return specificity
+	default:
+		return 1
+	}
+}
+
+// createEndpointProducer resolves the endpoint and creates its producer.
+func createEndpointProducer(c compilerConfig, uri string) (api.Producer, error) {
+	endpoint, err := c.endpointRegistry.ResolveEndpoint(uri)
+	if err != nil {
+		return nil, err
+	}
+	return endpoint.CreateProducer()
+}
+
+// usesOriginalMessage returns TRUE if the route error handler or any ErrorHandlerScope of the route
+// sends the original message to the dead letter channel.
+func usesOriginalMessage(errorHandler *routestep.ErrorHandler, steps []api.RouteStep) bool {
+	if errorHandler != nil && errorHandler.UseOriginalMessage {
+		return true
+	}
+	for _, step := range steps {
+		if scope, isScope := step.(*routestep.ErrorHandlerScope); isScope && scope.ErrorHandler.UseOriginalMessage {
+			return true
+		}
+		for _, branch := range stepBranches(step) {
+			if usesOriginalMessage(nil, branch.steps) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func createProcessor(c compilerConfig, routeName string, s ...api.RouteStep) (api.Processor, error) {
 	if s == nil || len(s) == 0 {
 		return nil, errors.New("empty steps")
@@ -65,19 +280,27 @@ func createProcessor(c compilerConfig, routeName string, s ...api.RouteStep) (ap
 
 	if len(s) > 1 {
 		pipe := pipeline.NewProcessor(routeName, "", false)
-		for _, innerStep := range s {
-			p, err := createProcessor(c, routeName, innerStep)
-			if err != nil {
-				return nil, err
-			}
-			pipe.AddProcessor(p)
+		if err := addStepProcessors(c, routeName, s, func(p api.Processor) { pipe.AddProcessor(p) }); err != nil {
+			return nil, err
 		}
 		return decorateProcessor(pipe, c.preProcessor, c.postProcessor), nil
 	}
 
-	switch t := s[0].(type) {
+	p, err := createStepProcessor(c, routeName, s[0])
+	if err != nil {
+		return nil, err
+	}
+	if stepProcessor, isDecorated := p.(*processor); isDecorated {
+		stepProcessor.stepPath = c.stepPaths[s[0]]
+		stepProcessor.interceptor = c.interceptor
+	}
+	return withErrorHandler(c, routeName, s[0], p)
+}
+
+func createStepProcessor(c compilerConfig, routeName string, step api.RouteStep) (api.Processor, error) {
+	switch t := step.(type) {
 	case *routestep.SetBody:
-		bodyExpr, err := createExpression(t.BodyValue)
+		bodyExpr, err := createExpression(c, t.BodyValue)
 		if err != nil {
 			return nil, err
 		}
@@ -85,7 +308,7 @@ func createProcessor(c compilerConfig, routeName string, s ...api.RouteStep) (ap
 		return decorateProcessor(p, c.preProcessor, c.postProcessor), nil
 
 	case *routestep.SetHeader:
-		headerExpr, err := createExpression(t.HeaderValue)
+		headerExpr, err := createExpression(c, t.HeaderValue)
 		if err != nil {
 			return nil, err
 		}
@@ -93,7 +316,7 @@ func createProcessor(c compilerConfig, routeName string, s ...api.RouteStep) (ap
 		return decorateProcessor(p, c.preProcessor, c.postProcessor), nil
 
 	case *routestep.SetProperty:
-		propertyExpr, err := createExpression(t.PropertyValue)
+		propertyExpr, err := createExpression(c, t.PropertyValue)
 		if err != nil {
 			return nil, err
 		}
@@ -108,9 +331,9 @@ func createProcessor(c compilerConfig, routeName string, s ...api.RouteStep) (ap
 
 		var p api.Processor
 		if len(uriVars) == 0 {
-			endpoint := c.endpointRegistry.Endpoint(t.URI)
-			if endpoint == nil {
-				return nil, fmt.Errorf("failed to create 'to' processor: not found endpoint for URI '%s'", t.URI)
+			endpoint, endpointErr := c.endpointRegistry.ResolveEndpoint(t.URI)
+			if endpointErr != nil {
+				return nil, fmt.Errorf("failed to create 'to' processor: %w", endpointErr)
 			}
 			producer, producerErr := endpoint.CreateProducer()
 			if producerErr != nil {
@@ -129,12 +352,8 @@ func createProcessor(c compilerConfig, routeName string, s ...api.RouteStep) (ap
 
 	case *routestep.Pipeline:
 		pipe := pipeline.NewProcessor(routeName, t.StepName(), t.StoOnError)
-		for _, innerStep := range t.Steps {
-			p, err := createProcessor(c, routeName, innerStep)
-			if err != nil {
-				return nil, err
-			}
-			pipe.AddProcessor(p)
+		if err := addStepProcessors(c, routeName, t.Steps, func(p api.Processor) { pipe.AddProcessor(p) }); err != nil {
+			return nil, err
 		}
 		return decorateProcessor(pipe, c.preProcessor, c.postProcessor), nil
 
@@ -146,7 +365,7 @@ func createProcessor(c compilerConfig, routeName string, s ...api.RouteStep) (ap
 				return nil, err
 			}
 
-			prdExpr, err := createExpression(when.Predicate)
+			prdExpr, err := createExpression(c, when.Predicate)
 			if err != nil {
 				return nil, err
 			}
@@ -164,12 +383,11 @@ func createProcessor(c compilerConfig, routeName string, s ...api.RouteStep) (ap
 	case *routestep.Try:
 		p := try.NewProcessor(routeName, t.StepName())
 
-		for _, tryStep := range t.Steps {
-			tp, err := createProcessor(c, routeName, tryStep)
-			if err != nil {
-				return nil, err
-			}
-			p.AddProcessor(tp)
+		// The catches of the try block take precedence over the onException clauses
+		tryConfig := c
+		tryConfig.onExceptionHandlers = nil
+		if err := addStepProcessors(tryConfig, routeName, t.Steps, func(tp api.Processor) { p.AddProcessor(tp) }); err != nil {
+			return nil, err
 		}
 
 		for _, catch := range t.WhenCatches {
@@ -177,15 +395,15 @@ func createProcessor(c compilerConfig, routeName string, s ...api.RouteStep) (ap
 			if err != nil {
 				return nil, err
 			}
-			p.AddCatch(createErrMatcher(catch.ErrorMatcher), catchProcessor)
-		}
-
-		for _, finallyStep := range t.FinallySteps {
-			finallyProcessor, err := createProcessor(c, routeName, finallyStep)
+			matcher, err := createErrMatcher(c, catch.ErrorMatcher)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("try routestep: %s: catch: %w", t.StepName(), err)
 			}
-			p.AddFinally(finallyProcessor)
+			p.AddCatch(matcher, catchProcessor)
+		}
+
+		if err := addStepProcessors(c, routeName, t.FinallySteps, func(fp api.Processor) { p.AddFinally(fp) }); err != nil {
+			return nil, err
 		}
 		return decorateProcessor(p, c.preProcessor, c.postProcessor), nil
 
@@ -212,7 +430,23 @@ func createProcessor(c compilerConfig, routeName string, s ...api.RouteStep) (ap
 		return decorateProcessor(p, c.preProcessor, c.postProcessor), nil
 
 	case *routestep.Multicast:
-		p := multicast.NewProcessor(routeName, t.StepName(), t.Parallel, t.StopOnError, t.Aggregator)
+		aggregator := t.Aggregator
+		if aggregator == nil && t.AggregatorRef != "" {
+			bean := c.beanRegistry.Bean(t.AggregatorRef)
+			if bean == nil {
+				return nil, fmt.Errorf("multicast routestep: %s: aggregator bean not found in registry: %s", t.StepName(), t.AggregatorRef)
+			}
+			beanAggregator, isAggregator := bean.(api.ExchangeAggregator)
+			if !isAggregator {
+				return nil, fmt.Errorf("multicast routestep: %s: bean '%s' does not implement ExchangeAggregator", t.StepName(), t.AggregatorRef)
+			}
+			aggregator = beanAggregator
+		}
+		p := multicast.NewProcessor(routeName, t.StepName(), t.Parallel, t.StopOnError, aggregator).
+			SetTimeout(time.Duration(t.Timeout) * time.Millisecond)
+		if t.ExecutorService != "" {
+			p.SetExecutorService(newExecutorServiceRef(c, t.ExecutorService))
+		}
 		for _, output := range t.Outputs {
 			outputProcessor, err := createProcessor(c, routeName, output.Steps...)
 			if err != nil {
@@ -234,12 +468,62 @@ func createProcessor(c compilerConfig, routeName string, s ...api.RouteStep) (ap
 		p := removeproperty.NewProcessor(routeName, t.StepName(), t.PropertyNames...)
 		return decorateProcessor(p, c.preProcessor, c.postProcessor), nil
 
+	case *routestep.SetPattern:
+		p := setpattern.NewProcessor(routeName, t.StepName(), t.Pattern)
+		return decorateProcessor(p, c.preProcessor, c.postProcessor), nil
+
+	case *routestep.ErrorHandlerScope:
+		scopeConfig, err := errorHandlerConfig(c, &t.ErrorHandler)
+		if err != nil {
+			return nil, err
+		}
+		pipe := pipeline.NewProcessor(routeName, t.StepName(), false)
+		if err := addStepProcessors(scopeConfig, routeName, t.Steps, func(p api.Processor) { pipe.AddProcessor(p) }); err != nil {
+			return nil, err
+		}
+		return decorateProcessor(pipe, c.preProcessor, c.postProcessor), nil
+
+	case *routestep.Saga:
+		body := pipeline.NewProcessor(routeName, t.StepName(), true)
+		if err := addStepProcessors(c, routeName, t.Steps, func(p api.Processor) { body.AddProcessor(p) }); err != nil {
+			return nil, err
+		}
+		p := saga.NewProcessor(routeName, t.StepName(), body, c.sagaService).
+			SetPropagation(saga.Propagation(t.Propagation)).
+			SetTimeout(time.Duration(t.Timeout) * time.Millisecond)
+		if t.CompensationURI != "" {
+			compensation, err := createEndpointProducer(c, t.CompensationURI)
+			if err != nil {
+				return nil, fmt.Errorf("saga routestep: %s: compensation: %w", t.StepName(), err)
+			}
+			p.SetCompensation(compensation)
+		}
+		if t.CompletionURI != "" {
+			completion, err := createEndpointProducer(c, t.CompletionURI)
+			if err != nil {
+				return nil, fmt.Errorf("saga routestep: %s: completion: %w", t.StepName(), err)
+			}
+			p.SetCompletion(completion)
+		}
+		return decorateProcessor(p, c.preProcessor, c.postProcessor), nil
+
+	case *routestep.Threads:
+		// Threads is the last step of the steps list (see addStepProcessors)
+		return createThreadsProcessor(c, routeName, t, nil)
+
 	case *routestep.Marshal:
 		p := marshal.NewProcessor(routeName, t.StepName(), c.dataFormatRegistry.DataFormat(t.Format))
 		return decorateProcessor(p, c.preProcessor, c.postProcessor), nil
 
 	case *routestep.Unmarshal:
-		p := unmarshal.NewProcessor(routeName, t.StepName(), t.TargetType, c.dataFormatRegistry.DataFormat(t.Format))
+		targetType := t.TargetType
+		if targetType == nil && t.TargetTypeRef != "" {
+			targetType = c.beanRegistry.Bean(t.TargetTypeRef)
+			if targetType == nil {
+				return nil, fmt.Errorf("unmarshal routestep: %s: target type bean not found in registry: %s", t.StepName(), t.TargetTypeRef)
+			}
+		}
+		p := unmarshal.NewProcessor(routeName, t.StepName(), targetType, c.dataFormatRegistry.DataFormat(t.Format))
 		return decorateProcessor(p, c.preProcessor, c.postProcessor), nil
 
 	case *routestep.ConvertBody:
@@ -306,13 +590,134 @@ func createProcessor(c compilerConfig, routeName string, s ...api.RouteStep) (ap
 		return decorateProcessor(p, c.preProcessor, c.postProcessor), nil
 	}
 
-	return nil, fmt.Errorf("unknown route step: %T", s[0])
+	return nil, fmt.Errorf("unknown route step: %T", step)
+}
+
+// withErrorHandler wraps the processor of the step into the error handler.
+// Only the steps without nested steps are redelivered, thus redelivery retries the failed step, not the whole block.
+// The steps with nested steps (try, choice, multicast,...) are wrapped without redelivery to apply the onException
+// clauses and the dead letter channel to the errors raised by the step itself (e.g. choice predicate error);
+// the errors already processed by the error handlers of the nested steps are passed through.
+func withErrorHandler(c compilerConfig, routeName string, step api.RouteStep, p api.Processor) (api.Processor, error) {
+	if _, isThreads := step.(*routestep.Threads); isThreads {
+		return p, nil
+	}
+	if len(stepBranches(step)) > 0 {
+		if c.deadLetter == nil && len(c.onExceptionHandlers) == 0 {
+			return p, nil
+		}
+		errorHandler := errorhandler.NewProcessor(routeName, step.StepName(), p, errorhandler.RedeliveryPolicy{})
+		if c.deadLetter != nil {
+			errorHandler.SetDeadLetter(c.deadLetter, c.errorHandler.UseOriginalMessage)
+		}
+		return errorHandler.SetOnExceptions(c.onExceptionHandlers), nil
+	}
+	if c.errorHandler == nil {
+		if len(c.onExceptionHandlers) == 0 {
+			return p, nil
+		}
+		return errorhandler.NewProcessor(routeName, step.StepName(), p, errorhandler.RedeliveryPolicy{}).
+			SetOnExceptions(c.onExceptionHandlers), nil
+	}
+
+	redeliveryPolicy := c.errorHandler.Redelivery
+	if redeliveryPolicy.MaximumRedeliveries == 0 && c.deadLetter == nil && len(c.onExceptionHandlers) == 0 {
+		return p, nil
+	}
+
+	policy := errorhandler.RedeliveryPolicy{
+		MaximumRedeliveries: redeliveryPolicy.MaximumRedeliveries,
+		Delay:               time.Duration(redeliveryPolicy.RedeliveryDelay) * time.Millisecond,
+		BackOffMultiplier:   redeliveryPolicy.BackOffMultiplier,
+		MaximumDelay:        time.Duration(redeliveryPolicy.MaximumRedeliveryDelay) * time.Millisecond,
+		Jitter:              redeliveryPolicy.Jitter,
+	}
+	if redeliveryPolicy.RetryWhile.Kind != "" {
+		retryWhile, err := createExpression(c, redeliveryPolicy.RetryWhile)
+		if err != nil {
+			return nil, fmt.Errorf("error handler: retryWhile: %w", err)
+		}
+		policy.RetryWhile = expression.NewPredicateFromExpression(retryWhile)
+	}
+	if len(redeliveryPolicy.RetryOn) > 0 {
+		matchers := make([]try.ErrorMatcher, len(redeliveryPolicy.RetryOn))
+		for i, matcher := range redeliveryPolicy.RetryOn {
+			var err error
+			if matchers[i], err = createErrMatcher(c, matcher); err != nil {
+				return nil, fmt.Errorf("error handler: retryOn: %w", err)
+			}
+		}
+		policy.RetryOn = try.AnyOf(matchers...)
+	}
+
+	errorHandler := errorhandler.NewProcessor(routeName, step.StepName(), p, policy)
+	if c.deadLetter != nil {
+		errorHandler.SetDeadLetter(c.deadLetter, c.errorHandler.UseOriginalMessage)
+	}
+	return errorHandler.SetOnExceptions(c.onExceptionHandlers), nil
+}
+
+// addStepProcessors creates processors of the steps in order, Threads step takes over the rest of the steps.
+func addStepProcessors(c compilerConfig, routeName string, steps []api.RouteStep, add func(p api.Processor)) error {
+	for i, step := range steps {
+		if threadsStep, isThreads := step.(*routestep.Threads); isThreads {
+			p, err := createThreadsProcessor(c, routeName, threadsStep, steps[i+1:])
+			if err != nil {
+				return err
+			}
+			add(p)
+			return nil
+		}
+
+		p, err := createProcessor(c, routeName, step)
+		if err != nil {
+			return err
+		}
+		add(p)
+	}
+	return nil
+}
+
+func createThreadsProcessor(c compilerConfig, routeName string, t *routestep.Threads, rest []api.RouteStep) (api.Processor, error) {
+	if _, exists := c.executorRegistry.Profile(t.Profile); !exists {
+		return nil, fmt.Errorf("threads routestep: %s: executor profile not found: %s", t.StepName(), t.Profile)
+	}
+
+	var next api.Processor
+	if len(rest) > 0 {
+		var err error
+		next, err = createProcessor(c, routeName, rest...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	p := threads.NewProcessor(routeName, t.StepName(), newExecutorServiceRef(c, t.Profile), next)
+	return decorateProcessor(p, c.preProcessor, c.postProcessor), nil
+}
+
+// executorServiceRef resolves the pool of the executor profile on every submit,
+// thus processors keep working after the pools are shut down and recreated by the runtime restart.
+type executorServiceRef struct {
+	registry ExecutorRegistry
+	profile  string
+}
+
+func newExecutorServiceRef(c compilerConfig, profile string) *executorServiceRef {
+	return &executorServiceRef{registry: c.executorRegistry, profile: profile}
+}
+
+func (r *executorServiceRef) Submit(task func()) error {
+	executorService, err := r.registry.ExecutorService(r.profile)
+	if err != nil {
+		return err
+	}
+	return executorService.Submit(task)
 }
 
-func createExpression(def expr.Definition) (expression.Expression, error) {
+func createExpression(c compilerConfig, def expr.Definition) (expression.Expression, error) {
 	switch def.Kind {
 	case expr.SimpleKind:
-		se, err := expression.NewSimple(def.Expression.(string))
+		se, err := expression.NewSimpleWithContext(def.Expression.(string), c.simpleContext)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create simple expression: %w", err)
 		}
@@ -323,27 +728,91 @@ func createExpression(def expr.Definition) (expression.Expression, error) {
 		if funcExpr, isFuncExpr := def.Expression.(func(e *exchange.Exchange) (any, error)); isFuncExpr {
 			return expression.NewFunc(funcExpr), nil
 		}
+		if beanName, isBeanRef := def.Expression.(string); isBeanRef {
+			bean := c.beanRegistry.Bean(beanName)
+			if bean == nil {
+				return nil, fmt.Errorf("failed to create func expression: bean not found in registry: %s", beanName)
+			}
+			if funcExpr, isFuncExpr := bean.(func(e *exchange.Exchange) (any, error)); isFuncExpr {
+				return expression.NewFunc(funcExpr), nil
+			}
+			return nil, fmt.Errorf("failed to create func expression: bean '%s': expected type 'func(e *exchange.Exchange) (any, error)', but got %T", beanName, bean)
+		}
 		return nil, fmt.Errorf("failed to create func expression: expected type 'func(e *exchange.Exchange) (any, error)', but got %T", def.Expression)
+	case expr.JSONPathKind:
+		jsonPathExpr, isJSONPathExpr := def.Expression.(expr.JSONPathExpression)
+		if !isJSONPathExpr {
+			return nil, fmt.Errorf("failed to create jsonpath expression: expected type 'expr.JSONPathExpression', but got %T", def.Expression)
+		}
+		switch jsonPathExpr.Result {
+		case expr.JSONPathResultAuto, expr.JSONPathResultSingle, expr.JSONPathResultList:
+		default:
+			return nil, fmt.Errorf("failed to create jsonpath expression: unknown result: %s", jsonPathExpr.Result)
+		}
+		jp, err := expression.NewJSONPath(jsonPathExpr.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create jsonpath expression: %w", err)
+		}
+		return jp.SetSuppressExceptions(jsonPathExpr.SuppressExceptions).
+			SetResult(expression.JSONPathResult(jsonPathExpr.Result)), nil
 	}
 
 	return nil, fmt.Errorf("unknown expression kind: %s", def.Kind)
 }
 
-func createErrMatcher(matcherDefinition errs.Matcher) try.ErrorMatcher {
-	if strings.TrimSpace(matcherDefinition.Target) == "*" || strings.TrimSpace(matcherDefinition.Target) == "" {
-		return try.AnyError()
+func createErrMatcher(c compilerConfig, matcherDefinition errs.Matcher) (try.ErrorMatcher, error) {
+	if matcherDefinition.IsAny() {
+		return try.AnyError(), nil
 	}
 
 	switch matcherDefinition.MatchMode {
 	case errs.MatchModeIs:
-		return try.ErrorIs(matcherDefinition.Target)
+		if matcherDefinition.Err != nil {
+			return try.ErrorIs(matcherDefinition.Err), nil
+		}
+		return try.ErrorIsMessage(matcherDefinition.Target), nil
+	case errs.MatchModeAs:
+		if matcherDefinition.Type == nil {
+			return nil, errors.New("error matcher: as: target type is not set")
+		}
+		return try.ErrorAs(matcherDefinition.Type), nil
 	case errs.MatchModeContains:
-		return try.ErrorContains(matcherDefinition.Target)
+		return try.ErrorContains(matcherDefinition.Target), nil
 	case errs.MatchModeEquals:
-		return try.ErrorEquals(matcherDefinition.Target)
+		return try.ErrorEquals(matcherDefinition.Target), nil
 	case errs.MatchModeRegex:
-		return try.ErrorMatches(matcherDefinition.Target)
+		if _, err := regexp.Compile(matcherDefinition.Target); err != nil {
+			return nil, fmt.Errorf("error matcher: regex: %w", err)
+		}
+		return try.ErrorMatches(matcherDefinition.Target), nil
+	case errs.MatchModePredicate:
+		predicate, err := createExpression(c, matcherDefinition.Predicate)
+		if err != nil {
+			return nil, fmt.Errorf("error matcher: predicate: %w", err)
+		}
+		return try.ErrorPredicate(expression.NewPredicateFromExpression(predicate)), nil
+	case errs.MatchModeAnyOf, errs.MatchModeAllOf, errs.MatchModeNot:
+		if len(matcherDefinition.Matchers) == 0 {
+			return nil, fmt.Errorf("error matcher: %s: no matchers", matcherDefinition.MatchMode)
+		}
+		operands := make([]try.ErrorMatcher, len(matcherDefinition.Matchers))
+		for i, operand := range matcherDefinition.Matchers {
+			var err error
+			if operands[i], err = createErrMatcher(c, operand); err != nil {
+				return nil, err
+			}
+		}
+		switch matcherDefinition.MatchMode {
+		case errs.MatchModeAnyOf:
+			return try.AnyOf(operands...), nil
+		case errs.MatchModeAllOf:
+			return try.AllOf(operands...), nil
+		}
+		if len(operands) != 1 {
+			return nil, errors.New("error matcher: not: expected exactly one matcher")
+		}
+		return try.Not(operands[0]), nil
 	}
 
-	panic(fmt.Errorf("camel: error matcher: unknown mode: %s", matcherDefinition.MatchMode))
+	return nil, fmt.Errorf("error matcher: unknown mode: %s", matcherDefinition.MatchMode)
 }
@@ -16,3 +16,33 @@ func (c *Consumer) Start() error {
 func (c *Consumer) Stop() error {
 	return nil
 }
+
+// RemoveProcessor detaches the processor from the consumer, e.g. when the route is removed from the runtime.
+func (c *Consumer) RemoveProcessor(processor api.Processor) {
+	c.endpoint.mu.Lock()
+	defer c.endpoint.mu.Unlock()
+
+	producers := make([]api.Producer, 0, len(c.producers))
+	for _, p := range c.producers {
+		if p != processor {
+			producers = append(producers, p)
+		}
+	}
+	c.producers = producers
+}
+
+// AddProcessor attaches the processor to the consumer, e.g. when the suspended route is resumed.
+func (c *Consumer) AddProcessor(processor api.Processor) {
+	c.endpoint.mu.Lock()
+	defer c.endpoint.mu.Unlock()
+
+	c.producers = append(c.producers, processor)
+}
+
+// ProcessorCount returns the number of the processors (routes) the consumer passes the exchanges to.
+func (c *Consumer) ProcessorCount() int {
+	c.endpoint.mu.RLock()
+	defer c.endpoint.mu.RUnlock()
+
+	return len(c.producers)
+}
@@ -1,15 +1,52 @@
 package direct
 
 import (
+	"errors"
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
 	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
 )
 
+// ErrNoConsumers is set on exchanges sent to a direct endpoint that no route consumes from.
+var ErrNoConsumers = errors.New("no consumers available on endpoint")
+
 type Producer struct {
 	endpoint *Endpoint
 }
 
 func (p *Producer) Process(e *exchange.Exchange) {
-	for _, producer := range p.endpoint.consumer.producers {
+	for _, producer := range p.consumerProducers(e) {
 		producer.Process(e)
 	}
 }
+
+// ProcessAsync passes the exchange to the consumers one after another by means of completion callbacks.
+func (p *Producer) ProcessAsync(e *exchange.Exchange, done func()) {
+	p.processNext(e, p.consumerProducers(e), done)
+}
+
+func (p *Producer) processNext(e *exchange.Exchange, producers []api.Producer, done func()) {
+	if len(producers) == 0 {
+		done()
+		return
+	}
+	api.ToAsync(producers[0]).ProcessAsync(e, func() {
+		p.processNext(e, producers[1:], done)
+	})
+}
+
+// consumerProducers returns the processors of the bound consumer, sets ErrNoConsumers if there are no one.
+func (p *Producer) consumerProducers(e *exchange.Exchange) []api.Producer {
+	// The consumer is bound lazily: the route that consumes from the endpoint can be started after the producer is created
+	p.endpoint.mu.RLock()
+	var producers []api.Producer
+	if p.endpoint.consumer != nil {
+		producers = p.endpoint.consumer.producers
+	}
+	p.endpoint.mu.RUnlock()
+
+	if len(producers) == 0 {
+		e.SetError(fmt.Errorf("%w: %s", ErrNoConsumers, p.endpoint.uri))
+	}
+	return producers
+}
@@ -0,0 +1,77 @@
+package seda
+
+import (
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	camelUri "github.com/paveldanilin/go-camel/pkg/camel/uri"
+	"sync"
+)
+
+// Component provides asynchronous in-memory queues: 'seda:name?size=1000&concurrentConsumers=1&timeout=30s'.
+//
+// InOnly exchanges are queued and the producer returns immediately,
+// InOut exchanges are queued and the producer waits for the reply (up to timeout).
+//
+// With 'executorService=<profile>' consumers hand the exchanges over to the pool of the runtime executor profile.
+//
+// The endpoints of the same queue name share one queue regardless of their parameters,
+// e.g. 'seda:orders' sends to the route consuming from 'seda:orders?concurrentConsumers=4'.
+// The queue size is defined by the first endpoint, the other endpoints must not set a different size.
+type Component struct {
+	executorServiceProvider api.ExecutorServiceProvider
+
+	mu     sync.Mutex
+	queues map[string]chan *task // queue name -> queue
+}
+
+func NewComponent() *Component {
+	return &Component{
+		queues: map[string]chan *task{},
+	}
+}
+
+func (c *Component) Id() string {
+	return "seda"
+}
+
+func (c *Component) CreateEndpoint(uri string) (api.Endpoint, error) {
+	parsedUri, err := camelUri.Parse(uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := NewEndpoint(parsedUri)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.executorProfile != "" && c.executorServiceProvider == nil {
+		return nil, fmt.Errorf("seda: parameter '%s' requires the component to be registered in the runtime", EndpointParamExecutorService)
+	}
+	endpoint.executorServiceProvider = c.executorServiceProvider
+
+	if endpoint.queue, err = c.queue(endpoint); err != nil {
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+// queue returns the queue shared by the endpoints of the same queue name, the first endpoint defines the queue.
+func (c *Component) queue(endpoint *Endpoint) (chan *task, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if queue, exists := c.queues[endpoint.name]; exists {
+		if endpoint.uri.HasParam(EndpointParamSize) && endpoint.size != cap(queue) {
+			return nil, fmt.Errorf("seda: queue '%s' already exists with size %d, but got %s=%d",
+				endpoint.name, cap(queue), EndpointParamSize, endpoint.size)
+		}
+		return queue, nil
+	}
+	c.queues[endpoint.name] = endpoint.queue
+	return endpoint.queue, nil
+}
+
+func (c *Component) SetExecutorServiceProvider(p api.ExecutorServiceProvider) {
+	c.executorServiceProvider = p
+}
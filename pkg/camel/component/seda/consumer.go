@@ -0,0 +1,129 @@
+package seda
+
+import (
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"sync"
+)
+
+type Consumer struct {
+	mu         sync.Mutex
+	endpoint   *Endpoint
+	processors []api.Processor
+	running    bool
+	done       chan struct{}
+}
+
+// Start starts 'concurrentConsumers' workers that take exchanges from the endpoint queue.
+func (c *Consumer) Start() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.running {
+		return nil
+	}
+
+	c.done = make(chan struct{})
+	c.running = true
+
+	// Consumer can be restarted (stopped and started again), so workers must not refer to c.done
+	done := c.done
+	for i := 0; i < c.endpoint.concurrentConsumers; i++ {
+		go c.work(done)
+	}
+
+	return nil
+}
+
+// Stop signals workers to stop, the exchanges left in the queue are processed when the consumer is started again.
+// Stop does not wait for the workers: the worker processing an exchange finishes it and exits,
+// thus the consumer can be stopped from the worker itself (e.g. a route policy suspends the route).
+func (c *Consumer) Stop() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.running {
+		return nil
+	}
+	close(c.done)
+	c.running = false
+
+	return nil
+}
+
+// RemoveProcessor detaches the processor from the consumer, e.g. when the route is removed from the runtime.
+func (c *Consumer) RemoveProcessor(processor api.Processor) {
+	c.endpoint.mu.Lock()
+	defer c.endpoint.mu.Unlock()
+
+	processors := make([]api.Processor, 0, len(c.processors))
+	for _, p := range c.processors {
+		if p != processor {
+			processors = append(processors, p)
+		}
+	}
+	c.processors = processors
+}
+
+// AddProcessor attaches the processor to the consumer, e.g. when the suspended route is resumed.
+func (c *Consumer) AddProcessor(processor api.Processor) {
+	c.endpoint.mu.Lock()
+	defer c.endpoint.mu.Unlock()
+
+	c.processors = append(c.processors, processor)
+}
+
+// ProcessorCount returns the number of the processors (routes) the consumer passes the exchanges to.
+func (c *Consumer) ProcessorCount() int {
+	c.endpoint.mu.RLock()
+	defer c.endpoint.mu.RUnlock()
+
+	return len(c.processors)
+}
+
+func (c *Consumer) work(done chan struct{}) {
+	for {
+		// The stopped worker must not take the next exchange, even if the queue is not empty
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		select {
+		case <-done:
+			return
+		case t := <-c.endpoint.queue:
+			c.endpoint.mu.RLock()
+			processors := c.processors
+			c.endpoint.mu.RUnlock()
+
+			if c.endpoint.executorProfile == "" {
+				process(t, processors)
+				continue
+			}
+			if err := c.submit(t, processors); err != nil {
+				t.exchange.SetError(fmt.Errorf("seda: %w", err))
+				t.done()
+			}
+		}
+	}
+}
+
+// submit hands the task over to the pool of the endpoint executor profile.
+func (c *Consumer) submit(t *task, processors []api.Processor) error {
+	executorService, err := c.endpoint.executorServiceProvider.ExecutorService(c.endpoint.executorProfile)
+	if err != nil {
+		return err
+	}
+	return executorService.Submit(func() {
+		process(t, processors)
+	})
+}
+
+func process(t *task, processors []api.Processor) {
+	for _, processor := range processors {
+		processor.Process(t.exchange)
+	}
+	t.done()
+}
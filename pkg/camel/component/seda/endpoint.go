@@ -0,0 +1,112 @@
+package seda
+
+import (
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/uri"
+	"sync"
+	"time"
+)
+
+const (
+	EndpointParamSize                = "size"
+	EndpointParamConcurrentConsumers = "concurrentConsumers"
+	EndpointParamTimeout             = "timeout"
+	EndpointParamExecutorService     = "executorService"
+
+	defaultSize    = 1000
+	defaultTimeout = 30 * time.Second
+)
+
+// task is a queued exchange, done is called when the exchange is processed.
+type task struct {
+	exchange *exchange.Exchange
+	done     func()
+}
+
+type Endpoint struct {
+	mu       sync.RWMutex
+	uri      *uri.URI
+	consumer *Consumer
+	producer *Producer
+	queue    chan *task
+
+	name                string
+	size                int
+	concurrentConsumers int
+	timeout             time.Duration
+
+	executorProfile         string
+	executorServiceProvider api.ExecutorServiceProvider
+}
+
+func NewEndpoint(uri *uri.URI) (*Endpoint, error) {
+	size := defaultSize
+	if uri.HasParam(EndpointParamSize) {
+		paramSize, err := uri.ParamInt(EndpointParamSize)
+		if err != nil || paramSize <= 0 {
+			return nil, fmt.Errorf("seda: invalid parameter '%s': %s", EndpointParamSize, uri.MustParam(EndpointParamSize))
+		}
+		size = paramSize
+	}
+
+	concurrentConsumers := 1
+	if uri.HasParam(EndpointParamConcurrentConsumers) {
+		paramConsumers, err := uri.ParamInt(EndpointParamConcurrentConsumers)
+		if err != nil || paramConsumers <= 0 {
+			return nil, fmt.Errorf("seda: invalid parameter '%s': %s", EndpointParamConcurrentConsumers, uri.MustParam(EndpointParamConcurrentConsumers))
+		}
+		concurrentConsumers = paramConsumers
+	}
+
+	timeout := defaultTimeout
+	if uri.HasParam(EndpointParamTimeout) {
+		paramTimeout, err := time.ParseDuration(uri.MustParam(EndpointParamTimeout))
+		if err != nil {
+			return nil, fmt.Errorf("seda: invalid parameter '%s': %w", EndpointParamTimeout, err)
+		}
+		timeout = paramTimeout
+	}
+
+	return &Endpoint{
+		uri:                 uri,
+		queue:               make(chan *task, size),
+		name:                uri.Path(),
+		size:                size,
+		concurrentConsumers: concurrentConsumers,
+		timeout:             timeout,
+		executorProfile:     uri.ParamOrDef(EndpointParamExecutorService, ""),
+	}, nil
+}
+
+func (e *Endpoint) Uri() *uri.URI {
+	return e.uri
+}
+
+func (e *Endpoint) CreateConsumer(processor api.Processor) (api.Consumer, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.consumer == nil {
+		e.consumer = &Consumer{
+			endpoint:   e,
+			processors: []api.Processor{processor},
+		}
+	} else {
+		e.consumer.processors = append(e.consumer.processors, processor)
+	}
+
+	return e.consumer, nil
+}
+
+func (e *Endpoint) CreateProducer() (api.Producer, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.producer == nil {
+		e.producer = &Producer{endpoint: e}
+	}
+
+	return e.producer, nil
+}
@@ -0,0 +1,93 @@
+package seda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"sync"
+	"time"
+)
+
+// ErrQueueFull is set on exchanges that cannot be queued because the endpoint queue is full.
+var ErrQueueFull = errors.New("seda queue is full")
+
+// ErrReplyTimeout is set on InOut exchanges that were not processed within the endpoint timeout.
+var ErrReplyTimeout = errors.New("seda reply timeout")
+
+type Producer struct {
+	endpoint *Endpoint
+}
+
+// Process queues a copy of the exchange.
+// InOnly: returns immediately. InOut: waits for the reply and copies the processing result back to the exchange.
+func (p *Producer) Process(e *exchange.Exchange) {
+	api.AsyncProcessorFunc(p.ProcessAsync).Process(e)
+}
+
+// ProcessAsync queues a copy of the exchange.
+// InOnly: completes immediately. InOut: completes when the reply is received, the endpoint timeout elapsed or
+// the exchange context is done, no goroutine is parked while waiting for the reply.
+func (p *Producer) ProcessAsync(e *exchange.Exchange, done func()) {
+	t := &task{exchange: e.Copy()}
+
+	if !e.IsInOut() {
+		t.done = func() {}
+		p.enqueue(e, t)
+		done()
+		return
+	}
+
+	var (
+		// setupMu guards the completion until the timer and the context watcher are set up
+		setupMu             sync.Mutex
+		once                sync.Once
+		timer               *time.Timer
+		stopWatchingContext func() bool
+	)
+	complete := func(result func()) {
+		once.Do(func() {
+			setupMu.Lock()
+			setupMu.Unlock()
+
+			timer.Stop()
+			stopWatchingContext()
+			result()
+			done()
+		})
+	}
+
+	setupMu.Lock()
+	t.done = func() {
+		complete(func() {
+			e.CopyFrom(t.exchange)
+		})
+	}
+	timer = time.AfterFunc(p.endpoint.timeout, func() {
+		complete(func() {
+			e.SetError(fmt.Errorf("%w: %s", ErrReplyTimeout, p.endpoint.uri))
+		})
+	})
+	stopWatchingContext = context.AfterFunc(e.Context(), func() {
+		complete(func() {
+			e.SetError(e.Context().Err())
+		})
+	})
+	setupMu.Unlock()
+
+	if !p.enqueue(e, t) {
+		complete(func() {})
+	}
+}
+
+// enqueue puts the task into the endpoint queue, returns false and sets the exchange error if the queue is full.
+func (p *Producer) enqueue(e *exchange.Exchange, t *task) bool {
+	select {
+	case p.endpoint.queue <- t:
+		return true
+	default:
+		e.SetError(fmt.Errorf("%w: %s", ErrQueueFull, p.endpoint.uri))
+		return false
+	}
+}
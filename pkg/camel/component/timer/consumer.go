@@ -40,16 +40,22 @@ func (c *Consumer) Start() error {
 	c.done = make(chan struct{})
 	c.running = true
 
+	// Consumer can be restarted (stopped and started again), so the goroutine must not refer to c.done/c.ticker
+	done, ticker := c.done, c.ticker
+
 	go func() {
 		count := int64(0)
 
 		for {
 			select {
-			case <-c.done:
+			case <-done:
 				return
-			case t := <-c.ticker.C:
+			case t := <-ticker.C:
 				count++
-				for _, processor := range c.processors {
+				c.endpoint.mu.Lock()
+				processors := c.processors
+				c.endpoint.mu.Unlock()
+				for _, processor := range processors {
 					exchange := c.endpoint.component.exchangeFactory.NewExchange(nil)
 					exchange.Message().SetHeader(HeaderTimeFiredTime, t)
 					exchange.Message().SetHeader(HeaderTimerName, c.endpoint.name)
@@ -78,3 +84,33 @@ func (c *Consumer) Stop() error {
 
 	return nil
 }
+
+// RemoveProcessor detaches the processor from the consumer, e.g. when the route is removed from the runtime.
+func (c *Consumer) RemoveProcessor(processor api.Processor) {
+	c.endpoint.mu.Lock()
+	defer c.endpoint.mu.Unlock()
+
+	processors := make([]api.Processor, 0, len(c.processors))
+	for _, p := range c.processors {
+		if p != processor {
+			processors = append(processors, p)
+		}
+	}
+	c.processors = processors
+}
+
+// AddProcessor attaches the processor to the consumer, e.g. when the suspended route is resumed.
+func (c *Consumer) AddProcessor(processor api.Processor) {
+	c.endpoint.mu.Lock()
+	defer c.endpoint.mu.Unlock()
+
+	c.processors = append(c.processors, processor)
+}
+
+// ProcessorCount returns the number of the processors (routes) the consumer passes the exchanges to.
+func (c *Consumer) ProcessorCount() int {
+	c.endpoint.mu.RLock()
+	defer c.endpoint.mu.RUnlock()
+
+	return len(c.processors)
+}
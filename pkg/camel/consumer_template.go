@@ -0,0 +1,184 @@
+package camel
+
+import (
+	"errors"
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/uri"
+	"sync"
+	"time"
+)
+
+// ErrReceiveTimeout is returned by ConsumerTemplate if no exchange was received within the timeout.
+var ErrReceiveTimeout = errors.New("receive timeout")
+
+// ConsumerTemplateQueueSize is the number of exchanges buffered per endpoint
+// for endpoints that do not support polling consumers natively.
+const ConsumerTemplateQueueSize = 100
+
+// ConsumerTemplate receives exchanges from endpoints in the application code.
+// Endpoints that do not implement api.PollingEndpoint are polled by means of an event-driven consumer that buffers
+// received exchanges. Polling consumers are created on the first Receive and kept until Stop.
+type ConsumerTemplate struct {
+	rt        *Runtime
+	mu        sync.Mutex
+	consumers map[string]api.PollingConsumer
+}
+
+// NewConsumerTemplate creates a new ConsumerTemplate bound to the runtime.
+func (rt *Runtime) NewConsumerTemplate() *ConsumerTemplate {
+	return &ConsumerTemplate{
+		rt:        rt,
+		consumers: map[string]api.PollingConsumer{},
+	}
+}
+
+// Receive waits for the next exchange from the endpoint up to timeout, returns ErrReceiveTimeout on timeout.
+func (ct *ConsumerTemplate) Receive(uri string, timeout time.Duration) (*exchange.Exchange, error) {
+	consumer, err := ct.consumer(uri)
+	if err != nil {
+		return nil, err
+	}
+	return consumer.Receive(timeout)
+}
+
+// ReceiveNoWait returns the exchange if it is immediately available, otherwise returns ErrReceiveTimeout.
+func (ct *ConsumerTemplate) ReceiveNoWait(uri string) (*exchange.Exchange, error) {
+	return ct.Receive(uri, 0)
+}
+
+// ReceiveBody waits for the next exchange from the endpoint and returns its body.
+func (ct *ConsumerTemplate) ReceiveBody(uri string, timeout time.Duration) (any, error) {
+	e, err := ct.Receive(uri, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return e.Message().Body, nil
+}
+
+// Stop stops all polling consumers created by the template.
+func (ct *ConsumerTemplate) Stop() error {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	var errs []error
+	for key, consumer := range ct.consumers {
+		if err := consumer.Stop(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(ct.consumers, key)
+	}
+	return errors.Join(errs...)
+}
+
+func (ct *ConsumerTemplate) consumer(rawUri string) (api.PollingConsumer, error) {
+	key, err := uri.Normalize(rawUri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URI '%s': %w", rawUri, err)
+	}
+
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	if consumer, exists := ct.consumers[key]; exists {
+		return consumer, nil
+	}
+
+	endpoint, err := ct.rt.ResolveEndpoint(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var consumer api.PollingConsumer
+	if pollingEndpoint, isPolling := endpoint.(api.PollingEndpoint); isPolling {
+		consumer, err = pollingEndpoint.CreatePollingConsumer()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create polling consumer for URI '%s': %w", rawUri, err)
+		}
+	} else {
+		consumer = newEventDrivenPollingConsumer(ct.rt, endpoint, ConsumerTemplateQueueSize)
+	}
+
+	if err := consumer.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start polling consumer for URI '%s': %w", rawUri, err)
+	}
+	ct.consumers[key] = consumer
+
+	return consumer, nil
+}
+
+// eventDrivenPollingConsumer adapts an event-driven endpoint consumer to api.PollingConsumer.
+type eventDrivenPollingConsumer struct {
+	rt       *Runtime
+	endpoint api.Endpoint
+	queue    chan *exchange.Exchange
+	consumer api.Consumer
+}
+
+func newEventDrivenPollingConsumer(rt *Runtime, endpoint api.Endpoint, queueSize int) *eventDrivenPollingConsumer {
+	return &eventDrivenPollingConsumer{
+		rt:       rt,
+		endpoint: endpoint,
+		queue:    make(chan *exchange.Exchange, queueSize),
+	}
+}
+
+// Process is called by the endpoint consumer, the exchange is rejected if the queue is full.
+func (c *eventDrivenPollingConsumer) Process(e *exchange.Exchange) {
+	select {
+	case c.queue <- e:
+	default:
+		e.SetError(fmt.Errorf("polling consumer queue is full: %s", c.endpoint.Uri()))
+	}
+}
+
+func (c *eventDrivenPollingConsumer) Start() error {
+	consumer, err := c.endpoint.CreateConsumer(c)
+	if err != nil {
+		return err
+	}
+	if err := consumer.Start(); err != nil {
+		return err
+	}
+	c.consumer = consumer
+	return nil
+}
+
+// Stop detaches from the endpoint consumer, the consumer is stopped unless it is used by a route.
+func (c *eventDrivenPollingConsumer) Stop() error {
+	if c.consumer == nil {
+		return nil
+	}
+	consumer := c.consumer
+	c.consumer = nil
+
+	if remover, canRemove := consumer.(processorRemover); canRemove {
+		remover.RemoveProcessor(c)
+	}
+	if c.rt.isConsumerInUse(consumer) {
+		return nil
+	}
+	return consumer.Stop()
+}
+
+func (c *eventDrivenPollingConsumer) Receive(timeout time.Duration) (*exchange.Exchange, error) {
+	if timeout <= 0 {
+		select {
+		case e := <-c.queue:
+			return e, nil
+		default:
+			return nil, ErrReceiveTimeout
+		}
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case e := <-c.queue:
+		return e, nil
+	case <-timer.C:
+		return nil, ErrReceiveTimeout
+	}
+}
@@ -1,5 +1,12 @@
 package errs
 
+import (
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"reflect"
+	"strings"
+)
+
 type MatchMode string
 
 const (
@@ -7,17 +14,100 @@ const (
 	MatchModeContains MatchMode = "contains"
 	MatchModeRegex    MatchMode = "regex"
 	MatchModeIs       MatchMode = "is"
+	// MatchModeAs matches the error by Go type (see errors.As).
+	MatchModeAs MatchMode = "as"
+	// MatchModePredicate matches the error if the predicate is true for the failed exchange.
+	MatchModePredicate MatchMode = "predicate"
+	MatchModeAnyOf     MatchMode = "anyOf"
+	MatchModeAllOf     MatchMode = "allOf"
+	MatchModeNot       MatchMode = "not"
 )
 
 type Matcher struct {
 	MatchMode MatchMode
 	Target    string
+	// Err is the target of MatchModeIs (nil - the error messages of the unwrap chain are compared with Target).
+	Err error
+	// Type is the target type of MatchModeAs.
+	Type reflect.Type
+	// Predicate is the expression of MatchModePredicate, it is evaluated against the failed exchange.
+	Predicate expr.Definition
+	// Matchers are the operands of MatchModeAnyOf, MatchModeAllOf and MatchModeNot.
+	Matchers []Matcher
+}
+
+// IsAny returns TRUE if the matcher matches any error.
+func (m Matcher) IsAny() bool {
+	switch m.MatchMode {
+	case "", MatchModeEquals, MatchModeContains, MatchModeRegex:
+		return strings.TrimSpace(m.Target) == "*" || strings.TrimSpace(m.Target) == ""
+	}
+	return false
+}
+
+func (m Matcher) String() string {
+	switch m.MatchMode {
+	case MatchModePredicate:
+		return fmt.Sprintf("%s:%v", m.MatchMode, m.Predicate.Expression)
+	case MatchModeAnyOf, MatchModeAllOf, MatchModeNot:
+		operands := make([]string, len(m.Matchers))
+		for i, operand := range m.Matchers {
+			operands[i] = operand.String()
+		}
+		return fmt.Sprintf("%s(%s)", m.MatchMode, strings.Join(operands, ","))
+	}
+	return fmt.Sprintf("%s:%s", m.MatchMode, m.Target)
 }
 
+// Is matches the errors that wrap the target (see errors.Is).
 func Is(target error) Matcher {
 	return Matcher{
 		MatchMode: MatchModeIs,
 		Target:    target.Error(),
+		Err:       target,
+	}
+}
+
+// As matches the errors that wrap an error of type T (see errors.As), e.g. errs.As[*MyErr]().
+func As[T error]() Matcher {
+	t := reflect.TypeFor[T]()
+	return Matcher{
+		MatchMode: MatchModeAs,
+		Target:    t.String(),
+		Type:      t,
+	}
+}
+
+// Predicate matches the error if the predicate is true for the failed exchange,
+// the predicate can refer to the error by means of the 'error' variable.
+func Predicate(predicate expr.Definition) Matcher {
+	return Matcher{
+		MatchMode: MatchModePredicate,
+		Predicate: predicate,
+	}
+}
+
+// AnyOf matches the error if at least one of the matchers matches it.
+func AnyOf(matchers ...Matcher) Matcher {
+	return Matcher{
+		MatchMode: MatchModeAnyOf,
+		Matchers:  matchers,
+	}
+}
+
+// AllOf matches the error if all the matchers match it.
+func AllOf(matchers ...Matcher) Matcher {
+	return Matcher{
+		MatchMode: MatchModeAllOf,
+		Matchers:  matchers,
+	}
+}
+
+// Not matches the error if the matcher does not match it.
+func Not(matcher Matcher) Matcher {
+	return Matcher{
+		MatchMode: MatchModeNot,
+		Matchers:  []Matcher{matcher},
 	}
 }
 
@@ -48,3 +138,20 @@ func Matches(pattern string) Matcher {
 		Target:    pattern,
 	}
 }
+
+// Chain returns the error followed by the errors it wraps, depth-first (see errors.Unwrap).
+func Chain(err error) []error {
+	if err == nil {
+		return nil
+	}
+	chain := []error{err}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		chain = append(chain, Chain(u.Unwrap())...)
+	case interface{ Unwrap() []error }:
+		for _, wrapped := range u.Unwrap() {
+			chain = append(chain, Chain(wrapped)...)
+		}
+	}
+	return chain
+}
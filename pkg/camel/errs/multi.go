@@ -0,0 +1,27 @@
+package errs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MultiError combines errors of several outputs (e.g. multicast outputs).
+// errors.Is and errors.As match any of the combined errors.
+type MultiError struct {
+	Errors []error
+}
+
+func (e *MultiError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d errors occurred: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
@@ -0,0 +1,44 @@
+package errs
+
+import (
+	"fmt"
+)
+
+// PanicError is the error a recovered panic is turned into, it keeps the stack trace of the panic.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error (e.g. panic(err)).
+func (e *PanicError) Unwrap() error {
+	if err, isErr := e.Value.(error); isErr {
+		return err
+	}
+	return nil
+}
+
+// StepError records where the exchange failed, it is exposed to the simple expressions as 'exception'
+// (e.g. exception.step, exception.stack). Error returns the message of the wrapped error as is.
+type StepError struct {
+	RouteName string `expr:"route"`
+	StepName  string `expr:"step"`
+	// StepPath is the path of the step in the route, e.g. 'order/choice[1]/when[0]/to[0]'.
+	StepPath   string `expr:"path"`
+	ExchangeId string `expr:"exchangeId"`
+	// Stack is the stack trace of the panic (empty if the step did not panic).
+	Stack string `expr:"stack"`
+	Err   error  `expr:"cause"`
+}
+
+func (e *StepError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
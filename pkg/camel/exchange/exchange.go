@@ -6,13 +6,51 @@ import (
 	"time"
 )
 
+// CamelPropertyMulticastIndex holds the index of the multicast output the exchange copy is sent to.
+const CamelPropertyMulticastIndex = "CAMEL_MULTICAST_INDEX"
+
+// CamelPropertyAggregatedSize holds the number of the exchanges aggregated into the exchange (see aggregator.CompleteAfter).
+const CamelPropertyAggregatedSize = "CAMEL_AGGREGATED_SIZE"
+
+const (
+	// CamelPropertyRouteStop is TRUE if the routing of the exchange is stopped, the remaining steps are skipped.
+	CamelPropertyRouteStop = "CAMEL_ROUTE_STOP"
+	// CamelPropertyErrorHandled is TRUE if the error is handled by the error handler (e.g. dead letter channel).
+	CamelPropertyErrorHandled = "CAMEL_ERROR_HANDLED"
+	// CamelPropertyExceptionCaught holds the error caught by the error handler.
+	CamelPropertyExceptionCaught = "CAMEL_EXCEPTION_CAUGHT"
+	// CamelPropertyExhaustedError holds the error the error handler gave up on, the enclosing error handlers pass it through.
+	CamelPropertyExhaustedError = "CAMEL_EXHAUSTED_ERROR"
+	// CamelPropertyOriginalMessage holds the copy of the message as it was received by the first route.
+	CamelPropertyOriginalMessage = "CAMEL_ORIGINAL_MESSAGE"
+	// CamelPropertyInterceptedEndpoint holds the URI of the endpoint the exchange is intercepted on the way to.
+	CamelPropertyInterceptedEndpoint = "CAMEL_INTERCEPTED_ENDPOINT"
+)
+
+// ExchangePattern defines whether the exchange expects a reply.
+type ExchangePattern string
+
+const (
+	// InOnly is a one-way (fire-and-forget) message exchange.
+	InOnly ExchangePattern = "InOnly"
+	// InOut is a request-reply message exchange.
+	InOut ExchangePattern = "InOut"
+)
+
 type Exchange struct {
 	id    string
 	start time.Time
 
+	pattern    ExchangePattern
 	properties Map
 	message    *Message
+	out        *Message
 	err        error
+	// errorHistory holds the errors caught before (see AddErrorHistory)
+	errorHistory []error
+	// unitOfWork is TRUE while the exchange is processed by the route that received it first
+	unitOfWork    bool
+	onCompletions []func(e *Exchange)
 
 	ctx         context.Context
 	cancel      context.CancelFunc
@@ -29,6 +67,7 @@ func NewExchange(c context.Context) *Exchange {
 	e := &Exchange{
 		id:         uuid.NewString(),
 		start:      time.Now(),
+		pattern:    InOnly,
 		properties: newMap(),
 		message:    NewMessage(),
 		ctx:        ctx,
@@ -113,6 +152,92 @@ func (e *Exchange) Message() *Message {
 	return e.message
 }
 
+func (e *Exchange) SetMessage(m *Message) {
+	e.message = m
+}
+
+// StopRoute stops the routing of the exchange, the remaining steps of the route (and calling routes) are skipped.
+func (e *Exchange) StopRoute() {
+	e.properties.Set(CamelPropertyRouteStop, true)
+}
+
+func (e *Exchange) IsRouteStopped() bool {
+	stop, _ := e.properties.Get(CamelPropertyRouteStop)
+	return stop == true
+}
+
+// AddOnCompletion registers the func called once the exchange is done by the route that received it first
+// (e.g. a consumer commits or rolls back the consumed resource depending on e.IsError()).
+// The funcs are called in the registration order, the copies of the exchange do not inherit them.
+func (e *Exchange) AddOnCompletion(fn func(e *Exchange)) {
+	e.onCompletions = append(e.onCompletions, fn)
+}
+
+// BeginUnitOfWork marks the exchange as being processed by a route, returns FALSE if it is already marked
+// (e.g. the exchange is sent to a nested route).
+func (e *Exchange) BeginUnitOfWork() bool {
+	if e.unitOfWork {
+		return false
+	}
+	e.unitOfWork = true
+	return true
+}
+
+// DoneUnitOfWork calls the funcs registered by AddOnCompletion once, and clears the mark set by BeginUnitOfWork.
+func (e *Exchange) DoneUnitOfWork() {
+	onCompletions := e.onCompletions
+	e.onCompletions = nil
+	e.unitOfWork = false
+	for _, fn := range onCompletions {
+		fn(e)
+	}
+}
+
+func (e *Exchange) Pattern() ExchangePattern {
+	return e.pattern
+}
+
+func (e *Exchange) SetPattern(pattern ExchangePattern) {
+	e.pattern = pattern
+}
+
+func (e *Exchange) IsInOut() bool {
+	return e.pattern == InOut
+}
+
+// Out returns the reply message, the message is created on the first call.
+func (e *Exchange) Out() *Message {
+	if e.out == nil {
+		e.out = NewMessage()
+	}
+	return e.out
+}
+
+func (e *Exchange) HasOut() bool {
+	return e.out != nil
+}
+
+func (e *Exchange) SetOut(m *Message) {
+	e.out = m
+}
+
+// Reply returns the out message if it is set, otherwise the (processed) in message.
+func (e *Exchange) Reply() *Message {
+	if e.out != nil {
+		return e.out
+	}
+	return e.message
+}
+
+// CopyFrom replaces the message, the out message, properties and error with the ones of the source exchange.
+// Exchange id, pattern and context are kept.
+func (e *Exchange) CopyFrom(source *Exchange) {
+	e.message = source.message
+	e.out = source.out
+	e.properties = source.properties
+	e.err = source.err
+}
+
 func (e *Exchange) StartedAt() time.Time {
 	return e.start
 }
@@ -129,6 +254,18 @@ func (e *Exchange) SetError(err error) {
 	e.err = err
 }
 
+// AddErrorHistory records the error caught by a catch block or an error handler.
+func (e *Exchange) AddErrorHistory(err error) {
+	if err != nil {
+		e.errorHistory = append(e.errorHistory, err)
+	}
+}
+
+// ErrorHistory returns the caught errors, the earliest goes first.
+func (e *Exchange) ErrorHistory() []error {
+	return e.errorHistory
+}
+
 func (e *Exchange) Copy() *Exchange {
 	if e == nil {
 		return nil
@@ -144,16 +281,37 @@ func (e *Exchange) Copy() *Exchange {
 	}
 
 	return &Exchange{
-		id:          uuid.NewString(),
-		properties:  propsCopy,
-		start:       e.start,
-		message:     msgCopy,
-		err:         e.err,
-		ctx:         e.ctx,
-		cancel:      e.cancel,
-		hasDeadline: e.hasDeadline,
-		deadline:    e.deadline,
+		id:           uuid.NewString(),
+		pattern:      e.pattern,
+		properties:   propsCopy,
+		start:        e.start,
+		message:      msgCopy,
+		out:          e.out.Copy(),
+		err:          e.err,
+		errorHistory: append([]error(nil), e.errorHistory...),
+		ctx:          e.ctx,
+		cancel:       e.cancel,
+		hasDeadline:  e.hasDeadline,
+		deadline:     e.deadline,
+	}
+}
+
+// CopyWithContext returns the copy of the exchange bound to the cancelable child of the given context.
+// Cancelling the copy (or the given context) does not affect the original exchange.
+func (e *Exchange) CopyWithContext(c context.Context) *Exchange {
+	cp := e.Copy()
+	if cp == nil {
+		return nil
+	}
+
+	cp.ctx, cp.cancel = context.WithCancel(c)
+	cp.hasDeadline = false
+	cp.deadline = time.Time{}
+	if dl, ok := c.Deadline(); ok {
+		cp.hasDeadline = true
+		cp.deadline = dl
 	}
+	return cp
 }
 
 // AsMap returns Exchange's data as map.
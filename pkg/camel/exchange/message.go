@@ -12,6 +12,29 @@ import (
 
 const CamelHeaderMessageHistory = "CAMEL_HEADER_MESSAGE_HISTORY"
 
+const (
+	// CamelHeaderRedelivered is TRUE if the message is redelivered by the error handler.
+	CamelHeaderRedelivered = "CAMEL_REDELIVERED"
+	// CamelHeaderRedeliveryCounter holds the number of the current redelivery (starts with 1).
+	CamelHeaderRedeliveryCounter = "CAMEL_REDELIVERY_COUNTER"
+	// CamelHeaderRedeliveryMaxCounter holds the max number of redeliveries (absent if unlimited).
+	CamelHeaderRedeliveryMaxCounter = "CAMEL_REDELIVERY_MAX_COUNTER"
+)
+
+const (
+	// CamelHeaderFailureRoute holds the name of the route where the exchange failed (set by the dead letter channel).
+	CamelHeaderFailureRoute = "CAMEL_FAILURE_ROUTE"
+	// CamelHeaderFailureStep holds the name of the failed step.
+	CamelHeaderFailureStep = "CAMEL_FAILURE_STEP"
+	// CamelHeaderFailureError holds the error text.
+	CamelHeaderFailureError = "CAMEL_FAILURE_ERROR"
+	// CamelHeaderFailureTime holds the time.Time of the failure.
+	CamelHeaderFailureTime = "CAMEL_FAILURE_TIME"
+)
+
+// CamelHeaderSagaId holds the id of the saga the exchange participates in.
+const CamelHeaderSagaId = "CAMEL_SAGA_ID"
+
 type Message struct {
 	id      string
 	headers Map
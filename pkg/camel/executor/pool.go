@@ -0,0 +1,167 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// RejectionPolicy defines what happens to the task that cannot be queued because the pool queue is full.
+type RejectionPolicy string
+
+const (
+	// RejectAbort rejects the task with ErrRejected.
+	RejectAbort RejectionPolicy = "abort"
+	// RejectCallerRuns runs the task in the caller goroutine, thus slows down the caller.
+	RejectCallerRuns RejectionPolicy = "callerRuns"
+	// RejectBlock blocks the caller until the task is queued.
+	RejectBlock RejectionPolicy = "block"
+)
+
+// ErrRejected is returned by Submit if the task is rejected by the RejectAbort policy.
+var ErrRejected = errors.New("task rejected, executor queue is full")
+
+// ErrShutdown is returned by Submit if the pool is shut down.
+var ErrShutdown = errors.New("executor is shut down")
+
+// Profile describes the pool of goroutines.
+type Profile struct {
+	Name string
+	// MaxConcurrency is the max number of tasks executed at the same time.
+	MaxConcurrency int
+	// QueueSize is the number of tasks waiting for a free goroutine, zero means no queue.
+	QueueSize       int
+	RejectionPolicy RejectionPolicy
+}
+
+func (p Profile) validate() error {
+	if p.Name == "" {
+		return errors.New("executor profile name must be not empty string")
+	}
+	if p.MaxConcurrency <= 0 {
+		return fmt.Errorf("executor profile '%s': max concurrency must be greater than zero", p.Name)
+	}
+	if p.QueueSize < 0 {
+		return fmt.Errorf("executor profile '%s': queue size must be not negative", p.Name)
+	}
+	switch p.RejectionPolicy {
+	case RejectAbort, RejectCallerRuns, RejectBlock:
+	default:
+		return fmt.Errorf("executor profile '%s': unknown rejection policy: '%s'", p.Name, p.RejectionPolicy)
+	}
+	return nil
+}
+
+// Pool executes tasks by means of a fixed number of goroutines (Profile.MaxConcurrency).
+type Pool struct {
+	profile Profile
+	tasks   chan func()
+
+	mu       sync.RWMutex
+	shutdown bool
+	once     sync.Once
+	// closing unblocks callers waiting for a free queue slot (RejectBlock)
+	closing chan struct{}
+	// stopped tells workers to execute the queued tasks and exit
+	stopped chan struct{}
+	wg      sync.WaitGroup
+}
+
+func NewPool(profile Profile) (*Pool, error) {
+	if err := profile.validate(); err != nil {
+		return nil, err
+	}
+
+	p := &Pool{
+		profile: profile,
+		tasks:   make(chan func(), profile.QueueSize),
+		closing: make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	for i := 0; i < profile.MaxConcurrency; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+	return p, nil
+}
+
+func (p *Pool) Profile() Profile {
+	return p.profile
+}
+
+// Submit queues the task, if the queue is full the task is handled according to the rejection policy.
+func (p *Pool) Submit(task func()) error {
+	queued, err := p.enqueue(task)
+	if err != nil {
+		return err
+	}
+	if !queued {
+		// RejectCallerRuns
+		task()
+	}
+	return nil
+}
+
+// enqueue returns false if the task must be executed by the caller.
+func (p *Pool) enqueue(task func()) (bool, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.shutdown {
+		return false, ErrShutdown
+	}
+
+	select {
+	case p.tasks <- task:
+		return true, nil
+	default:
+	}
+
+	switch p.profile.RejectionPolicy {
+	case RejectCallerRuns:
+		return false, nil
+	case RejectBlock:
+		select {
+		case p.tasks <- task:
+			return true, nil
+		case <-p.closing:
+			return false, ErrShutdown
+		}
+	default:
+		return false, fmt.Errorf("%w: %s", ErrRejected, p.profile.Name)
+	}
+}
+
+// Shutdown stops accepting new tasks and waits until the queued tasks are executed.
+func (p *Pool) Shutdown() {
+	p.once.Do(func() {
+		close(p.closing)
+
+		p.mu.Lock()
+		p.shutdown = true
+		p.mu.Unlock()
+
+		close(p.stopped)
+		p.wg.Wait()
+	})
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case task := <-p.tasks:
+			task()
+		case <-p.stopped:
+			for {
+				select {
+				case task := <-p.tasks:
+					task()
+				default:
+					return
+				}
+			}
+		}
+	}
+}
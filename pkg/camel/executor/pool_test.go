@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool_MaxConcurrency(t *testing.T) {
+	pool, err := NewPool(Profile{Name: "test", MaxConcurrency: 2, QueueSize: 10, RejectionPolicy: RejectAbort})
+	if err != nil {
+		t.Fatalf("TestPool_MaxConcurrency(): failed to create pool: %s", err)
+	}
+	defer pool.Shutdown()
+
+	var running, maxRunning atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		err := pool.Submit(func() {
+			defer wg.Done()
+			n := running.Add(1)
+			for {
+				current := maxRunning.Load()
+				if n <= current || maxRunning.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+		})
+		if err != nil {
+			t.Fatalf("TestPool_MaxConcurrency(): failed to submit task: %s", err)
+		}
+	}
+	wg.Wait()
+
+	if maxRunning.Load() != 2 {
+		t.Fatalf("TestPool_MaxConcurrency() = %d concurrent tasks; want %d", maxRunning.Load(), 2)
+	}
+}
+
+func TestPool_RejectionPolicy(t *testing.T) {
+	release := make(chan struct{})
+
+	abortPool, _ := NewPool(Profile{Name: "abort", MaxConcurrency: 1, QueueSize: 0, RejectionPolicy: RejectAbort})
+	callerRunsPool, _ := NewPool(Profile{Name: "callerRuns", MaxConcurrency: 1, QueueSize: 0, RejectionPolicy: RejectCallerRuns})
+	defer abortPool.Shutdown()
+	defer callerRunsPool.Shutdown()
+	defer close(release)
+
+	// let workers start, then occupy the only worker of each pool (there is no queue)
+	time.Sleep(10 * time.Millisecond)
+	for _, pool := range []*Pool{abortPool, callerRunsPool} {
+		started := make(chan struct{})
+		go pool.Submit(func() {
+			close(started)
+			<-release
+		})
+		<-started
+	}
+
+	if err := abortPool.Submit(func() {}); !errors.Is(err, ErrRejected) {
+		t.Fatalf("TestPool_RejectionPolicy(): expected ErrRejected, but got %v", err)
+	}
+
+	ranByCaller := false
+	if err := callerRunsPool.Submit(func() { ranByCaller = true }); err != nil {
+		t.Fatalf("TestPool_RejectionPolicy(): unexpected error: %s", err)
+	}
+	if !ranByCaller {
+		t.Fatalf("TestPool_RejectionPolicy(): expected task to be run by the caller")
+	}
+}
+
+func TestPool_Shutdown(t *testing.T) {
+	pool, _ := NewPool(Profile{Name: "test", MaxConcurrency: 1, QueueSize: 10, RejectionPolicy: RejectBlock})
+
+	var executed atomic.Int64
+	for i := 0; i < 5; i++ {
+		if err := pool.Submit(func() { executed.Add(1) }); err != nil {
+			t.Fatalf("TestPool_Shutdown(): failed to submit task: %s", err)
+		}
+	}
+	pool.Shutdown()
+
+	if executed.Load() != 5 {
+		t.Fatalf("TestPool_Shutdown() = %d executed tasks; want %d", executed.Load(), 5)
+	}
+	if err := pool.Submit(func() {}); !errors.Is(err, ErrShutdown) {
+		t.Fatalf("TestPool_Shutdown(): expected ErrShutdown, but got %v", err)
+	}
+}
+
+func TestRegistry_DefaultProfile(t *testing.T) {
+	r := NewRegistry()
+
+	if profile, exists := r.Profile(DefaultProfile); !exists || profile != NewDefaultProfile() {
+		t.Fatalf("TestRegistry_DefaultProfile(): expected built-in default profile, but got %v", profile)
+	}
+	if err := r.RegisterProfile(Profile{Name: DefaultProfile, MaxConcurrency: 4, RejectionPolicy: RejectBlock}); err != nil {
+		t.Fatalf("TestRegistry_DefaultProfile(): failed to override default profile: %s", err)
+	}
+	if profile, _ := r.Profile(DefaultProfile); profile.MaxConcurrency != 4 {
+		t.Fatalf("TestRegistry_DefaultProfile(): expected overridden default profile, but got %v", profile)
+	}
+	if _, err := r.ExecutorService("unknown"); err == nil {
+		t.Fatalf("TestRegistry_DefaultProfile(): expected error for unknown profile")
+	}
+	if err := r.RegisterProfile(Profile{Name: "bad", MaxConcurrency: 0, RejectionPolicy: RejectAbort}); err == nil {
+		t.Fatalf("TestRegistry_DefaultProfile(): expected error for invalid profile")
+	}
+}
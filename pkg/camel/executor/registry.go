@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"sync"
+)
+
+// DefaultProfile is the name of the profile that is used if no profile is registered under this name.
+const DefaultProfile = "default"
+
+// NewDefaultProfile returns the profile used by DefaultProfile unless it is overridden.
+func NewDefaultProfile() Profile {
+	return Profile{
+		Name:            DefaultProfile,
+		MaxConcurrency:  20,
+		QueueSize:       1000,
+		RejectionPolicy: RejectCallerRuns,
+	}
+}
+
+type registry struct {
+	mu       sync.Mutex
+	profiles map[string]Profile
+	pools    map[string]*Pool
+}
+
+func NewRegistry() *registry {
+	return &registry{
+		profiles: map[string]Profile{},
+		pools:    map[string]*Pool{},
+	}
+}
+
+func (r *registry) RegisterProfile(profile Profile) error {
+	if err := profile.validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.profiles[profile.Name]; exists {
+		return fmt.Errorf("executor profile already registered: %s", profile.Name)
+	}
+	r.profiles[profile.Name] = profile
+	return nil
+}
+
+func (r *registry) Profile(name string) (Profile, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.profile(name)
+}
+
+// ExecutorService returns the pool of the profile, the pool is created on the first call.
+func (r *registry) ExecutorService(name string) (api.ExecutorService, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if pool, exists := r.pools[name]; exists {
+		return pool, nil
+	}
+
+	profile, exists := r.profile(name)
+	if !exists {
+		return nil, fmt.Errorf("executor profile not found: %s", name)
+	}
+	pool, err := NewPool(profile)
+	if err != nil {
+		return nil, err
+	}
+	r.pools[name] = pool
+	return pool, nil
+}
+
+// Shutdown shuts down all created pools, pools are created again on demand.
+func (r *registry) Shutdown() {
+	r.mu.Lock()
+	pools := r.pools
+	r.pools = map[string]*Pool{}
+	r.mu.Unlock()
+
+	for _, pool := range pools {
+		pool.Shutdown()
+	}
+}
+
+func (r *registry) profile(name string) (Profile, bool) {
+	if profile, exists := r.profiles[name]; exists {
+		return profile, true
+	}
+	if name == DefaultProfile {
+		return NewDefaultProfile(), true
+	}
+	return Profile{}, false
+}
@@ -1,6 +1,9 @@
 package expr
 
-import "github.com/paveldanilin/go-camel/pkg/camel/exchange"
+import (
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+)
 
 type Kind string
 
@@ -8,6 +11,7 @@ const (
 	SimpleKind   Kind = "simple"
 	ConstantKind      = "constant"
 	FuncKind          = "func"
+	JSONPathKind      = "jsonpath"
 )
 
 type Definition struct {
@@ -15,6 +19,25 @@ type Definition struct {
 	Expression any
 }
 
+// String returns the kind and the human-readable form of the expression, e.g. 'simple:body > 10'.
+// The inline func is shown as 'func', the func reference as 'func:beanName'.
+func (d Definition) String() string {
+	switch d.Kind {
+	case SimpleKind:
+		return fmt.Sprintf("%s:%v", d.Kind, d.Expression)
+	case JSONPathKind:
+		if jsonPathExpr, isJSONPathExpr := d.Expression.(JSONPathExpression); isJSONPathExpr {
+			return fmt.Sprintf("%s:%s", d.Kind, jsonPathExpr.Path)
+		}
+	case FuncKind:
+		if beanName, isRef := d.Expression.(string); isRef {
+			return fmt.Sprintf("%s:%s", d.Kind, beanName)
+		}
+		return string(d.Kind)
+	}
+	return fmt.Sprintf("%s:%v", d.Kind, d.Expression)
+}
+
 func Simple(expression string) Definition {
 	return Definition{
 		Kind:       SimpleKind,
@@ -35,3 +58,53 @@ func Func(fn func(e *exchange.Exchange) (any, error)) Definition {
 		Expression: fn,
 	}
 }
+
+// FuncRef refers to a func registered as a bean (see Runtime.RegisterBean).
+// The bean must have type 'func(e *exchange.Exchange) (any, error)'.
+func FuncRef(beanName string) Definition {
+	return Definition{
+		Kind:       FuncKind,
+		Expression: beanName,
+	}
+}
+
+// JSONPathResult defines the result of the JSONPath expression.
+type JSONPathResult string
+
+const (
+	// JSONPathResultAuto returns the value for the definite path (e.g. $.order.id) and the list of values otherwise.
+	JSONPathResultAuto JSONPathResult = ""
+	// JSONPathResultSingle returns the first matched value.
+	JSONPathResultSingle JSONPathResult = "single"
+	// JSONPathResultList returns the list of the matched values.
+	JSONPathResultList JSONPathResult = "list"
+)
+
+type JSONPathOptions struct {
+	// SuppressExceptions returns nil (or the empty list) instead of the error if the path is not found.
+	SuppressExceptions bool
+	Result             JSONPathResult
+}
+
+// JSONPathExpression is the Expression of the JSONPath definition.
+type JSONPathExpression struct {
+	Path string
+	JSONPathOptions
+}
+
+// JSONPath is evaluated against the Message body: JSON ([]byte, string) or already unmarshalled value (maps, structs),
+// e.g. expr.JSONPath("$.order.lines[?(@.qty > 1)].sku").
+// Used as a predicate (e.g. Choice.When) it is TRUE if the path matches anything (or refers to TRUE).
+func JSONPath(path string) Definition {
+	return JSONPathWithOptions(path, JSONPathOptions{})
+}
+
+func JSONPathWithOptions(path string, options JSONPathOptions) Definition {
+	return Definition{
+		Kind: JSONPathKind,
+		Expression: JSONPathExpression{
+			Path:            path,
+			JSONPathOptions: options,
+		},
+	}
+}
@@ -0,0 +1,166 @@
+package policy
+
+import (
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"sync"
+	"time"
+)
+
+const defaultScheduledCheckInterval = time.Minute
+
+// Scheduled keeps the route active only within a daily time window [from, to).
+// Outside the window the route is suspended. A window that crosses midnight (e.g. 22:00-06:00) is supported.
+// Within the window only the routes suspended by the policy are resumed, so it can be combined with other policies.
+type Scheduled struct {
+	mu            sync.Mutex
+	from          time.Duration // offset from midnight
+	to            time.Duration // offset from midnight
+	location      *time.Location
+	checkInterval time.Duration
+	now           func() time.Time
+	stop          map[string]chan struct{}
+	suspended     map[string]bool // routes suspended by this policy
+	onError       func(r api.RouteController, err error)
+}
+
+// NewScheduled creates Scheduled policy, from and to must be in format "HH:MM" or "HH:MM:SS".
+func NewScheduled(from, to string) (*Scheduled, error) {
+	fromOffset, err := parseTimeOfDay(from)
+	if err != nil {
+		return nil, fmt.Errorf("policy: scheduled: invalid 'from': %w", err)
+	}
+	toOffset, err := parseTimeOfDay(to)
+	if err != nil {
+		return nil, fmt.Errorf("policy: scheduled: invalid 'to': %w", err)
+	}
+	if fromOffset == toOffset {
+		return nil, fmt.Errorf("policy: scheduled: 'from' and 'to' must be different")
+	}
+
+	return &Scheduled{
+		from:          fromOffset,
+		to:            toOffset,
+		location:      time.Local,
+		checkInterval: defaultScheduledCheckInterval,
+		now:           time.Now,
+		stop:          map[string]chan struct{}{},
+		suspended:     map[string]bool{},
+	}, nil
+}
+
+func MustScheduled(from, to string) *Scheduled {
+	p, err := NewScheduled(from, to)
+	if err != nil {
+		panic(fmt.Errorf("camel: %w", err))
+	}
+	return p
+}
+
+// Location sets the time zone of the window, default is time.Local.
+func (p *Scheduled) Location(location *time.Location) *Scheduled {
+	p.location = location
+	return p
+}
+
+// CheckInterval sets how often the window is checked, default is 1 minute.
+func (p *Scheduled) CheckInterval(interval time.Duration) *Scheduled {
+	p.checkInterval = interval
+	return p
+}
+
+// OnError sets a callback that is called when the route cannot be suspended or resumed.
+func (p *Scheduled) OnError(fn func(r api.RouteController, err error)) *Scheduled {
+	p.onError = fn
+	return p
+}
+
+// IsActive reports whether the given time is within the window.
+func (p *Scheduled) IsActive(t time.Time) bool {
+	t = t.In(p.location)
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+
+	if p.from < p.to {
+		return offset >= p.from && offset < p.to
+	}
+	// crosses midnight
+	return offset >= p.from || offset < p.to
+}
+
+func (p *Scheduled) OnInit(_ api.RouteController) {}
+
+func (p *Scheduled) OnStart(r api.RouteController) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, started := p.stop[r.Name()]; started {
+		return
+	}
+
+	p.apply(r)
+
+	stop := make(chan struct{})
+	p.stop[r.Name()] = stop
+
+	go func() {
+		ticker := time.NewTicker(p.checkInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				p.mu.Lock()
+				p.apply(r)
+				p.mu.Unlock()
+			}
+		}
+	}()
+}
+
+func (p *Scheduled) OnStop(r api.RouteController) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if stop, started := p.stop[r.Name()]; started {
+		close(stop)
+		delete(p.stop, r.Name())
+	}
+}
+
+func (p *Scheduled) OnExchangeBegin(_ api.RouteController, _ *exchange.Exchange) {}
+
+func (p *Scheduled) OnExchangeDone(_ api.RouteController, _ *exchange.Exchange) {}
+
+// apply suspends or resumes the route depending on the current time, must be called under p.mu lock.
+func (p *Scheduled) apply(r api.RouteController) {
+	var err error
+	if p.IsActive(p.now()) {
+		if p.suspended[r.Name()] {
+			if err = r.Resume(); err == nil {
+				delete(p.suspended, r.Name())
+			}
+		}
+	} else if !p.suspended[r.Name()] && !r.IsSuspended() {
+		if err = r.Suspend(); err == nil {
+			p.suspended[r.Name()] = true
+		}
+	}
+	if err != nil && p.onError != nil {
+		p.onError(r, err)
+	}
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	layout := "15:04"
+	if len(s) > len(layout) {
+		layout = "15:04:05"
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second, nil
+}
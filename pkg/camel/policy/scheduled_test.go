@@ -0,0 +1,77 @@
+package policy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduled_IsActive(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		at       string
+		want     bool
+	}{
+		{name: "within window", from: "08:00", to: "18:00", at: "12:30", want: true},
+		{name: "window start", from: "08:00", to: "18:00", at: "08:00", want: true},
+		{name: "window end", from: "08:00", to: "18:00", at: "18:00", want: false},
+		{name: "before window", from: "08:00", to: "18:00", at: "07:59", want: false},
+		{name: "crosses midnight, late", from: "22:00", to: "06:00", at: "23:15", want: true},
+		{name: "crosses midnight, early", from: "22:00", to: "06:00", at: "05:59", want: true},
+		{name: "crosses midnight, day", from: "22:00", to: "06:00", at: "12:00", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := MustScheduled(tt.from, tt.to).Location(time.UTC)
+			at, _ := time.Parse("15:04", tt.at)
+
+			if got := p.IsActive(at); got != tt.want {
+				t.Errorf("TestScheduled_IsActive() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduled_OnStart(t *testing.T) {
+	p := MustScheduled("08:00", "18:00").Location(time.UTC)
+	p.now = func() time.Time {
+		return time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC)
+	}
+
+	r := &testRoute{name: "test"}
+	p.OnStart(r)
+	defer p.OnStop(r)
+
+	if !r.IsSuspended() {
+		t.Fatalf("TestScheduled_OnStart(): route must be suspended outside of the window")
+	}
+}
+
+func TestScheduled_ResumesOnlySuspendedByPolicy(t *testing.T) {
+	p := MustScheduled("08:00", "18:00").Location(time.UTC)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	p.now = func() time.Time {
+		return now
+	}
+
+	// suspended by another policy or by the operator
+	r := &testRoute{name: "test", suspended: true}
+	p.apply(r)
+	if !r.IsSuspended() {
+		t.Fatalf("TestScheduled_ResumesOnlySuspendedByPolicy(): route suspended by others must not be resumed")
+	}
+
+	r.suspended = false
+	now = time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC)
+	p.apply(r)
+	if !r.IsSuspended() {
+		t.Fatalf("TestScheduled_ResumesOnlySuspendedByPolicy(): route must be suspended outside of the window")
+	}
+
+	now = time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC)
+	p.apply(r)
+	if r.IsSuspended() {
+		t.Fatalf("TestScheduled_ResumesOnlySuspendedByPolicy(): route suspended by the policy must be resumed within the window")
+	}
+}
@@ -0,0 +1,94 @@
+package policy
+
+import (
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"sync"
+)
+
+// ThrottlingInflight suspends the route consumer when the number of in-flight exchanges exceeds maxInflight
+// and resumes it once the number drops below resumeBelow.
+// The policy can be shared between routes, in-flight exchanges are counted per route.
+type ThrottlingInflight struct {
+	mu          sync.Mutex
+	maxInflight int
+	resumeBelow int
+	inflight    map[string]int
+	suspended   map[string]bool // routes suspended by this policy
+	onError     func(r api.RouteController, err error)
+}
+
+func NewThrottlingInflight(maxInflight, resumeBelow int) *ThrottlingInflight {
+	if maxInflight <= 0 {
+		panic(fmt.Errorf("camel: policy: maxInflight must be greater than 0"))
+	}
+	if resumeBelow <= 0 || resumeBelow > maxInflight {
+		panic(fmt.Errorf("camel: policy: resumeBelow must be in range [1, maxInflight]"))
+	}
+	return &ThrottlingInflight{
+		maxInflight: maxInflight,
+		resumeBelow: resumeBelow,
+		inflight:    map[string]int{},
+		suspended:   map[string]bool{},
+	}
+}
+
+// OnError sets a callback that is called when the route cannot be suspended or resumed.
+func (p *ThrottlingInflight) OnError(fn func(r api.RouteController, err error)) *ThrottlingInflight {
+	p.onError = fn
+	return p
+}
+
+// Inflight returns the current number of in-flight exchanges of the given route.
+func (p *ThrottlingInflight) Inflight(routeName string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.inflight[routeName]
+}
+
+func (p *ThrottlingInflight) OnInit(r api.RouteController) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inflight[r.Name()] = 0
+}
+
+func (p *ThrottlingInflight) OnStart(_ api.RouteController) {}
+
+func (p *ThrottlingInflight) OnStop(_ api.RouteController) {}
+
+func (p *ThrottlingInflight) OnExchangeBegin(r api.RouteController, _ *exchange.Exchange) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inflight[r.Name()]++
+	if p.inflight[r.Name()] > p.maxInflight && !p.suspended[r.Name()] {
+		if err := r.Suspend(); err != nil {
+			p.handleError(r, err)
+			return
+		}
+		p.suspended[r.Name()] = true
+	}
+}
+
+func (p *ThrottlingInflight) OnExchangeDone(r api.RouteController, _ *exchange.Exchange) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inflight[r.Name()]--
+	if p.inflight[r.Name()] < p.resumeBelow && p.suspended[r.Name()] {
+		if err := r.Resume(); err != nil {
+			p.handleError(r, err)
+			return
+		}
+		p.suspended[r.Name()] = false
+	}
+}
+
+func (p *ThrottlingInflight) handleError(r api.RouteController, err error) {
+	if p.onError != nil {
+		p.onError(r, err)
+	}
+}
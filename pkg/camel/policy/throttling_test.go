@@ -0,0 +1,47 @@
+package policy
+
+import (
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"testing"
+)
+
+type testRoute struct {
+	name      string
+	suspended bool
+}
+
+func (r *testRoute) Name() string      { return r.name }
+func (r *testRoute) From() string      { return "test:" + r.name }
+func (r *testRoute) Suspend() error    { r.suspended = true; return nil }
+func (r *testRoute) Resume() error     { r.suspended = false; return nil }
+func (r *testRoute) IsSuspended() bool { return r.suspended }
+
+func TestThrottlingInflight(t *testing.T) {
+	p := NewThrottlingInflight(2, 1)
+	r := &testRoute{name: "test"}
+	p.OnInit(r)
+
+	e := exchange.NewExchange(nil)
+
+	p.OnExchangeBegin(r, e)
+	p.OnExchangeBegin(r, e)
+	if r.IsSuspended() {
+		t.Fatalf("TestThrottlingInflight(): route suspended with %d in-flight exchanges", p.Inflight("test"))
+	}
+
+	p.OnExchangeBegin(r, e)
+	if !r.IsSuspended() {
+		t.Fatalf("TestThrottlingInflight(): route not suspended with %d in-flight exchanges", p.Inflight("test"))
+	}
+
+	p.OnExchangeDone(r, e)
+	p.OnExchangeDone(r, e)
+	if !r.IsSuspended() {
+		t.Fatalf("TestThrottlingInflight(): route resumed with %d in-flight exchanges", p.Inflight("test"))
+	}
+
+	p.OnExchangeDone(r, e)
+	if r.IsSuspended() {
+		t.Fatalf("TestThrottlingInflight(): route not resumed with %d in-flight exchanges", p.Inflight("test"))
+	}
+}
@@ -1,8 +1,11 @@
 package camel
 
 import (
+	"errors"
 	"fmt"
+	internalprocessor "github.com/paveldanilin/go-camel/internal/processor"
 	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
 	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
 )
 
@@ -28,11 +31,17 @@ func getRouteName(p api.Processor) string {
 	return fmt.Sprintf("%T", p)
 }
 
-// processor represents a decorator for any processor with pre/post processing functions.
+// processor represents a decorator for any processor with pre/post processing functions and the interceptor.
+// The error of the processor (including a recovered panic) is wrapped into errs.StepError.
 type processor struct {
 	delegate      api.Processor
 	preProcessor  func(*exchange.Exchange)
 	postProcessor func(*exchange.Exchange)
+	// interceptor is processed before the delegate, the delegate is skipped if the interceptor fails
+	// or stops the routing of the exchange (see Route.Intercepts)
+	interceptor api.Processor
+	// stepPath is the path of the route step the processor is created for (empty if unknown)
+	stepPath string
 }
 
 func decorateProcessor(p api.Processor, preProcessor func(*exchange.Exchange), postProcessor func(*exchange.Exchange)) *processor {
@@ -44,14 +53,10 @@ func decorateProcessor(p api.Processor, preProcessor func(*exchange.Exchange), p
 }
 
 func (p *processor) Process(e *exchange.Exchange) {
-	// Check if exchange supports MessageHistory
-	if mh, supportsMessageHistory := e.Message().Header(exchange.CamelHeaderMessageHistory); supportsMessageHistory {
-		if hist, isMessageHistory := mh.(*exchange.MessageHistory); isMessageHistory {
-			rec := exchange.NewMessageHistoryRecord(getRouteName(p.delegate), getProcessorName(p.delegate))
-			hist.AddRecord(rec)
-			// Update elapsed time
-			defer rec.UpdateElapsedTime()
-		}
+	rec := p.recordHistory(e)
+	if rec != nil {
+		// Update elapsed time
+		defer rec.UpdateElapsedTime()
 	}
 
 	if err := e.CheckCancelOrTimeout(); err != nil {
@@ -69,5 +74,93 @@ func (p *processor) Process(e *exchange.Exchange) {
 		p.preProcessor(e)
 	}
 
-	p.delegate.Process(e)
+	if p.interceptor != nil {
+		internalprocessor.Invoke(p.interceptor, e)
+		if e.IsError() || e.IsRouteStopped() {
+			p.wrapError(e)
+			return
+		}
+	}
+
+	internalprocessor.Invoke(p.delegate, e)
+	p.wrapError(e)
+}
+
+// ProcessAsync is the asynchronous variant of Process, post-processing is done in the completion callback.
+func (p *processor) ProcessAsync(e *exchange.Exchange, done func()) {
+	rec := p.recordHistory(e)
+
+	if err := e.CheckCancelOrTimeout(); err != nil {
+		e.SetError(err)
+		if rec != nil {
+			rec.UpdateElapsedTime()
+		}
+		done()
+		return
+	}
+
+	if p.preProcessor != nil {
+		p.preProcessor(e)
+	}
+
+	complete := func() {
+		p.wrapError(e)
+		if p.postProcessor != nil {
+			p.postProcessor(e)
+		}
+		if rec != nil {
+			rec.UpdateElapsedTime()
+		}
+		done()
+	}
+
+	if p.interceptor == nil {
+		internalprocessor.InvokeAsync(p.delegate, e, complete)
+		return
+	}
+	internalprocessor.InvokeAsync(p.interceptor, e, func() {
+		if e.IsError() || e.IsRouteStopped() {
+			complete()
+			return
+		}
+		internalprocessor.InvokeAsync(p.delegate, e, complete)
+	})
+}
+
+// wrapError wraps the exchange error into errs.StepError unless the error is already wrapped by the nested step,
+// thus the error refers to the step where it occurred.
+func (p *processor) wrapError(e *exchange.Exchange) {
+	err := e.Error()
+	if err == nil {
+		return
+	}
+	var stepErr *errs.StepError
+	if errors.As(err, &stepErr) {
+		return
+	}
+
+	stepErr = &errs.StepError{
+		RouteName:  getRouteName(p.delegate),
+		StepName:   getProcessorName(p.delegate),
+		StepPath:   p.stepPath,
+		ExchangeId: e.Id(),
+		Err:        err,
+	}
+	var panicErr *errs.PanicError
+	if errors.As(err, &panicErr) {
+		stepErr.Stack = string(panicErr.Stack)
+	}
+	e.SetError(stepErr)
+}
+
+// recordHistory adds the message history record if exchange supports MessageHistory, returns nil otherwise.
+func (p *processor) recordHistory(e *exchange.Exchange) *exchange.MessageHistoryRecord {
+	if mh, supportsMessageHistory := e.Message().Header(exchange.CamelHeaderMessageHistory); supportsMessageHistory {
+		if hist, isMessageHistory := mh.(*exchange.MessageHistory); isMessageHistory {
+			rec := exchange.NewMessageHistoryRecord(getRouteName(p.delegate), getProcessorName(p.delegate))
+			hist.AddRecord(rec)
+			return rec
+		}
+	}
+	return nil
 }
@@ -0,0 +1,239 @@
+package camel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/uri"
+	"reflect"
+	"sync"
+)
+
+// ErrNoDefaultEndpoint is returned by ProducerTemplate *Default methods if the default endpoint is not configured.
+var ErrNoDefaultEndpoint = errors.New("default endpoint is not configured")
+
+// ProducerTemplate sends messages to endpoints from the application code.
+// Producers are created once per endpoint and cached, the template is safe for concurrent use.
+//
+//	Send* methods send InOnly (fire-and-forget) messages, only an error is reported back.
+//	Request* methods send InOut (request-reply) messages and return the reply message.
+type ProducerTemplate struct {
+	rt              *Runtime
+	mu              sync.RWMutex
+	defaultEndpoint string
+	producers       map[string]api.Producer
+}
+
+// NewProducerTemplate creates a new ProducerTemplate bound to the runtime.
+func (rt *Runtime) NewProducerTemplate() *ProducerTemplate {
+	return &ProducerTemplate{
+		rt:        rt,
+		producers: map[string]api.Producer{},
+	}
+}
+
+// SetDefaultEndpoint sets the endpoint that is used by *Default methods.
+func (pt *ProducerTemplate) SetDefaultEndpoint(uri string) *ProducerTemplate {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	pt.defaultEndpoint = uri
+	return pt
+}
+
+func (pt *ProducerTemplate) DefaultEndpoint() string {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+
+	return pt.defaultEndpoint
+}
+
+// Send sends the InOnly message to the endpoint and returns the processing error.
+// Asynchronous endpoints (e.g. seda) do not wait for the message to be processed.
+func (pt *ProducerTemplate) Send(ctx context.Context, uri string, body any, headers map[string]any) error {
+	e := pt.newExchange(ctx, body, headers)
+	e.SetPattern(exchange.InOnly)
+	return pt.Process(uri, e)
+}
+
+func (pt *ProducerTemplate) SendBody(ctx context.Context, uri string, body any) error {
+	return pt.Send(ctx, uri, body, nil)
+}
+
+func (pt *ProducerTemplate) SendDefault(ctx context.Context, body any, headers map[string]any) error {
+	defaultEndpoint, err := pt.requireDefaultEndpoint()
+	if err != nil {
+		return err
+	}
+	return pt.Send(ctx, defaultEndpoint, body, headers)
+}
+
+// Request sends the InOut message to the endpoint and waits for the reply.
+func (pt *ProducerTemplate) Request(ctx context.Context, uri string, body any, headers map[string]any) (*exchange.Message, error) {
+	e := pt.newExchange(ctx, body, headers)
+	e.SetPattern(exchange.InOut)
+	if err := pt.Process(uri, e); err != nil {
+		return nil, err
+	}
+	return e.Reply(), nil
+}
+
+// RequestBody sends the body to the endpoint and returns the reply body.
+func (pt *ProducerTemplate) RequestBody(ctx context.Context, uri string, body any) (any, error) {
+	reply, err := pt.Request(ctx, uri, body, nil)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Body, nil
+}
+
+func (pt *ProducerTemplate) RequestDefault(ctx context.Context, body any, headers map[string]any) (*exchange.Message, error) {
+	defaultEndpoint, err := pt.requireDefaultEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	return pt.Request(ctx, defaultEndpoint, body, headers)
+}
+
+// Process sends the given exchange to the endpoint and returns the exchange error.
+func (pt *ProducerTemplate) Process(uri string, e *exchange.Exchange) error {
+	producer, err := pt.producer(uri)
+	if err != nil {
+		return err
+	}
+
+	producer.Process(e)
+
+	return e.Error()
+}
+
+// AsyncSend sends the message in a separate goroutine, the Future holds the processing error.
+func (pt *ProducerTemplate) AsyncSend(ctx context.Context, uri string, body any, headers map[string]any) *Future {
+	f := newFuture()
+	go func() {
+		f.complete(nil, pt.Send(ctx, uri, body, headers))
+	}()
+	return f
+}
+
+// AsyncRequest sends the message in a separate goroutine, the Future holds the reply.
+func (pt *ProducerTemplate) AsyncRequest(ctx context.Context, uri string, body any, headers map[string]any) *Future {
+	f := newFuture()
+	go func() {
+		f.complete(pt.Request(ctx, uri, body, headers))
+	}()
+	return f
+}
+
+// Stop drops cached producers.
+func (pt *ProducerTemplate) Stop() {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	pt.producers = map[string]api.Producer{}
+}
+
+func (pt *ProducerTemplate) requireDefaultEndpoint() (string, error) {
+	defaultEndpoint := pt.DefaultEndpoint()
+	if defaultEndpoint == "" {
+		return "", ErrNoDefaultEndpoint
+	}
+	return defaultEndpoint, nil
+}
+
+func (pt *ProducerTemplate) newExchange(ctx context.Context, body any, headers map[string]any) *exchange.Exchange {
+	e := pt.rt.NewExchange(ctx)
+	e.Message().Body = body
+	e.Message().Headers().SetAll(headers)
+	return e
+}
+
+func (pt *ProducerTemplate) producer(rawUri string) (api.Producer, error) {
+	key, err := uri.Normalize(rawUri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URI '%s': %w", rawUri, err)
+	}
+
+	pt.mu.RLock()
+	producer, exists := pt.producers[key]
+	pt.mu.RUnlock()
+	if exists {
+		return producer, nil
+	}
+
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	if producer, exists = pt.producers[key]; exists {
+		return producer, nil
+	}
+
+	endpoint, err := pt.rt.ResolveEndpoint(key)
+	if err != nil {
+		return nil, err
+	}
+	producer, err = endpoint.CreateProducer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create producer for URI '%s': %w", rawUri, err)
+	}
+	pt.producers[key] = producer
+
+	return producer, nil
+}
+
+// RequestBodyAs sends the body to the endpoint and returns the reply body converted to T.
+// The body is converted by means of the runtime converters if it is not of type T.
+func RequestBodyAs[T any](ctx context.Context, pt *ProducerTemplate, uri string, body any) (T, error) {
+	var zero T
+
+	reply, err := pt.RequestBody(ctx, uri, body)
+	if err != nil {
+		return zero, err
+	}
+	if typed, isTyped := reply.(T); isTyped {
+		return typed, nil
+	}
+
+	converted, err := pt.rt.converterRegistry.Convert(reply, reflect.TypeFor[T](), nil)
+	if err != nil {
+		return zero, fmt.Errorf("failed to convert reply body %T to %s: %w", reply, reflect.TypeFor[T](), err)
+	}
+	typed, isTyped := converted.(T)
+	if !isTyped {
+		return zero, fmt.Errorf("failed to convert reply body %T to %s", reply, reflect.TypeFor[T]())
+	}
+	return typed, nil
+}
+
+// Future is the result of an asynchronous ProducerTemplate call.
+type Future struct {
+	done  chan struct{}
+	reply *exchange.Message
+	err   error
+}
+
+func newFuture() *Future {
+	return &Future{done: make(chan struct{})}
+}
+
+func (f *Future) complete(reply *exchange.Message, err error) {
+	f.reply, f.err = reply, err
+	close(f.done)
+}
+
+// Done returns a channel that is closed when the call is completed.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Get waits for the call completion and returns the reply (nil for AsyncSend).
+func (f *Future) Get(ctx context.Context) (*exchange.Message, error) {
+	select {
+	case <-f.done:
+		return f.reply, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,43 @@
+package camel
+
+import (
+	"errors"
+	"sync"
+)
+
+type beanRegistry struct {
+	mu      sync.Mutex
+	beanMap map[string]any
+}
+
+func newBeanRegistry() *beanRegistry {
+	return &beanRegistry{
+		beanMap: map[string]any{},
+	}
+}
+
+func (br *beanRegistry) RegisterBean(name string, bean any) error {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	if bean == nil {
+		return errors.New("bean must be not nil")
+	}
+	if _, exists := br.beanMap[name]; exists {
+		return errors.New("bean already registered")
+	}
+
+	br.beanMap[name] = bean
+	return nil
+}
+
+func (br *beanRegistry) Bean(name string) any {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	if bean, exists := br.beanMap[name]; exists {
+		return bean
+	}
+
+	return nil
+}
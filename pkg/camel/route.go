@@ -3,15 +3,30 @@ package camel
 import (
 	"fmt"
 	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
 	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
 	"github.com/paveldanilin/go-camel/pkg/camel/expr"
 	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
 )
 
 type Route struct {
-	Name  string
-	From  string
-	Steps []api.RouteStep
+	Name     string
+	From     string
+	Steps    []api.RouteStep
+	Policies []api.RoutePolicy
+	// ErrorHandler handles the errors of the route steps (nil - no error handling).
+	ErrorHandler *routestep.ErrorHandler
+	// OnExceptions handle the errors of any route step (see routestep.OnException).
+	OnExceptions []routestep.OnException
+	// OnCompletions are processed once the route is done with the exchange (see routestep.OnCompletion).
+	OnCompletions []routestep.OnCompletion
+	// Intercepts are processed before every step of the route (see routestep.Intercept).
+	Intercepts []routestep.Intercept
+	// InterceptFroms are processed before the route consumes the exchange (see routestep.InterceptFrom).
+	InterceptFroms []routestep.InterceptFrom
+	// InterceptSendToEndpoints are processed before the exchange is sent to an endpoint
+	// (see routestep.InterceptSendToEndpoint).
+	InterceptSendToEndpoints []routestep.InterceptSendToEndpoint
 }
 
 // RouteBuilder represents a Route builder.
@@ -79,6 +94,129 @@ func (b *RouteBuilder) popStack() {
 	b.stack = b.stack[:len(b.stack)-1]
 }
 
+// RoutePolicy attaches the given policies to the route.
+func (b *RouteBuilder) RoutePolicy(policy ...api.RoutePolicy) *RouteBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.route.Policies = append(b.route.Policies, policy...)
+	return b
+}
+
+// ErrorHandler sets the route error handler, it applies to every step of the route
+// unless the step is nested in ErrorHandlerScope.
+func (b *RouteBuilder) ErrorHandler(errorHandler routestep.ErrorHandler) *RouteBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.route.ErrorHandler = &errorHandler
+	return b
+}
+
+// OnException adds the clause that handles the errors of any route step matched by errorMatcher.
+// Function configure will be called to configure the handler steps.
+func (b *RouteBuilder) OnException(errorMatcher errs.Matcher, configure func(b *RouteBuilder)) *OnExceptionBuilder {
+	if b.err != nil {
+		return &OnExceptionBuilder{builder: b, onException: &routestep.OnException{}}
+	}
+
+	var steps []api.RouteStep
+	b.pushStack(&steps)
+	configure(b)
+	b.popStack()
+
+	b.route.OnExceptions = append(b.route.OnExceptions, routestep.OnException{ErrorMatcher: errorMatcher, Steps: steps})
+	return &OnExceptionBuilder{builder: b, onException: &b.route.OnExceptions[len(b.route.OnExceptions)-1]}
+}
+
+// OnCompletion adds the clause processed once the route is done with the exchange in the given mode
+// (empty - routestep.OnCompletionAlways). Function configure will be called to configure the clause steps.
+func (b *RouteBuilder) OnCompletion(mode routestep.OnCompletionMode, configure func(b *RouteBuilder)) *OnCompletionBuilder {
+	if b.err != nil {
+		return &OnCompletionBuilder{builder: b, onCompletion: &routestep.OnCompletion{}}
+	}
+
+	var steps []api.RouteStep
+	b.pushStack(&steps)
+	configure(b)
+	b.popStack()
+
+	b.route.OnCompletions = append(b.route.OnCompletions, routestep.OnCompletion{Mode: mode, Steps: steps})
+	return &OnCompletionBuilder{builder: b, onCompletion: &b.route.OnCompletions[len(b.route.OnCompletions)-1]}
+}
+
+// Intercept adds the clause processed before every step of the route.
+// Function configure will be called to configure the clause steps.
+func (b *RouteBuilder) Intercept(configure func(b *RouteBuilder)) *RouteBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	var steps []api.RouteStep
+	b.pushStack(&steps)
+	configure(b)
+	b.popStack()
+
+	b.route.Intercepts = append(b.route.Intercepts, routestep.Intercept{Steps: steps})
+	return b
+}
+
+// InterceptFrom adds the clause processed before the route consumes the exchange from the endpoint
+// matched by uriPattern (see routestep.InterceptFrom). Function configure will be called to configure the clause steps.
+func (b *RouteBuilder) InterceptFrom(uriPattern string, configure func(b *RouteBuilder)) *RouteBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	var steps []api.RouteStep
+	b.pushStack(&steps)
+	configure(b)
+	b.popStack()
+
+	b.route.InterceptFroms = append(b.route.InterceptFroms, routestep.InterceptFrom{URIPattern: uriPattern, Steps: steps})
+	return b
+}
+
+// InterceptSendToEndpoint adds the clause processed before the exchange is sent to the endpoint matched by uriPattern
+// (see routestep.InterceptSendToEndpoint). Function configure will be called to configure the clause steps.
+func (b *RouteBuilder) InterceptSendToEndpoint(uriPattern string, configure func(b *RouteBuilder)) *InterceptSendToEndpointBuilder {
+	if b.err != nil {
+		return &InterceptSendToEndpointBuilder{builder: b, intercept: &routestep.InterceptSendToEndpoint{}}
+	}
+
+	var steps []api.RouteStep
+	b.pushStack(&steps)
+	configure(b)
+	b.popStack()
+
+	b.route.InterceptSendToEndpoints = append(b.route.InterceptSendToEndpoints,
+		routestep.InterceptSendToEndpoint{URIPattern: uriPattern, Steps: steps})
+	return &InterceptSendToEndpointBuilder{
+		builder:   b,
+		intercept: &b.route.InterceptSendToEndpoints[len(b.route.InterceptSendToEndpoints)-1],
+	}
+}
+
+// ErrorHandlerScope adds step that applies the error handler to the nested steps.
+// Function configure will be called to configure the nested steps.
+func (b *RouteBuilder) ErrorHandlerScope(stepName string, errorHandler routestep.ErrorHandler, configure func(b *RouteBuilder)) *RouteBuilder {
+	if b.err != nil {
+		return b
+	}
+	scope := &routestep.ErrorHandlerScope{
+		Name:         stepName,
+		ErrorHandler: errorHandler,
+		Steps:        []api.RouteStep{},
+	}
+	b.addStep(scope)
+
+	b.pushStack(&scope.Steps)
+	configure(b)
+	b.popStack()
+
+	return b
+}
+
 // SetBody adds step to set the message body.
 func (b *RouteBuilder) SetBody(stepName string, bodyValue expr.Definition) *RouteBuilder {
 	if b.err != nil {
@@ -208,6 +346,23 @@ func (b *RouteBuilder) Try(stepName string, configure func(b *RouteBuilder)) *Tr
 	return &TryStepBuilder{builder: b, tryStep: tryStep}
 }
 
+// Saga adds step that processes the nested steps within a saga (see routestep.Saga).
+// Function configure will be called to configure the nested steps.
+func (b *RouteBuilder) Saga(stepName string, configure func(b *RouteBuilder)) *SagaStepBuilder {
+	if b.err != nil {
+		return &SagaStepBuilder{builder: b}
+	}
+
+	sagaStep := &routestep.Saga{Name: stepName, Propagation: routestep.SagaPropagationRequired}
+	b.addStep(sagaStep)
+
+	b.pushStack(&sagaStep.Steps)
+	configure(b)
+	b.popStack()
+
+	return &SagaStepBuilder{builder: b, sagaStep: sagaStep}
+}
+
 func (b *RouteBuilder) SetError(stepName string, err error) *RouteBuilder {
 	if b.err != nil {
 		return b
@@ -255,6 +410,19 @@ func (b *RouteBuilder) Unmarshal(stepName string, format string, targetType any)
 	return b
 }
 
+// UnmarshalRef adds unmarshal step, targetTypeRef is a name of the bean used as a target type prototype.
+func (b *RouteBuilder) UnmarshalRef(stepName string, format string, targetTypeRef string) *RouteBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.addStep(&routestep.Unmarshal{
+		Name:          stepName,
+		Format:        format,
+		TargetTypeRef: targetTypeRef,
+	})
+	return b
+}
+
 func (b *RouteBuilder) Marshal(stepName string, format string) *RouteBuilder {
 	if b.err != nil {
 		return b
@@ -393,6 +561,30 @@ func (b *RouteBuilder) RemoveHeader(stepName string, headerName ...string) *Rout
 	return b
 }
 
+// SetPattern sets the exchange pattern (InOnly/InOut) for the rest of the route.
+func (b *RouteBuilder) SetPattern(stepName string, pattern exchange.ExchangePattern) *RouteBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.addStep(&routestep.SetPattern{
+		Name:    stepName,
+		Pattern: pattern,
+	})
+	return b
+}
+
+// Threads hands the rest of the route over to the pool of the executor profile.
+func (b *RouteBuilder) Threads(stepName, profile string) *RouteBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.addStep(&routestep.Threads{
+		Name:    stepName,
+		Profile: profile,
+	})
+	return b
+}
+
 func (b *RouteBuilder) RemoveProperty(stepName string, propertyName ...string) *RouteBuilder {
 	if b.err != nil {
 		return b
@@ -0,0 +1,76 @@
+package camel
+
+import (
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+)
+
+type InterceptSendToEndpointBuilder struct {
+	builder   *RouteBuilder
+	intercept *routestep.InterceptSendToEndpoint
+	err       error
+}
+
+// NewIntercept creates the intercept clause used by every route of the runtime (see RuntimeConfig.Intercepts).
+func NewIntercept(configure func(b *RouteBuilder)) (routestep.Intercept, error) {
+	steps, err := buildInterceptSteps("intercept", configure)
+	if err != nil {
+		return routestep.Intercept{}, err
+	}
+	return routestep.Intercept{Steps: steps}, nil
+}
+
+// NewInterceptFrom creates the interceptFrom clause used by every route of the runtime
+// (see RuntimeConfig.InterceptFroms).
+func NewInterceptFrom(uriPattern string, configure func(b *RouteBuilder)) (routestep.InterceptFrom, error) {
+	steps, err := buildInterceptSteps("interceptFrom", configure)
+	if err != nil {
+		return routestep.InterceptFrom{}, err
+	}
+	return routestep.InterceptFrom{URIPattern: uriPattern, Steps: steps}, nil
+}
+
+// NewInterceptSendToEndpoint creates the builder of the interceptSendToEndpoint clause used by every route
+// of the runtime (see RuntimeConfig.InterceptSendToEndpoints).
+func NewInterceptSendToEndpoint(uriPattern string, configure func(b *RouteBuilder)) *InterceptSendToEndpointBuilder {
+	intercept := &routestep.InterceptSendToEndpoint{URIPattern: uriPattern}
+
+	steps, err := buildInterceptSteps("interceptSendToEndpoint", configure)
+	if err != nil {
+		return &InterceptSendToEndpointBuilder{intercept: intercept, err: err}
+	}
+	intercept.Steps = steps
+	return &InterceptSendToEndpointBuilder{intercept: intercept}
+}
+
+func buildInterceptSteps(name string, configure func(b *RouteBuilder)) ([]api.RouteStep, error) {
+	b := NewRoute(name, name)
+	configure(b)
+	r, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return r.Steps, nil
+}
+
+// SkipSendToOriginalEndpoint processes the clause steps instead of sending the exchange to the endpoint.
+func (ib *InterceptSendToEndpointBuilder) SkipSendToOriginalEndpoint() *InterceptSendToEndpointBuilder {
+	if ib.builder != nil && ib.builder.err != nil {
+		return ib
+	}
+	ib.intercept.SkipSendToOriginalEndpoint = true
+	return ib
+}
+
+// EndInterceptSendToEndpoint returns to the route builder.
+func (ib *InterceptSendToEndpointBuilder) EndInterceptSendToEndpoint() *RouteBuilder {
+	return ib.builder
+}
+
+// Build returns the interceptSendToEndpoint clause created by NewInterceptSendToEndpoint.
+func (ib *InterceptSendToEndpointBuilder) Build() (routestep.InterceptSendToEndpoint, error) {
+	if ib.err != nil {
+		return routestep.InterceptSendToEndpoint{}, ib.err
+	}
+	return *ib.intercept, nil
+}
@@ -25,11 +25,30 @@ func (mb *MulticastStepBuilder) StopOnError(stopOnError bool) *MulticastStepBuil
 	return mb
 }
 
+// Timeout sets the max processing time of all outputs in milliseconds,
+// the outputs that are not completed in time are cancelled.
+func (mb *MulticastStepBuilder) Timeout(timeoutMs int64) *MulticastStepBuilder {
+	mb.multicastStep.Timeout = timeoutMs
+	return mb
+}
+
 func (mb *MulticastStepBuilder) Aggregator(aggregator api.ExchangeAggregator) *MulticastStepBuilder {
 	mb.multicastStep.Aggregator = aggregator
 	return mb
 }
 
+// AggregatorRef sets a name of the aggregator bean registered by means of Runtime.RegisterBean.
+func (mb *MulticastStepBuilder) AggregatorRef(beanName string) *MulticastStepBuilder {
+	mb.multicastStep.AggregatorRef = beanName
+	return mb
+}
+
+// ExecutorService sets a name of the executor profile that caps the number of outputs processed in parallel.
+func (mb *MulticastStepBuilder) ExecutorService(profile string) *MulticastStepBuilder {
+	mb.multicastStep.ExecutorService = profile
+	return mb
+}
+
 func (mb *MulticastStepBuilder) Process(configure func(b *RouteBuilder)) *MulticastStepBuilder {
 	if mb.builder.err != nil {
 		return mb
@@ -0,0 +1,34 @@
+package camel
+
+import (
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+)
+
+type OnCompletionBuilder struct {
+	builder      *RouteBuilder
+	onCompletion *routestep.OnCompletion
+}
+
+// OnWhen sets the predicate the final exchange must match to process the clause steps.
+func (ob *OnCompletionBuilder) OnWhen(predicate expr.Definition) *OnCompletionBuilder {
+	if ob.builder.err != nil {
+		return ob
+	}
+	ob.onCompletion.OnWhen = predicate
+	return ob
+}
+
+// ParallelProcessing processes the clause steps in a separate goroutine, the route does not wait for them.
+func (ob *OnCompletionBuilder) ParallelProcessing() *OnCompletionBuilder {
+	if ob.builder.err != nil {
+		return ob
+	}
+	ob.onCompletion.Parallel = true
+	return ob
+}
+
+// EndOnCompletion returns to the route builder.
+func (ob *OnCompletionBuilder) EndOnCompletion() *RouteBuilder {
+	return ob.builder
+}
@@ -0,0 +1,52 @@
+package camel
+
+import (
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+)
+
+type OnExceptionBuilder struct {
+	builder     *RouteBuilder
+	onException *routestep.OnException
+	err         error
+}
+
+// NewOnException creates the builder of the onException clause used by every route of the runtime
+// (see RuntimeConfig.OnExceptions).
+func NewOnException(errorMatcher errs.Matcher, configure func(b *RouteBuilder)) *OnExceptionBuilder {
+	onException := &routestep.OnException{ErrorMatcher: errorMatcher}
+
+	b := NewRoute("onException", "onException")
+	configure(b)
+	r, err := b.Build()
+	if err != nil {
+		return &OnExceptionBuilder{onException: onException, err: err}
+	}
+	onException.Steps = r.Steps
+	return &OnExceptionBuilder{onException: onException}
+}
+
+// Handled clears the error and stops the routing of the exchange after the handler steps.
+func (ob *OnExceptionBuilder) Handled(handled bool) *OnExceptionBuilder {
+	ob.onException.Handled = handled
+	return ob
+}
+
+// Continued clears the error and continues the routing with the step next to the failed one.
+func (ob *OnExceptionBuilder) Continued(continued bool) *OnExceptionBuilder {
+	ob.onException.Continued = continued
+	return ob
+}
+
+// EndOnException returns to the route builder.
+func (ob *OnExceptionBuilder) EndOnException() *RouteBuilder {
+	return ob.builder
+}
+
+// Build returns the onException clause created by NewOnException.
+func (ob *OnExceptionBuilder) Build() (routestep.OnException, error) {
+	if ob.err != nil {
+		return routestep.OnException{}, ob.err
+	}
+	return *ob.onException, nil
+}
@@ -0,0 +1,50 @@
+package camel
+
+import (
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+)
+
+type SagaStepBuilder struct {
+	builder  *RouteBuilder
+	sagaStep *routestep.Saga
+}
+
+// Propagation sets how the saga step deals with the active saga (routestep.SagaPropagationRequired by default).
+func (sb *SagaStepBuilder) Propagation(propagation routestep.SagaPropagation) *SagaStepBuilder {
+	if sb.builder.err != nil {
+		return sb
+	}
+	sb.sagaStep.Propagation = propagation
+	return sb
+}
+
+// Timeout sets the time in milliseconds the created saga is compensated after unless it is finished.
+func (sb *SagaStepBuilder) Timeout(timeoutMs int64) *SagaStepBuilder {
+	if sb.builder.err != nil {
+		return sb
+	}
+	sb.sagaStep.Timeout = timeoutMs
+	return sb
+}
+
+// Compensation sets the endpoint the exchange is sent to once the saga is compensated.
+func (sb *SagaStepBuilder) Compensation(uri string) *SagaStepBuilder {
+	if sb.builder.err != nil {
+		return sb
+	}
+	sb.sagaStep.CompensationURI = uri
+	return sb
+}
+
+// Completion sets the endpoint the exchange is sent to once the saga is completed.
+func (sb *SagaStepBuilder) Completion(uri string) *SagaStepBuilder {
+	if sb.builder.err != nil {
+		return sb
+	}
+	sb.sagaStep.CompletionURI = uri
+	return sb
+}
+
+func (sb *SagaStepBuilder) EndSaga() *RouteBuilder {
+	return sb.builder
+}
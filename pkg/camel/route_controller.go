@@ -0,0 +1,210 @@
+package camel
+
+import (
+	"errors"
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"sync"
+	"time"
+)
+
+const drainPollInterval = 10 * time.Millisecond
+
+// ErrRouteSuspended is set on exchanges that were sent to a suspended route.
+var ErrRouteSuspended = errors.New("route is suspended")
+
+func (r *route) Name() string {
+	return r.name
+}
+
+func (r *route) From() string {
+	return r.from
+}
+
+// sharedConsumer is implemented by the consumers shared by the routes consuming from the same endpoint.
+type sharedConsumer interface {
+	processorRemover
+	AddProcessor(processor api.Processor)
+	ProcessorCount() int
+}
+
+// sharedConsumerMu serializes detaching and attaching the routes of the shared consumers.
+var sharedConsumerMu sync.Mutex
+
+// Suspend stops the route consumer, the consumer shared with other routes keeps running: the route is detached from it.
+func (r *route) Suspend() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.suspended {
+		return nil
+	}
+	if r.consumer != nil {
+		if err := r.suspendConsumer(); err != nil {
+			return fmt.Errorf("failed to suspend route '%s': %w", r.name, err)
+		}
+	}
+	r.suspended = true
+	return nil
+}
+
+func (r *route) suspendConsumer() error {
+	shared, isShared := r.consumer.(sharedConsumer)
+	if !isShared {
+		return r.consumer.Stop()
+	}
+
+	sharedConsumerMu.Lock()
+	defer sharedConsumerMu.Unlock()
+
+	// The route is kept attached to the consumer used only by it, thus it rejects the exchanges (see route.begin)
+	if shared.ProcessorCount() <= 1 {
+		return r.consumer.Stop()
+	}
+	shared.RemoveProcessor(r)
+	r.detached = true
+	return nil
+}
+
+// Resume attaches the route to its consumer and starts the consumer.
+func (r *route) Resume() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.suspended {
+		return nil
+	}
+	if r.consumer != nil {
+		if err := r.resumeConsumer(); err != nil {
+			return fmt.Errorf("failed to resume route '%s': %w", r.name, err)
+		}
+	}
+	r.suspended = false
+	return nil
+}
+
+func (r *route) resumeConsumer() error {
+	if !r.detached {
+		return r.consumer.Start()
+	}
+
+	sharedConsumerMu.Lock()
+	defer sharedConsumerMu.Unlock()
+
+	r.consumer.(sharedConsumer).AddProcessor(r)
+	r.detached = false
+	return r.consumer.Start()
+}
+
+func (r *route) IsSuspended() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.suspended
+}
+
+// Process is the route entry point used by the route consumer.
+func (r *route) Process(e *exchange.Exchange) {
+	producer, uow, accepted := r.begin(e)
+	if !accepted {
+		return
+	}
+	defer r.end(e, uow)
+
+	producer.Process(e)
+}
+
+// ProcessAsync is the asynchronous route entry point, done is called when the exchange is processed by the route.
+func (r *route) ProcessAsync(e *exchange.Exchange, done func()) {
+	producer, uow, accepted := r.begin(e)
+	if !accepted {
+		done()
+		return
+	}
+
+	api.ToAsync(producer).ProcessAsync(e, func() {
+		r.end(e, uow)
+		done()
+	})
+}
+
+// routeUnitOfWork holds the state of the route the exchange is accepted with.
+type routeUnitOfWork struct {
+	policies      []api.RoutePolicy
+	onCompletions []api.Processor
+	// owner is TRUE if the route received the exchange first, it calls the exchange completions (see Exchange.AddOnCompletion)
+	owner bool
+}
+
+// begin accepts the exchange for processing, returns false if the route is suspended.
+// The exchange received while the route is drained waits for the new version of the route.
+func (r *route) begin(e *exchange.Exchange) (api.Producer, *routeUnitOfWork, bool) {
+	r.mu.RLock()
+	for r.draining != nil {
+		drained := r.draining
+		r.mu.RUnlock()
+		select {
+		case <-drained:
+		case <-e.Context().Done():
+			e.SetError(e.Context().Err())
+			return nil, nil, false
+		}
+		r.mu.RLock()
+	}
+	if r.suspended {
+		r.mu.RUnlock()
+		e.SetError(fmt.Errorf("%w: %s", ErrRouteSuspended, r.name))
+		if e.BeginUnitOfWork() {
+			e.DoneUnitOfWork()
+		}
+		return nil, nil, false
+	}
+	producer, keepOriginalMessage := r.producer, r.keepOriginalMessage
+	uow := &routeUnitOfWork{policies: r.policies, onCompletions: r.onCompletions}
+	r.inflight.Add(1)
+	r.mu.RUnlock()
+
+	uow.owner = e.BeginUnitOfWork()
+
+	// The first route keeps the original message of the exchange
+	if keepOriginalMessage && !e.HasProperty(exchange.CamelPropertyOriginalMessage) {
+		e.SetProperty(exchange.CamelPropertyOriginalMessage, e.Message().Copy())
+	}
+
+	for _, policy := range uow.policies {
+		policy.OnExchangeBegin(r, e)
+	}
+	return producer, uow, true
+}
+
+// end processes the onCompletion clauses of the route, then the exchange completions if the route owns the exchange.
+func (r *route) end(e *exchange.Exchange, uow *routeUnitOfWork) {
+	for _, onCompletion := range uow.onCompletions {
+		onCompletion.Process(e)
+	}
+	for _, policy := range uow.policies {
+		policy.OnExchangeDone(r, e)
+	}
+	if uow.owner {
+		e.DoneUnitOfWork()
+	}
+	r.inflight.Add(-1)
+}
+
+// Inflight returns the number of exchanges being processed by the route.
+func (r *route) Inflight() int64 {
+	return r.inflight.Load()
+}
+
+// drain waits until in-flight exchanges are done or timeout elapsed, returns false on timeout.
+func (r *route) drain(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for r.inflight.Load() > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(drainPollInterval)
+	}
+	return true
+}
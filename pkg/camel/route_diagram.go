@@ -0,0 +1,296 @@
+package camel
+
+import (
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+	"github.com/paveldanilin/go-camel/pkg/camel/uri"
+	"io"
+	"strings"
+)
+
+type diagramNodeShape int
+
+const (
+	shapeStep diagramNodeShape = iota
+	shapeFrom
+	shapeDecision
+	shapeJoin
+)
+
+type diagramNode struct {
+	id    string
+	label string
+	shape diagramNodeShape
+}
+
+type diagramEdge struct {
+	from   string
+	to     string
+	label  string
+	dashed bool
+}
+
+type diagramCluster struct {
+	name  string
+	nodes []diagramNode
+}
+
+// diagram is a format-independent representation of route flowcharts.
+type diagram struct {
+	clusters []*diagramCluster
+	edges    []diagramEdge
+	seq      int
+}
+
+func (d *diagram) addNode(c *diagramCluster, label string, shape diagramNodeShape) string {
+	d.seq++
+	id := fmt.Sprintf("n%d", d.seq)
+	c.nodes = append(c.nodes, diagramNode{id: id, label: label, shape: shape})
+	return id
+}
+
+func (d *diagram) connect(prev []string, to, label string, dashed bool) {
+	for _, from := range prev {
+		d.edges = append(d.edges, diagramEdge{from: from, to: to, label: label, dashed: dashed})
+	}
+}
+
+// newDiagram builds flowcharts of the given routes.
+// 'to' steps that send to 'direct:' endpoints consumed by one of the given routes are linked with the route start node.
+func newDiagram(routes ...*Route) *diagram {
+	d := &diagram{}
+	directFrom := map[string]string{} // direct endpoint name -> route start node id
+	var directTo []diagramEdge        // 'to' node id -> direct endpoint name
+
+	for _, r := range routes {
+		c := &diagramCluster{name: r.Name}
+		d.clusters = append(d.clusters, c)
+
+		start := d.addNode(c, r.From, shapeFrom)
+		if name, isDirect := directEndpointName(r.From); isDirect {
+			directFrom[name] = start
+		}
+
+		d.addSteps(c, r.Steps, []string{start}, "", false, func(id string, step *routestep.To) {
+			if name, isDirect := directEndpointName(step.URI); isDirect {
+				directTo = append(directTo, diagramEdge{from: id, to: name})
+			}
+		})
+	}
+
+	for _, link := range directTo {
+		if start, exists := directFrom[link.to]; exists {
+			d.edges = append(d.edges, diagramEdge{from: link.from, to: start, label: "direct", dashed: true})
+		}
+	}
+
+	return d
+}
+
+// addSteps adds a sequence of steps connected to prev nodes and returns the exit nodes of the sequence.
+// label and dashed are applied to the edges that lead to the first step.
+func (d *diagram) addSteps(c *diagramCluster, steps []api.RouteStep, prev []string, label string, dashed bool, onTo func(string, *routestep.To)) []string {
+	for _, step := range steps {
+		prev = d.addStep(c, step, prev, label, dashed, onTo)
+		label, dashed = "", false
+	}
+	return prev
+}
+
+func (d *diagram) addStep(c *diagramCluster, step api.RouteStep, prev []string, label string, dashed bool, onTo func(string, *routestep.To)) []string {
+	switch t := step.(type) {
+	case *routestep.Choice:
+		id := d.addNode(c, t.StepName(), shapeDecision)
+		d.connect(prev, id, label, dashed)
+
+		var exits []string
+		hasOtherwise := false
+		for _, branch := range stepBranches(t) {
+			hasOtherwise = hasOtherwise || branch.kind == branchOtherwise
+			exits = append(exits, d.addSteps(c, branch.steps, []string{id}, branch.label, false, onTo)...)
+		}
+		if !hasOtherwise {
+			exits = append(exits, id)
+		}
+		return exits
+
+	case *routestep.Try:
+		id := d.addNode(c, t.StepName(), shapeStep)
+		d.connect(prev, id, label, dashed)
+
+		var exits []string
+		var finally *stepBranch
+		for _, branch := range stepBranches(t) {
+			switch branch.kind {
+			case branchSteps:
+				exits = append(exits, d.addSteps(c, branch.steps, []string{id}, "", false, onTo)...)
+			case branchCatch:
+				exits = append(exits, d.addSteps(c, branch.steps, []string{id}, "catch "+branch.label, true, onTo)...)
+			case branchFinally:
+				finally = &branch
+			}
+		}
+		if finally != nil {
+			exits = d.addSteps(c, finally.steps, exits, finally.label, false, onTo)
+		}
+		return exits
+
+	case *routestep.Multicast:
+		id := d.addNode(c, t.StepName(), shapeStep)
+		d.connect(prev, id, label, dashed)
+
+		var exits []string
+		for _, branch := range stepBranches(t) {
+			exits = append(exits, d.addSteps(c, branch.steps, []string{id}, branch.label, false, onTo)...)
+		}
+		join := d.addNode(c, "", shapeJoin)
+		d.connect(exits, join, "", false)
+		return []string{join}
+
+	case *routestep.Pipeline:
+		id := d.addNode(c, t.StepName(), shapeStep)
+		d.connect(prev, id, label, dashed)
+		return d.addSteps(c, t.Steps, []string{id}, "", false, onTo)
+
+	case *routestep.ErrorHandlerScope:
+		id := d.addNode(c, t.StepName(), shapeStep)
+		d.connect(prev, id, label, dashed)
+		return d.addSteps(c, t.Steps, []string{id}, "", false, onTo)
+
+	case *routestep.Saga:
+		id := d.addNode(c, t.StepName(), shapeStep)
+		d.connect(prev, id, label, dashed)
+		return d.addSteps(c, t.Steps, []string{id}, "", false, onTo)
+
+	case *routestep.Loop:
+		id := d.addNode(c, t.StepName(), shapeDecision)
+		d.connect(prev, id, label, dashed)
+		exits := d.addSteps(c, t.Steps, []string{id}, "repeat", false, onTo)
+		d.connect(exits, id, "", true)
+		return []string{id}
+
+	case *routestep.To:
+		id := d.addNode(c, t.StepName(), shapeStep)
+		d.connect(prev, id, label, dashed)
+		onTo(id, t)
+		return []string{id}
+	}
+
+	id := d.addNode(c, step.StepName(), shapeStep)
+	d.connect(prev, id, label, dashed)
+	return []string{id}
+}
+
+func directEndpointName(rawUri string) (string, bool) {
+	u, err := uri.Parse(rawUri, nil)
+	if err != nil || u.Component() != "direct" {
+		return "", false
+	}
+	return u.Path(), true
+}
+
+// WriteDOT renders the given routes as Graphviz DOT digraph, each route is rendered as a cluster.
+func WriteDOT(w io.Writer, routes ...*Route) error {
+	d := newDiagram(routes...)
+
+	var b strings.Builder
+	b.WriteString("digraph routes {\n")
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [shape=box, style=rounded];\n")
+
+	for i, c := range d.clusters {
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&b, "    label=%s;\n", dotQuote(c.name))
+		for _, n := range c.nodes {
+			var attrs string
+			switch n.shape {
+			case shapeFrom:
+				attrs = "shape=ellipse"
+			case shapeDecision:
+				attrs = "shape=diamond, style=solid"
+			case shapeJoin:
+				attrs = "shape=point"
+			}
+			if attrs == "" {
+				fmt.Fprintf(&b, "    %s [label=%s];\n", n.id, dotQuote(n.label))
+			} else {
+				fmt.Fprintf(&b, "    %s [label=%s, %s];\n", n.id, dotQuote(n.label), attrs)
+			}
+		}
+		b.WriteString("  }\n")
+	}
+
+	for _, e := range d.edges {
+		var attrs []string
+		if e.label != "" {
+			attrs = append(attrs, "label="+dotQuote(e.label))
+		}
+		if e.dashed {
+			attrs = append(attrs, "style=dashed")
+		}
+		if len(attrs) == 0 {
+			fmt.Fprintf(&b, "  %s -> %s;\n", e.from, e.to)
+		} else {
+			fmt.Fprintf(&b, "  %s -> %s [%s];\n", e.from, e.to, strings.Join(attrs, ", "))
+		}
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid renders the given routes as Mermaid flowchart, each route is rendered as a subgraph.
+func WriteMermaid(w io.Writer, routes ...*Route) error {
+	d := newDiagram(routes...)
+
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+
+	for i, c := range d.clusters {
+		fmt.Fprintf(&b, "  subgraph route%d[%s]\n", i, mermaidQuote(c.name))
+		for _, n := range c.nodes {
+			switch n.shape {
+			case shapeFrom:
+				fmt.Fprintf(&b, "    %s([%s])\n", n.id, mermaidQuote(n.label))
+			case shapeDecision:
+				fmt.Fprintf(&b, "    %s{%s}\n", n.id, mermaidQuote(n.label))
+			case shapeJoin:
+				fmt.Fprintf(&b, "    %s((%s))\n", n.id, mermaidQuote(" "))
+			default:
+				fmt.Fprintf(&b, "    %s[%s]\n", n.id, mermaidQuote(n.label))
+			}
+		}
+		b.WriteString("  end\n")
+	}
+
+	for _, e := range d.edges {
+		arrow := "-->"
+		if e.dashed {
+			arrow = "-.->"
+		}
+		if e.label == "" {
+			fmt.Fprintf(&b, "  %s %s %s\n", e.from, arrow, e.to)
+		} else {
+			fmt.Fprintf(&b, "  %s %s|%s| %s\n", e.from, arrow, mermaidQuote(e.label), e.to)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func mermaidQuote(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	s = strings.ReplaceAll(s, "\n", " ")
+	return `"` + s + `"`
+}
@@ -0,0 +1,662 @@
+package camel
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+	"gopkg.in/yaml.v3"
+	"io"
+	"reflect"
+)
+
+type DocumentFormat string
+
+const (
+	DocumentFormatJSON DocumentFormat = "json"
+	DocumentFormatYAML DocumentFormat = "yaml"
+)
+
+// routesDocument is used to keep the order of route properties stable.
+type routesDocument struct {
+	Routes []routeDocument `json:"routes" yaml:"routes"`
+}
+
+type routeDocument struct {
+	Name                    string         `json:"name" yaml:"name"`
+	From                    string         `json:"from" yaml:"from"`
+	ErrorHandler            map[string]any `json:"errorHandler,omitempty" yaml:"errorHandler,omitempty"`
+	OnException             []any          `json:"onException,omitempty" yaml:"onException,omitempty"`
+	OnCompletion            []any          `json:"onCompletion,omitempty" yaml:"onCompletion,omitempty"`
+	Intercept               []any          `json:"intercept,omitempty" yaml:"intercept,omitempty"`
+	InterceptFrom           []any          `json:"interceptFrom,omitempty" yaml:"interceptFrom,omitempty"`
+	InterceptSendToEndpoint []any          `json:"interceptSendToEndpoint,omitempty" yaml:"interceptSendToEndpoint,omitempty"`
+	Steps                   []any          `json:"steps" yaml:"steps"`
+}
+
+// ExportRoutes writes the given routes as a declarative document (see ParseRoutes).
+// Returns RouteDefinitionError if a step cannot be represented in the document (e.g. inline Go funcs, route policies).
+func ExportRoutes(w io.Writer, format DocumentFormat, routes ...*Route) error {
+	data, err := MarshalRoutes(format, routes...)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// MarshalRoutes returns the given routes as a declarative document (see ParseRoutes).
+func MarshalRoutes(format DocumentFormat, routes ...*Route) ([]byte, error) {
+	doc := routesDocument{Routes: make([]routeDocument, 0, len(routes))}
+	for i, r := range routes {
+		path := fmt.Sprintf("routes[%d]", i)
+		if len(r.Policies) > 0 {
+			return nil, definitionErr(path+".policies", "route policies cannot be exported")
+		}
+		steps, err := exportStepsDefinition(path+".steps", r.Steps)
+		if err != nil {
+			return nil, err
+		}
+		var errorHandler map[string]any
+		if r.ErrorHandler != nil {
+			errorHandler = map[string]any{}
+			if err := exportErrorHandlerDefinition(path+".errorHandler", errorHandler, *r.ErrorHandler); err != nil {
+				return nil, err
+			}
+		}
+		onExceptions, err := exportOnExceptionsDefinition(path+".onException", r.OnExceptions)
+		if err != nil {
+			return nil, err
+		}
+		onCompletions, err := exportOnCompletionsDefinition(path+".onCompletion", r.OnCompletions)
+		if err != nil {
+			return nil, err
+		}
+		rd := routeDocument{
+			Name:         r.Name,
+			From:         r.From,
+			ErrorHandler: errorHandler,
+			OnException:  onExceptions,
+			OnCompletion: onCompletions,
+			Steps:        steps,
+		}
+		if err := exportInterceptsDefinition(path, &rd, r); err != nil {
+			return nil, err
+		}
+		doc.Routes = append(doc.Routes, rd)
+	}
+
+	switch format {
+	case DocumentFormatJSON:
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case DocumentFormatYAML:
+		return yaml.Marshal(doc)
+	}
+
+	return nil, fmt.Errorf("unknown document format: %s", format)
+}
+
+func exportStepsDefinition(path string, steps []api.RouteStep) ([]any, error) {
+	items := make([]any, 0, len(steps))
+	for i, step := range steps {
+		item, err := exportStepDefinition(fmt.Sprintf("%s[%d]", path, i), step)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// exportStepDefinition returns {kind: {properties}} representation of the given step.
+func exportStepDefinition(path string, s api.RouteStep) (map[string]any, error) {
+	obj := map[string]any{}
+	kind := ""
+
+	switch t := s.(type) {
+	case *routestep.SetBody:
+		kind = "setBody"
+		value, err := exportExpressionDefinition(path+"."+kind+".value", t.BodyValue)
+		if err != nil {
+			return nil, err
+		}
+		setName(obj, t.Name)
+		obj["value"] = value
+
+	case *routestep.SetHeader:
+		kind = "setHeader"
+		value, err := exportExpressionDefinition(path+"."+kind+".value", t.HeaderValue)
+		if err != nil {
+			return nil, err
+		}
+		setName(obj, t.Name)
+		obj["header"] = t.HeaderName
+		obj["value"] = value
+
+	case *routestep.SetProperty:
+		kind = "setProperty"
+		value, err := exportExpressionDefinition(path+"."+kind+".value", t.PropertyValue)
+		if err != nil {
+			return nil, err
+		}
+		setName(obj, t.Name)
+		obj["property"] = t.PropertyName
+		obj["value"] = value
+
+	case *routestep.RemoveHeader:
+		kind = "removeHeader"
+		setName(obj, t.Name)
+		obj["headers"] = t.HeaderNames
+
+	case *routestep.RemoveProperty:
+		kind = "removeProperty"
+		setName(obj, t.Name)
+		obj["properties"] = t.PropertyNames
+
+	case *routestep.SetPattern:
+		kind = "setPattern"
+		setName(obj, t.Name)
+		obj["pattern"] = string(t.Pattern)
+
+	case *routestep.Threads:
+		kind = "threads"
+		setName(obj, t.Name)
+		obj["profile"] = t.Profile
+
+	case *routestep.ConvertBody:
+		kind = "convertBody"
+		if err := exportConvertDefinition(path+"."+kind, obj, t.TargetType, t.NamedType, t.Params); err != nil {
+			return nil, err
+		}
+		setName(obj, t.Name)
+
+	case *routestep.ConvertHeader:
+		kind = "convertHeader"
+		if err := exportConvertDefinition(path+"."+kind, obj, t.TargetType, t.NamedType, t.Params); err != nil {
+			return nil, err
+		}
+		setName(obj, t.Name)
+		obj["header"] = t.HeaderName
+
+	case *routestep.ConvertProperty:
+		kind = "convertProperty"
+		if err := exportConvertDefinition(path+"."+kind, obj, t.TargetType, t.NamedType, t.Params); err != nil {
+			return nil, err
+		}
+		setName(obj, t.Name)
+		obj["property"] = t.PropertyName
+
+	case *routestep.To:
+		kind = "to"
+		setName(obj, t.Name)
+		obj["uri"] = t.URI
+
+	case *routestep.Log:
+		kind = "log"
+		level, err := exportLogLevel(path+"."+kind+".level", t.Level)
+		if err != nil {
+			return nil, err
+		}
+		setName(obj, t.Name)
+		obj["level"] = level
+		obj["message"] = t.Msg
+
+	case *routestep.Fn:
+		kind = "fn"
+		funcName, isNamedFunc := t.Func.(string)
+		if !isNamedFunc {
+			return nil, definitionErr(path+"."+kind+".func", "inline func %T cannot be exported, register it by means of Runtime.RegisterFunc and refer by name", t.Func)
+		}
+		setName(obj, t.Name)
+		obj["func"] = funcName
+
+	case *routestep.Delay:
+		kind = "delay"
+		setName(obj, t.Name)
+		obj["duration"] = t.Duration
+
+	case *routestep.Marshal:
+		kind = "marshal"
+		setName(obj, t.Name)
+		obj["format"] = t.Format
+
+	case *routestep.Unmarshal:
+		kind = "unmarshal"
+		if t.TargetType != nil {
+			return nil, definitionErr(path+"."+kind+".targetType", "target type %T cannot be exported, register it by means of Runtime.RegisterBean and use TargetTypeRef", t.TargetType)
+		}
+		setName(obj, t.Name)
+		obj["format"] = t.Format
+		obj["targetType"] = t.TargetTypeRef
+
+	case *routestep.SetError:
+		kind = "setError"
+		if t.Error == nil {
+			return nil, definitionErr(path+"."+kind+".message", "error is nil")
+		}
+		setName(obj, t.Name)
+		obj["message"] = t.Error.Error()
+
+	case *routestep.Pipeline:
+		kind = "pipeline"
+		steps, err := exportStepsDefinition(path+"."+kind+".steps", t.Steps)
+		if err != nil {
+			return nil, err
+		}
+		setName(obj, t.Name)
+		obj["stopOnError"] = t.StoOnError
+		obj["steps"] = steps
+
+	case *routestep.Choice:
+		kind = "choice"
+		when := make([]any, 0, len(t.WhenCases))
+		for i, whenCase := range t.WhenCases {
+			whenPath := fmt.Sprintf("%s.%s.when[%d]", path, kind, i)
+			predicate, err := exportExpressionDefinition(whenPath+".predicate", whenCase.Predicate)
+			if err != nil {
+				return nil, err
+			}
+			steps, err := exportStepsDefinition(whenPath+".steps", whenCase.Steps)
+			if err != nil {
+				return nil, err
+			}
+			when = append(when, map[string]any{"predicate": predicate, "steps": steps})
+		}
+		setName(obj, t.Name)
+		obj["when"] = when
+		if len(t.Otherwise) > 0 {
+			otherwise, err := exportStepsDefinition(path+"."+kind+".otherwise", t.Otherwise)
+			if err != nil {
+				return nil, err
+			}
+			obj["otherwise"] = otherwise
+		}
+
+	case *routestep.Try:
+		kind = "try"
+		steps, err := exportStepsDefinition(path+"."+kind+".steps", t.Steps)
+		if err != nil {
+			return nil, err
+		}
+		setName(obj, t.Name)
+		obj["steps"] = steps
+		if len(t.WhenCatches) > 0 {
+			catches := make([]any, 0, len(t.WhenCatches))
+			for i, catch := range t.WhenCatches {
+				catchPath := fmt.Sprintf("%s.%s.catch[%d]", path, kind, i)
+				matcher, err := exportMatcherDefinition(catchPath+".matcher", catch.ErrorMatcher)
+				if err != nil {
+					return nil, err
+				}
+				catchSteps, err := exportStepsDefinition(catchPath+".steps", catch.Steps)
+				if err != nil {
+					return nil, err
+				}
+				catches = append(catches, map[string]any{"matcher": matcher, "steps": catchSteps})
+			}
+			obj["catch"] = catches
+		}
+		if len(t.FinallySteps) > 0 {
+			finally, err := exportStepsDefinition(path+"."+kind+".finally", t.FinallySteps)
+			if err != nil {
+				return nil, err
+			}
+			obj["finally"] = finally
+		}
+
+	case *routestep.Multicast:
+		kind = "multicast"
+		if t.Aggregator != nil {
+			return nil, definitionErr(path+"."+kind+".aggregator", "aggregator %T cannot be exported, register it by means of Runtime.RegisterBean and use AggregatorRef", t.Aggregator)
+		}
+		outputs := make([]any, 0, len(t.Outputs))
+		for i, output := range t.Outputs {
+			steps, err := exportStepsDefinition(fmt.Sprintf("%s.%s.outputs[%d].steps", path, kind, i), output.Steps)
+			if err != nil {
+				return nil, err
+			}
+			outputs = append(outputs, map[string]any{"steps": steps})
+		}
+		setName(obj, t.Name)
+		obj["parallel"] = t.Parallel
+		obj["stopOnError"] = t.StopOnError
+		if t.Timeout > 0 {
+			obj["timeout"] = t.Timeout
+		}
+		if t.AggregatorRef != "" {
+			obj["aggregator"] = t.AggregatorRef
+		}
+		if t.ExecutorService != "" {
+			obj["executorService"] = t.ExecutorService
+		}
+		obj["outputs"] = outputs
+
+	case *routestep.ErrorHandlerScope:
+		kind = "errorHandler"
+		if err := exportErrorHandlerDefinition(path+"."+kind, obj, t.ErrorHandler); err != nil {
+			return nil, err
+		}
+		steps, err := exportStepsDefinition(path+"."+kind+".steps", t.Steps)
+		if err != nil {
+			return nil, err
+		}
+		setName(obj, t.Name)
+		obj["steps"] = steps
+
+	case *routestep.Saga:
+		kind = "saga"
+		steps, err := exportStepsDefinition(path+"."+kind+".steps", t.Steps)
+		if err != nil {
+			return nil, err
+		}
+		setName(obj, t.Name)
+		if t.Propagation != "" && t.Propagation != routestep.SagaPropagationRequired {
+			obj["propagation"] = string(t.Propagation)
+		}
+		if t.Timeout > 0 {
+			obj["timeout"] = t.Timeout
+		}
+		if t.CompensationURI != "" {
+			obj["compensation"] = t.CompensationURI
+		}
+		if t.CompletionURI != "" {
+			obj["completion"] = t.CompletionURI
+		}
+		obj["steps"] = steps
+
+	default:
+		return nil, definitionErr(path, "step %T cannot be exported", s)
+	}
+
+	return map[string]any{kind: obj}, nil
+}
+
+func exportExpressionDefinition(path string, def expr.Definition) (map[string]any, error) {
+	switch def.Kind {
+	case expr.SimpleKind:
+		return map[string]any{string(expr.SimpleKind): def.Expression}, nil
+	case expr.ConstantKind:
+		if err := checkExportableValue(path+"."+expr.ConstantKind, def.Expression); err != nil {
+			return nil, err
+		}
+		return map[string]any{expr.ConstantKind: def.Expression}, nil
+	case expr.FuncKind:
+		if beanName, isBeanRef := def.Expression.(string); isBeanRef {
+			return map[string]any{expr.FuncKind: beanName}, nil
+		}
+		return nil, definitionErr(path+"."+expr.FuncKind, "inline func %T cannot be exported, register it by means of Runtime.RegisterBean and use expr.FuncRef", def.Expression)
+	case expr.JSONPathKind:
+		jsonPathExpr, isJSONPathExpr := def.Expression.(expr.JSONPathExpression)
+		if !isJSONPathExpr {
+			return nil, definitionErr(path+"."+expr.JSONPathKind, "expected expr.JSONPathExpression, but got %T", def.Expression)
+		}
+		if jsonPathExpr.JSONPathOptions == (expr.JSONPathOptions{}) {
+			return map[string]any{expr.JSONPathKind: jsonPathExpr.Path}, nil
+		}
+		obj := map[string]any{"path": jsonPathExpr.Path}
+		if jsonPathExpr.SuppressExceptions {
+			obj["suppressExceptions"] = true
+		}
+		if jsonPathExpr.Result != expr.JSONPathResultAuto {
+			obj["result"] = string(jsonPathExpr.Result)
+		}
+		return map[string]any{expr.JSONPathKind: obj}, nil
+	}
+	return nil, definitionErr(path, "unknown expression kind: %s", def.Kind)
+}
+
+func exportOnExceptionsDefinition(path string, onExceptions []routestep.OnException) ([]any, error) {
+	if len(onExceptions) == 0 {
+		return nil, nil
+	}
+	items := make([]any, 0, len(onExceptions))
+	for i, onException := range onExceptions {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		matcher, err := exportMatcherDefinition(itemPath+".matcher", onException.ErrorMatcher)
+		if err != nil {
+			return nil, err
+		}
+		obj := map[string]any{"matcher": matcher}
+		if onException.Handled {
+			obj["handled"] = true
+		}
+		if onException.Continued {
+			obj["continued"] = true
+		}
+		if len(onException.Steps) > 0 {
+			steps, err := exportStepsDefinition(itemPath+".steps", onException.Steps)
+			if err != nil {
+				return nil, err
+			}
+			obj["steps"] = steps
+		}
+		items = append(items, obj)
+	}
+	return items, nil
+}
+
+func exportOnCompletionsDefinition(path string, onCompletions []routestep.OnCompletion) ([]any, error) {
+	if len(onCompletions) == 0 {
+		return nil, nil
+	}
+	items := make([]any, 0, len(onCompletions))
+	for i, onCompletion := range onCompletions {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		steps, err := exportStepsDefinition(itemPath+".steps", onCompletion.Steps)
+		if err != nil {
+			return nil, err
+		}
+		obj := map[string]any{"steps": steps}
+		if onCompletion.Mode != "" {
+			obj["mode"] = string(onCompletion.Mode)
+		}
+		if onCompletion.OnWhen.Kind != "" {
+			onWhen, err := exportExpressionDefinition(itemPath+".onWhen", onCompletion.OnWhen)
+			if err != nil {
+				return nil, err
+			}
+			obj["onWhen"] = onWhen
+		}
+		if onCompletion.Parallel {
+			obj["parallel"] = true
+		}
+		items = append(items, obj)
+	}
+	return items, nil
+}
+
+func exportInterceptsDefinition(path string, rd *routeDocument, r *Route) error {
+	for i, intercept := range r.Intercepts {
+		steps, err := exportStepsDefinition(fmt.Sprintf("%s.intercept[%d].steps", path, i), intercept.Steps)
+		if err != nil {
+			return err
+		}
+		rd.Intercept = append(rd.Intercept, map[string]any{"steps": steps})
+	}
+	for i, intercept := range r.InterceptFroms {
+		steps, err := exportStepsDefinition(fmt.Sprintf("%s.interceptFrom[%d].steps", path, i), intercept.Steps)
+		if err != nil {
+			return err
+		}
+		obj := map[string]any{"steps": steps}
+		if intercept.URIPattern != "" {
+			obj["uri"] = intercept.URIPattern
+		}
+		rd.InterceptFrom = append(rd.InterceptFrom, obj)
+	}
+	for i, intercept := range r.InterceptSendToEndpoints {
+		steps, err := exportStepsDefinition(fmt.Sprintf("%s.interceptSendToEndpoint[%d].steps", path, i), intercept.Steps)
+		if err != nil {
+			return err
+		}
+		obj := map[string]any{"uri": intercept.URIPattern, "steps": steps}
+		if intercept.SkipSendToOriginalEndpoint {
+			obj["skipSendToOriginalEndpoint"] = true
+		}
+		rd.InterceptSendToEndpoint = append(rd.InterceptSendToEndpoint, obj)
+	}
+	return nil
+}
+
+func exportErrorHandlerDefinition(path string, obj map[string]any, errorHandler routestep.ErrorHandler) error {
+	redelivery, err := exportRedeliveryPolicyDefinition(path+".redelivery", errorHandler.Redelivery)
+	if err != nil {
+		return err
+	}
+	obj["redelivery"] = redelivery
+	if errorHandler.DeadLetterURI != "" {
+		obj["deadLetter"] = errorHandler.DeadLetterURI
+	}
+	if errorHandler.UseOriginalMessage {
+		obj["useOriginalMessage"] = true
+	}
+	return nil
+}
+
+func exportRedeliveryPolicyDefinition(path string, policy routestep.RedeliveryPolicy) (map[string]any, error) {
+	obj := map[string]any{"maximumRedeliveries": policy.MaximumRedeliveries}
+	if policy.RedeliveryDelay != 0 {
+		obj["redeliveryDelay"] = policy.RedeliveryDelay
+	}
+	if policy.BackOffMultiplier != 0 {
+		obj["backOffMultiplier"] = policy.BackOffMultiplier
+	}
+	if policy.MaximumRedeliveryDelay != 0 {
+		obj["maximumRedeliveryDelay"] = policy.MaximumRedeliveryDelay
+	}
+	if policy.Jitter != 0 {
+		obj["jitter"] = policy.Jitter
+	}
+	if policy.RetryWhile.Kind != "" {
+		retryWhile, err := exportExpressionDefinition(path+".retryWhile", policy.RetryWhile)
+		if err != nil {
+			return nil, err
+		}
+		obj["retryWhile"] = retryWhile
+	}
+	if len(policy.RetryOn) > 0 {
+		retryOn := make([]any, 0, len(policy.RetryOn))
+		for i, m := range policy.RetryOn {
+			matcher, err := exportMatcherDefinition(fmt.Sprintf("%s.retryOn[%d]", path, i), m)
+			if err != nil {
+				return nil, err
+			}
+			retryOn = append(retryOn, matcher)
+		}
+		obj["retryOn"] = retryOn
+	}
+	return obj, nil
+}
+
+func exportMatcherDefinition(path string, m errs.Matcher) (map[string]any, error) {
+	if m.IsAny() {
+		return map[string]any{"any": true}, nil
+	}
+	switch m.MatchMode {
+	case errs.MatchModeEquals, errs.MatchModeContains, errs.MatchModeRegex:
+		return map[string]any{string(m.MatchMode): m.Target}, nil
+	case errs.MatchModeIs:
+		// The error value cannot be represented in the document, only the matcher by message
+		if m.Err == nil {
+			return map[string]any{string(m.MatchMode): m.Target}, nil
+		}
+	case errs.MatchModePredicate:
+		predicate, err := exportExpressionDefinition(path+".predicate", m.Predicate)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{string(m.MatchMode): predicate}, nil
+	case errs.MatchModeNot:
+		if len(m.Matchers) == 1 {
+			operand, err := exportMatcherDefinition(path+".not", m.Matchers[0])
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{string(m.MatchMode): operand}, nil
+		}
+	case errs.MatchModeAnyOf, errs.MatchModeAllOf:
+		operands := make([]any, len(m.Matchers))
+		for i, operand := range m.Matchers {
+			exported, err := exportMatcherDefinition(fmt.Sprintf("%s.%s[%d]", path, m.MatchMode, i), operand)
+			if err != nil {
+				return nil, err
+			}
+			operands[i] = exported
+		}
+		return map[string]any{string(m.MatchMode): operands}, nil
+	}
+	return nil, definitionErr(path, "matcher mode '%s' cannot be exported", m.MatchMode)
+}
+
+func exportConvertDefinition(path string, obj map[string]any, targetType any, namedType string, params map[string]any) error {
+	typeName := namedType
+	if targetType != nil {
+		if reflectedType, isReflectType := targetType.(reflect.Type); isReflectType {
+			typeName = reflectedType.String()
+		} else {
+			typeName = reflect.TypeOf(targetType).String()
+		}
+	}
+	obj["type"] = typeName
+
+	if len(params) > 0 {
+		if err := checkExportableValue(path+".params", params); err != nil {
+			return err
+		}
+		obj["params"] = params
+	}
+	return nil
+}
+
+func exportLogLevel(path string, level api.LogLevel) (string, error) {
+	switch level {
+	case api.LogLevelError:
+		return "error", nil
+	case api.LogLevelWarn:
+		return "warn", nil
+	case api.LogLevelInfo:
+		return "info", nil
+	case api.LogLevelDebug:
+		return "debug", nil
+	}
+	return "", definitionErr(path, "unknown log level: %d", level)
+}
+
+// checkExportableValue checks that the value can be represented in JSON/YAML document without losing its type.
+func checkExportableValue(path string, v any) error {
+	switch x := v.(type) {
+	case nil, bool, string,
+		int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return nil
+	case []any:
+		for i, item := range x {
+			if err := checkExportableValue(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []string:
+		return nil
+	case map[string]any:
+		for _, k := range sortedKeys(x) {
+			if err := checkExportableValue(path+"."+k, x[k]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return definitionErr(path, "value of type %T cannot be exported", v)
+}
+
+func setName(obj map[string]any, name string) {
+	if name != "" {
+		obj["name"] = name
+	}
+}
@@ -0,0 +1,199 @@
+package camel
+
+import (
+	"fmt"
+	"github.com/paveldanilin/go-camel/internal/eip/intercept"
+	"github.com/paveldanilin/go-camel/internal/eip/pipeline"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+	"regexp"
+	"strings"
+)
+
+// routeIntercepts holds the compiled intercept clauses of the route, the runtime clauses go first.
+type routeIntercepts struct {
+	routeName string
+	// interceptor is processed before every step of the route
+	interceptor api.Processor
+	// fromInterceptor is processed before the route consumes the exchange
+	fromInterceptor api.Processor
+	sendTos         []sendToInterceptor
+}
+
+type sendToInterceptor struct {
+	uriPattern *endpointPattern
+	skip       bool
+	processor  api.Processor
+}
+
+// createIntercepts compiles the intercept clauses of the route and the runtime.
+// The clause steps are compiled without the error handler and are not intercepted themselves.
+func createIntercepts(c compilerConfig, routeDefinition *Route) (*routeIntercepts, error) {
+	routeName := routeDefinition.Name
+	intercepts := append(append([]routestep.Intercept{}, c.intercepts...), routeDefinition.Intercepts...)
+	interceptFroms := append(append([]routestep.InterceptFrom{}, c.interceptFroms...), routeDefinition.InterceptFroms...)
+	interceptSendTos := append(append([]routestep.InterceptSendToEndpoint{}, c.interceptSendTos...), routeDefinition.InterceptSendToEndpoints...)
+
+	interceptConfig := c
+	interceptConfig.errorHandler = nil
+	interceptConfig.deadLetter = nil
+	interceptConfig.onExceptionHandlers = nil
+	interceptConfig.interceptor = nil
+
+	ri := &routeIntercepts{routeName: routeName}
+
+	var steps [][]api.RouteStep
+	for i, clause := range intercepts {
+		collectStepPaths(fmt.Sprintf("%s/intercept[%d]", routeName, i), clause.Steps, c.stepPaths)
+		steps = append(steps, clause.Steps)
+	}
+	var err error
+	if ri.interceptor, err = createInterceptor(interceptConfig, routeName, "intercept", steps); err != nil {
+		return nil, err
+	}
+
+	steps = nil
+	for i, clause := range interceptFroms {
+		collectStepPaths(fmt.Sprintf("%s/interceptFrom[%d]", routeName, i), clause.Steps, c.stepPaths)
+		if compileEndpointPattern(clause.URIPattern).match(routeDefinition.From) {
+			steps = append(steps, clause.Steps)
+		}
+	}
+	if ri.fromInterceptor, err = createInterceptor(interceptConfig, routeName, "interceptFrom", steps); err != nil {
+		return nil, err
+	}
+
+	for i, clause := range interceptSendTos {
+		path := fmt.Sprintf("%s/interceptSendToEndpoint[%d]", routeName, i)
+		collectStepPaths(path, clause.Steps, c.stepPaths)
+		p, err := createProcessor(interceptConfig, routeName, clause.Steps...)
+		if err != nil {
+			return nil, fmt.Errorf("interceptSendToEndpoint[%d]: %w", i, err)
+		}
+		ri.sendTos = append(ri.sendTos, sendToInterceptor{
+			uriPattern: compileEndpointPattern(clause.URIPattern),
+			skip:       clause.SkipSendToOriginalEndpoint,
+			processor:  p,
+		})
+	}
+	return ri, nil
+}
+
+// createInterceptor compiles the steps of the clauses into the processor that stops on the first error,
+// returns nil if there are no clauses.
+func createInterceptor(c compilerConfig, routeName, name string, clauses [][]api.RouteStep) (api.Processor, error) {
+	if len(clauses) == 0 {
+		return nil, nil
+	}
+
+	pipe := pipeline.NewProcessor(routeName, name, true)
+	for i, steps := range clauses {
+		p, err := createProcessor(c, routeName, steps...)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: %w", name, i, err)
+		}
+		pipe.AddProcessor(p)
+	}
+	return pipe, nil
+}
+
+// apply returns the config to compile the route steps with the intercept clauses.
+func (ri *routeIntercepts) apply(c compilerConfig) compilerConfig {
+	c.interceptor = ri.interceptor
+	if len(ri.sendTos) > 0 {
+		c.endpointRegistry = &interceptedEndpointRegistry{EndpointRegistry: c.endpointRegistry, intercepts: ri}
+	}
+	return c
+}
+
+// interceptFrom returns the route producer that processes the interceptFrom clauses first.
+func (ri *routeIntercepts) interceptFrom(routeDefinition *Route, producer api.Producer) api.Producer {
+	if ri.fromInterceptor == nil {
+		return producer
+	}
+	return intercept.NewProcessor(ri.routeName, "interceptFrom", ri.fromInterceptor, producer).
+		SetEndpointURI(routeDefinition.From)
+}
+
+// interceptedEndpointRegistry resolves the endpoints intercepted by the interceptSendToEndpoint clauses.
+type interceptedEndpointRegistry struct {
+	EndpointRegistry
+	intercepts *routeIntercepts
+}
+
+func (r *interceptedEndpointRegistry) ResolveEndpoint(uri string) (api.Endpoint, error) {
+	endpoint, err := r.EndpointRegistry.ResolveEndpoint(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []sendToInterceptor
+	for _, sendTo := range r.intercepts.sendTos {
+		if sendTo.uriPattern.match(uri) {
+			matched = append(matched, sendTo)
+		}
+	}
+	if len(matched) == 0 {
+		return endpoint, nil
+	}
+	return &interceptedEndpoint{Endpoint: endpoint, uri: uri, routeName: r.intercepts.routeName, sendTos: matched}, nil
+}
+
+type interceptedEndpoint struct {
+	api.Endpoint
+	uri       string
+	routeName string
+	sendTos   []sendToInterceptor
+}
+
+// CreateProducer returns the producer that processes the matched clauses in the declaration order before
+// the endpoint producer.
+func (ep *interceptedEndpoint) CreateProducer() (api.Producer, error) {
+	producer, err := ep.Endpoint.CreateProducer()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(ep.sendTos) - 1; i >= 0; i-- {
+		producer = intercept.NewProcessor(ep.routeName, "interceptSendToEndpoint", ep.sendTos[i].processor, producer).
+			SetSkipTarget(ep.sendTos[i].skip).
+			SetEndpointURI(ep.uri)
+	}
+	return producer, nil
+}
+
+// endpointPattern matches the endpoint URIs: empty or '*' matches any URI,
+// otherwise the pattern is the exact URI, a wildcard (e.g. 'direct:*') or a regular expression.
+// The pattern without parameters matches the URI regardless of its parameters.
+type endpointPattern struct {
+	pattern string
+	// re is the compiled wildcard or regular expression, nil if the pattern is not a valid regular expression
+	re *regexp.Regexp
+}
+
+func compileEndpointPattern(pattern string) *endpointPattern {
+	p := &endpointPattern{pattern: pattern}
+	if pattern == "" || pattern == "*" {
+		return p
+	}
+
+	if strings.Contains(pattern, "*") && !strings.Contains(pattern, ".*") {
+		wildcard := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+		p.re = regexp.MustCompile("^" + wildcard + "$")
+	} else {
+		p.re, _ = regexp.Compile("^(?:" + pattern + ")$")
+	}
+	return p
+}
+
+func (p *endpointPattern) match(uri string) bool {
+	if p.pattern == "" || p.pattern == "*" || uri == p.pattern {
+		return true
+	}
+	if !strings.Contains(p.pattern, "?") {
+		uri, _, _ = strings.Cut(uri, "?")
+		if uri == p.pattern {
+			return true
+		}
+	}
+	return p.re != nil && p.re.MatchString(uri)
+}
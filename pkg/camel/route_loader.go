@@ -0,0 +1,1215 @@
+package camel
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+	"gopkg.in/yaml.v3"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// RouteDefinitionError reports an invalid route definition document, Path points at the offending element.
+//
+//	Example: routes[0].steps[2].choice.when[0].predicate
+type RouteDefinitionError struct {
+	Path string
+	Msg  string
+}
+
+func (e *RouteDefinitionError) Error() string {
+	if e.Path == "" {
+		return "route definition: " + e.Msg
+	}
+	return fmt.Sprintf("route definition: %s: %s", e.Path, e.Msg)
+}
+
+func definitionErr(path, format string, args ...any) error {
+	return &RouteDefinitionError{Path: path, Msg: fmt.Sprintf(format, args...)}
+}
+
+// ParseRoutes reads JSON or YAML document and returns Route definitions.
+//
+// Document example (YAML):
+//
+//	routes:
+//	  - name: sum
+//	    from: direct:sum
+//	    steps:
+//	      - setBody:
+//	          value: {simple: "header.a + header.b"}
+//	      - choice:
+//	          when:
+//	            - predicate: {simple: "body > 10"}
+//	              steps:
+//	                - to: {uri: "direct:big"}
+//	          otherwise:
+//	            - log: {level: info, message: "small sum: ${body}"}
+//
+// Expressions are defined as {simple: "..."}, {constant: ...} or {func: "beanName"}.
+// Funcs (fn step) are resolved from FuncRegistry, aggregators, func expressions
+// and unmarshal target types are resolved from BeanRegistry when the route is registered.
+func ParseRoutes(r io.Reader) ([]*Route, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := decodeDocument(data)
+	if err != nil {
+		return nil, definitionErr("", "%s", err)
+	}
+
+	return parseRoutesDocument(doc)
+}
+
+// LoadRoutes parses route definitions (see ParseRoutes) and registers them in the current Runtime.
+// The routes are registered only if all of them are valid.
+func (rt *Runtime) LoadRoutes(r io.Reader) error {
+	routes, err := ParseRoutes(r)
+	if err != nil {
+		return err
+	}
+	return rt.registerRoutes(routes)
+}
+
+// decodeDocument decodes JSON (if the document starts with '{' or '[') or YAML document into generic values.
+func decodeDocument(data []byte) (any, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errors.New("empty document")
+	}
+
+	var doc any
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.UseNumber()
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return normalizeJSONNumbers(doc), nil
+	}
+
+	if err := yaml.Unmarshal(trimmed, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	return doc, nil
+}
+
+// normalizeJSONNumbers converts json.Number into int (if possible) or float64, so JSON and YAML documents
+// produce the same values.
+func normalizeJSONNumbers(v any) any {
+	switch x := v.(type) {
+	case json.Number:
+		if i, err := x.Int64(); err == nil && i >= math.MinInt && i <= math.MaxInt {
+			return int(i)
+		}
+		f, _ := x.Float64()
+		return f
+	case map[string]any:
+		for k, vv := range x {
+			x[k] = normalizeJSONNumbers(vv)
+		}
+		return x
+	case []any:
+		for i, vv := range x {
+			x[i] = normalizeJSONNumbers(vv)
+		}
+		return x
+	}
+	return v
+}
+
+func parseRoutesDocument(doc any) ([]*Route, error) {
+	root, err := definitionObject("", doc, "routes")
+	if err != nil {
+		return nil, err
+	}
+	items, err := definitionList("routes", root["routes"])
+	if err != nil {
+		return nil, err
+	}
+
+	routes := make([]*Route, 0, len(items))
+	for i, item := range items {
+		r, err := parseRouteDefinition(fmt.Sprintf("routes[%d]", i), item)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, r)
+	}
+	return routes, nil
+}
+
+func parseRouteDefinition(path string, v any) (*Route, error) {
+	obj, err := definitionObject(path, v, "name", "from", "errorHandler", "onException", "onCompletion",
+		"intercept", "interceptFrom", "interceptSendToEndpoint", "steps")
+	if err != nil {
+		return nil, err
+	}
+	name, err := definitionString(path, obj, "name", true)
+	if err != nil {
+		return nil, err
+	}
+	from, err := definitionString(path, obj, "from", true)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := parseStepsDefinition(path+".steps", obj["steps"], true)
+	if err != nil {
+		return nil, err
+	}
+
+	var errorHandler *routestep.ErrorHandler
+	if obj["errorHandler"] != nil {
+		errorHandlerObj, err := definitionObject(path+".errorHandler", obj["errorHandler"], errorHandlerKeys...)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := parseErrorHandlerDefinition(path+".errorHandler", errorHandlerObj)
+		if err != nil {
+			return nil, err
+		}
+		errorHandler = &parsed
+	}
+
+	onExceptions, err := parseOnExceptionsDefinition(path+".onException", obj["onException"])
+	if err != nil {
+		return nil, err
+	}
+	onCompletions, err := parseOnCompletionsDefinition(path+".onCompletion", obj["onCompletion"])
+	if err != nil {
+		return nil, err
+	}
+	r := &Route{
+		Name:          name,
+		From:          from,
+		Steps:         steps,
+		ErrorHandler:  errorHandler,
+		OnExceptions:  onExceptions,
+		OnCompletions: onCompletions,
+	}
+	if err := parseInterceptsDefinition(path, obj, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// parseInterceptsDefinition parses intercept: [{steps: [...]}], interceptFrom: [{uri: 'direct:*', steps: [...]}]
+// and interceptSendToEndpoint: [{uri: 'direct:*', skipSendToOriginalEndpoint: true, steps: [...]}].
+func parseInterceptsDefinition(path string, obj map[string]any, r *Route) error {
+	if obj["intercept"] != nil {
+		items, err := definitionList(path+".intercept", obj["intercept"])
+		if err != nil {
+			return err
+		}
+		for i, item := range items {
+			itemPath := fmt.Sprintf("%s.intercept[%d]", path, i)
+			itemObj, err := definitionObject(itemPath, item, "steps")
+			if err != nil {
+				return err
+			}
+			steps, err := parseStepsDefinition(itemPath+".steps", itemObj["steps"], true)
+			if err != nil {
+				return err
+			}
+			r.Intercepts = append(r.Intercepts, routestep.Intercept{Steps: steps})
+		}
+	}
+
+	if obj["interceptFrom"] != nil {
+		items, err := definitionList(path+".interceptFrom", obj["interceptFrom"])
+		if err != nil {
+			return err
+		}
+		for i, item := range items {
+			itemPath := fmt.Sprintf("%s.interceptFrom[%d]", path, i)
+			itemObj, err := definitionObject(itemPath, item, "uri", "steps")
+			if err != nil {
+				return err
+			}
+			intercept := routestep.InterceptFrom{}
+			if intercept.URIPattern, err = definitionString(itemPath, itemObj, "uri", false); err != nil {
+				return err
+			}
+			if intercept.Steps, err = parseStepsDefinition(itemPath+".steps", itemObj["steps"], true); err != nil {
+				return err
+			}
+			r.InterceptFroms = append(r.InterceptFroms, intercept)
+		}
+	}
+
+	if obj["interceptSendToEndpoint"] != nil {
+		items, err := definitionList(path+".interceptSendToEndpoint", obj["interceptSendToEndpoint"])
+		if err != nil {
+			return err
+		}
+		for i, item := range items {
+			itemPath := fmt.Sprintf("%s.interceptSendToEndpoint[%d]", path, i)
+			itemObj, err := definitionObject(itemPath, item, "uri", "skipSendToOriginalEndpoint", "steps")
+			if err != nil {
+				return err
+			}
+			intercept := routestep.InterceptSendToEndpoint{}
+			if intercept.URIPattern, err = definitionString(itemPath, itemObj, "uri", true); err != nil {
+				return err
+			}
+			if intercept.SkipSendToOriginalEndpoint, err = definitionBool(itemPath, itemObj, "skipSendToOriginalEndpoint"); err != nil {
+				return err
+			}
+			if intercept.Steps, err = parseStepsDefinition(itemPath+".steps", itemObj["steps"], true); err != nil {
+				return err
+			}
+			r.InterceptSendToEndpoints = append(r.InterceptSendToEndpoints, intercept)
+		}
+	}
+	return nil
+}
+
+// parseOnCompletionsDefinition parses [{mode: onCompleteOnly, onWhen: {...}, parallel: true, steps: [...]}].
+func parseOnCompletionsDefinition(path string, v any) ([]routestep.OnCompletion, error) {
+	if v == nil {
+		return nil, nil
+	}
+	items, err := definitionList(path, v)
+	if err != nil {
+		return nil, err
+	}
+
+	onCompletions := make([]routestep.OnCompletion, 0, len(items))
+	for i, item := range items {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		obj, err := definitionObject(itemPath, item, "mode", "onWhen", "parallel", "steps")
+		if err != nil {
+			return nil, err
+		}
+		onCompletion := routestep.OnCompletion{}
+		mode, err := definitionString(itemPath, obj, "mode", false)
+		if err != nil {
+			return nil, err
+		}
+		onCompletion.Mode = routestep.OnCompletionMode(mode)
+		if obj["onWhen"] != nil {
+			if onCompletion.OnWhen, err = parseExpressionDefinition(itemPath+".onWhen", obj["onWhen"]); err != nil {
+				return nil, err
+			}
+		}
+		if onCompletion.Parallel, err = definitionBool(itemPath, obj, "parallel"); err != nil {
+			return nil, err
+		}
+		if onCompletion.Steps, err = parseStepsDefinition(itemPath+".steps", obj["steps"], true); err != nil {
+			return nil, err
+		}
+		onCompletions = append(onCompletions, onCompletion)
+	}
+	return onCompletions, nil
+}
+
+// parseOnExceptionsDefinition parses [{matcher: {...}, handled: true, continued: false, steps: [...]}].
+func parseOnExceptionsDefinition(path string, v any) ([]routestep.OnException, error) {
+	if v == nil {
+		return nil, nil
+	}
+	items, err := definitionList(path, v)
+	if err != nil {
+		return nil, err
+	}
+
+	onExceptions := make([]routestep.OnException, 0, len(items))
+	for i, item := range items {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		obj, err := definitionObject(itemPath, item, "matcher", "handled", "continued", "steps")
+		if err != nil {
+			return nil, err
+		}
+		onException := routestep.OnException{}
+		if onException.ErrorMatcher, err = parseMatcherDefinition(itemPath+".matcher", obj["matcher"]); err != nil {
+			return nil, err
+		}
+		if onException.Handled, err = definitionBool(itemPath, obj, "handled"); err != nil {
+			return nil, err
+		}
+		if onException.Continued, err = definitionBool(itemPath, obj, "continued"); err != nil {
+			return nil, err
+		}
+		if onException.Steps, err = parseStepsDefinition(itemPath+".steps", obj["steps"], false); err != nil {
+			return nil, err
+		}
+		onExceptions = append(onExceptions, onException)
+	}
+	return onExceptions, nil
+}
+
+func parseStepsDefinition(path string, v any, required bool) ([]api.RouteStep, error) {
+	if v == nil {
+		if required {
+			return nil, definitionErr(path, "at least one step is required")
+		}
+		return nil, nil
+	}
+	items, err := definitionList(path, v)
+	if err != nil {
+		return nil, err
+	}
+	if required && len(items) == 0 {
+		return nil, definitionErr(path, "at least one step is required")
+	}
+
+	steps := make([]api.RouteStep, 0, len(items))
+	for i, item := range items {
+		step, err := parseStepDefinition(fmt.Sprintf("%s[%d]", path, i), item)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// parseStepDefinition parses a single step, the step is an object with exactly one key - the step kind.
+func parseStepDefinition(path string, v any) (api.RouteStep, error) {
+	m, isObject := v.(map[string]any)
+	if !isObject {
+		return nil, definitionErr(path, "expected object, but got %s", definitionType(v))
+	}
+	if len(m) != 1 {
+		return nil, definitionErr(path, "step must have exactly one kind, but got: %s", strings.Join(sortedKeys(m), ", "))
+	}
+
+	var kind string
+	for k := range m {
+		kind = k
+	}
+	path = path + "." + kind
+	v = m[kind]
+
+	if obj, isObject := v.(map[string]any); isObject {
+		if _, err := definitionString(path, obj, "name", false); err != nil {
+			return nil, err
+		}
+	}
+
+	switch kind {
+	case "setBody":
+		obj, err := definitionObject(path, v, "name", "value")
+		if err != nil {
+			return nil, err
+		}
+		value, err := parseExpressionDefinition(path+".value", obj["value"])
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.SetBody{Name: optString(obj, "name"), BodyValue: value}, nil
+
+	case "setHeader":
+		obj, err := definitionObject(path, v, "name", "header", "value")
+		if err != nil {
+			return nil, err
+		}
+		header, err := definitionString(path, obj, "header", true)
+		if err != nil {
+			return nil, err
+		}
+		value, err := parseExpressionDefinition(path+".value", obj["value"])
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.SetHeader{Name: optString(obj, "name"), HeaderName: header, HeaderValue: value}, nil
+
+	case "setProperty":
+		obj, err := definitionObject(path, v, "name", "property", "value")
+		if err != nil {
+			return nil, err
+		}
+		property, err := definitionString(path, obj, "property", true)
+		if err != nil {
+			return nil, err
+		}
+		value, err := parseExpressionDefinition(path+".value", obj["value"])
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.SetProperty{Name: optString(obj, "name"), PropertyName: property, PropertyValue: value}, nil
+
+	case "removeHeader":
+		obj, err := definitionObject(path, v, "name", "headers")
+		if err != nil {
+			return nil, err
+		}
+		headers, err := definitionStringList(path+".headers", obj["headers"])
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.RemoveHeader{Name: optString(obj, "name"), HeaderNames: headers}, nil
+
+	case "removeProperty":
+		obj, err := definitionObject(path, v, "name", "properties")
+		if err != nil {
+			return nil, err
+		}
+		properties, err := definitionStringList(path+".properties", obj["properties"])
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.RemoveProperty{Name: optString(obj, "name"), PropertyNames: properties}, nil
+
+	case "setPattern":
+		obj, err := definitionObject(path, v, "name", "pattern")
+		if err != nil {
+			return nil, err
+		}
+		pattern, err := definitionString(path, obj, "pattern", true)
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.SetPattern{Name: optString(obj, "name"), Pattern: exchange.ExchangePattern(pattern)}, nil
+
+	case "threads":
+		obj, err := definitionObject(path, v, "name", "profile")
+		if err != nil {
+			return nil, err
+		}
+		profile, err := definitionString(path, obj, "profile", true)
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.Threads{Name: optString(obj, "name"), Profile: profile}, nil
+
+	case "convertBody":
+		obj, err := definitionObject(path, v, "name", "type", "params")
+		if err != nil {
+			return nil, err
+		}
+		typeName, err := definitionString(path, obj, "type", true)
+		if err != nil {
+			return nil, err
+		}
+		params, err := definitionParams(path+".params", obj["params"])
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.ConvertBody{Name: optString(obj, "name"), NamedType: typeName, Params: params}, nil
+
+	case "convertHeader":
+		obj, err := definitionObject(path, v, "name", "header", "type", "params")
+		if err != nil {
+			return nil, err
+		}
+		header, err := definitionString(path, obj, "header", true)
+		if err != nil {
+			return nil, err
+		}
+		typeName, err := definitionString(path, obj, "type", true)
+		if err != nil {
+			return nil, err
+		}
+		params, err := definitionParams(path+".params", obj["params"])
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.ConvertHeader{Name: optString(obj, "name"), HeaderName: header, NamedType: typeName, Params: params}, nil
+
+	case "convertProperty":
+		obj, err := definitionObject(path, v, "name", "property", "type", "params")
+		if err != nil {
+			return nil, err
+		}
+		property, err := definitionString(path, obj, "property", true)
+		if err != nil {
+			return nil, err
+		}
+		typeName, err := definitionString(path, obj, "type", true)
+		if err != nil {
+			return nil, err
+		}
+		params, err := definitionParams(path+".params", obj["params"])
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.ConvertProperty{Name: optString(obj, "name"), PropertyName: property, NamedType: typeName, Params: params}, nil
+
+	case "to":
+		obj, err := definitionObject(path, v, "name", "uri")
+		if err != nil {
+			return nil, err
+		}
+		uri, err := definitionString(path, obj, "uri", true)
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.To{Name: optString(obj, "name"), URI: uri}, nil
+
+	case "log":
+		obj, err := definitionObject(path, v, "name", "level", "message")
+		if err != nil {
+			return nil, err
+		}
+		message, err := definitionString(path, obj, "message", true)
+		if err != nil {
+			return nil, err
+		}
+		levelName, err := definitionString(path, obj, "level", false)
+		if err != nil {
+			return nil, err
+		}
+		level, err := parseLogLevel(path+".level", levelName)
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.Log{Name: optString(obj, "name"), Msg: message, Level: level}, nil
+
+	case "fn":
+		obj, err := definitionObject(path, v, "name", "func")
+		if err != nil {
+			return nil, err
+		}
+		funcName, err := definitionString(path, obj, "func", true)
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.Fn{Name: optString(obj, "name"), Func: funcName}, nil
+
+	case "delay":
+		obj, err := definitionObject(path, v, "name", "duration")
+		if err != nil {
+			return nil, err
+		}
+		duration, err := definitionInt(path, obj, "duration", true)
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.Delay{Name: optString(obj, "name"), Duration: int64(duration)}, nil
+
+	case "marshal":
+		obj, err := definitionObject(path, v, "name", "format")
+		if err != nil {
+			return nil, err
+		}
+		format, err := definitionString(path, obj, "format", true)
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.Marshal{Name: optString(obj, "name"), Format: format}, nil
+
+	case "unmarshal":
+		obj, err := definitionObject(path, v, "name", "format", "targetType")
+		if err != nil {
+			return nil, err
+		}
+		format, err := definitionString(path, obj, "format", true)
+		if err != nil {
+			return nil, err
+		}
+		targetTypeRef, err := definitionString(path, obj, "targetType", true)
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.Unmarshal{Name: optString(obj, "name"), Format: format, TargetTypeRef: targetTypeRef}, nil
+
+	case "setError":
+		obj, err := definitionObject(path, v, "name", "message")
+		if err != nil {
+			return nil, err
+		}
+		message, err := definitionString(path, obj, "message", true)
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.SetError{Name: optString(obj, "name"), Error: errors.New(message)}, nil
+
+	case "pipeline":
+		obj, err := definitionObject(path, v, "name", "stopOnError", "steps")
+		if err != nil {
+			return nil, err
+		}
+		stopOnError, err := definitionBool(path, obj, "stopOnError")
+		if err != nil {
+			return nil, err
+		}
+		steps, err := parseStepsDefinition(path+".steps", obj["steps"], true)
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.Pipeline{Name: optString(obj, "name"), StoOnError: stopOnError, Steps: steps}, nil
+
+	case "choice":
+		return parseChoiceDefinition(path, v)
+
+	case "try":
+		return parseTryDefinition(path, v)
+
+	case "multicast":
+		return parseMulticastDefinition(path, v)
+
+	case "errorHandler":
+		obj, err := definitionObject(path, v, append([]string{"name", "steps"}, errorHandlerKeys...)...)
+		if err != nil {
+			return nil, err
+		}
+		errorHandler, err := parseErrorHandlerDefinition(path, obj)
+		if err != nil {
+			return nil, err
+		}
+		steps, err := parseStepsDefinition(path+".steps", obj["steps"], true)
+		if err != nil {
+			return nil, err
+		}
+		return &routestep.ErrorHandlerScope{Name: optString(obj, "name"), ErrorHandler: errorHandler, Steps: steps}, nil
+
+	case "saga":
+		return parseSagaDefinition(path, v)
+	}
+
+	return nil, definitionErr(path, "unknown step kind")
+}
+
+func parseSagaDefinition(path string, v any) (api.RouteStep, error) {
+	obj, err := definitionObject(path, v, "name", "propagation", "timeout", "compensation", "completion", "steps")
+	if err != nil {
+		return nil, err
+	}
+	propagation, err := definitionString(path, obj, "propagation", false)
+	if err != nil {
+		return nil, err
+	}
+	if propagation == "" {
+		propagation = string(routestep.SagaPropagationRequired)
+	}
+	timeout, err := definitionInt(path, obj, "timeout", false)
+	if err != nil {
+		return nil, err
+	}
+	compensation, err := definitionString(path, obj, "compensation", false)
+	if err != nil {
+		return nil, err
+	}
+	completion, err := definitionString(path, obj, "completion", false)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := parseStepsDefinition(path+".steps", obj["steps"], true)
+	if err != nil {
+		return nil, err
+	}
+	return &routestep.Saga{
+		Name:            optString(obj, "name"),
+		Propagation:     routestep.SagaPropagation(propagation),
+		Timeout:         int64(timeout),
+		CompensationURI: compensation,
+		CompletionURI:   completion,
+		Steps:           steps,
+	}, nil
+}
+
+func parseChoiceDefinition(path string, v any) (api.RouteStep, error) {
+	obj, err := definitionObject(path, v, "name", "when", "otherwise")
+	if err != nil {
+		return nil, err
+	}
+	whenItems, err := definitionList(path+".when", obj["when"])
+	if err != nil {
+		return nil, err
+	}
+	if len(whenItems) == 0 {
+		return nil, definitionErr(path+".when", "at least one 'when' is required")
+	}
+
+	step := &routestep.Choice{Name: optString(obj, "name")}
+	for i, item := range whenItems {
+		whenPath := fmt.Sprintf("%s.when[%d]", path, i)
+		whenObj, err := definitionObject(whenPath, item, "predicate", "steps")
+		if err != nil {
+			return nil, err
+		}
+		predicate, err := parseExpressionDefinition(whenPath+".predicate", whenObj["predicate"])
+		if err != nil {
+			return nil, err
+		}
+		steps, err := parseStepsDefinition(whenPath+".steps", whenObj["steps"], true)
+		if err != nil {
+			return nil, err
+		}
+		step.WhenCases = append(step.WhenCases, routestep.ChoiceWhen{Predicate: predicate, Steps: steps})
+	}
+
+	step.Otherwise, err = parseStepsDefinition(path+".otherwise", obj["otherwise"], false)
+	if err != nil {
+		return nil, err
+	}
+	return step, nil
+}
+
+func parseTryDefinition(path string, v any) (api.RouteStep, error) {
+	obj, err := definitionObject(path, v, "name", "steps", "catch", "finally")
+	if err != nil {
+		return nil, err
+	}
+	steps, err := parseStepsDefinition(path+".steps", obj["steps"], true)
+	if err != nil {
+		return nil, err
+	}
+
+	step := &routestep.Try{Name: optString(obj, "name"), Steps: steps}
+
+	if obj["catch"] != nil {
+		catchItems, err := definitionList(path+".catch", obj["catch"])
+		if err != nil {
+			return nil, err
+		}
+		for i, item := range catchItems {
+			catchPath := fmt.Sprintf("%s.catch[%d]", path, i)
+			catchObj, err := definitionObject(catchPath, item, "matcher", "steps")
+			if err != nil {
+				return nil, err
+			}
+			matcher, err := parseMatcherDefinition(catchPath+".matcher", catchObj["matcher"])
+			if err != nil {
+				return nil, err
+			}
+			catchSteps, err := parseStepsDefinition(catchPath+".steps", catchObj["steps"], true)
+			if err != nil {
+				return nil, err
+			}
+			step.WhenCatches = append(step.WhenCatches, routestep.CatchWhen{ErrorMatcher: matcher, Steps: catchSteps})
+		}
+	}
+
+	step.FinallySteps, err = parseStepsDefinition(path+".finally", obj["finally"], false)
+	if err != nil {
+		return nil, err
+	}
+	return step, nil
+}
+
+func parseMulticastDefinition(path string, v any) (api.RouteStep, error) {
+	obj, err := definitionObject(path, v, "name", "parallel", "stopOnError", "timeout", "aggregator", "executorService", "outputs")
+	if err != nil {
+		return nil, err
+	}
+	parallel, err := definitionBool(path, obj, "parallel")
+	if err != nil {
+		return nil, err
+	}
+	stopOnError, err := definitionBool(path, obj, "stopOnError")
+	if err != nil {
+		return nil, err
+	}
+	aggregatorRef, err := definitionString(path, obj, "aggregator", false)
+	if err != nil {
+		return nil, err
+	}
+	executorService, err := definitionString(path, obj, "executorService", false)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := definitionInt(path, obj, "timeout", false)
+	if err != nil {
+		return nil, err
+	}
+	outputItems, err := definitionList(path+".outputs", obj["outputs"])
+	if err != nil {
+		return nil, err
+	}
+	if len(outputItems) == 0 {
+		return nil, definitionErr(path+".outputs", "at least one output is required")
+	}
+
+	step := &routestep.Multicast{
+		Name:            optString(obj, "name"),
+		Parallel:        parallel,
+		StopOnError:     stopOnError,
+		Timeout:         int64(timeout),
+		AggregatorRef:   aggregatorRef,
+		ExecutorService: executorService,
+	}
+	for i, item := range outputItems {
+		outputPath := fmt.Sprintf("%s.outputs[%d]", path, i)
+		outputObj, err := definitionObject(outputPath, item, "steps")
+		if err != nil {
+			return nil, err
+		}
+		steps, err := parseStepsDefinition(outputPath+".steps", outputObj["steps"], true)
+		if err != nil {
+			return nil, err
+		}
+		step.Outputs = append(step.Outputs, routestep.OutputProcess{Steps: steps})
+	}
+	return step, nil
+}
+
+// errorHandlerKeys are the properties of the route error handler and errorHandler step.
+var errorHandlerKeys = []string{"redelivery", "deadLetter", "useOriginalMessage"}
+
+// parseErrorHandlerDefinition parses {redelivery: {...}, deadLetter: "uri", useOriginalMessage: true}.
+func parseErrorHandlerDefinition(path string, obj map[string]any) (routestep.ErrorHandler, error) {
+	errorHandler := routestep.ErrorHandler{}
+	var err error
+	if errorHandler.Redelivery, err = parseRedeliveryPolicyDefinition(path+".redelivery", obj["redelivery"]); err != nil {
+		return errorHandler, err
+	}
+	if errorHandler.DeadLetterURI, err = definitionString(path, obj, "deadLetter", false); err != nil {
+		return errorHandler, err
+	}
+	if errorHandler.UseOriginalMessage, err = definitionBool(path, obj, "useOriginalMessage"); err != nil {
+		return errorHandler, err
+	}
+	return errorHandler, nil
+}
+
+// parseRedeliveryPolicyDefinition parses {maximumRedeliveries: 3, redeliveryDelay: 100,...}, nil - no redelivery.
+func parseRedeliveryPolicyDefinition(path string, v any) (routestep.RedeliveryPolicy, error) {
+	policy := routestep.RedeliveryPolicy{}
+	if v == nil {
+		return policy, nil
+	}
+	obj, err := definitionObject(path, v, "maximumRedeliveries", "redeliveryDelay",
+		"backOffMultiplier", "maximumRedeliveryDelay", "jitter", "retryWhile", "retryOn")
+	if err != nil {
+		return policy, err
+	}
+	if policy.MaximumRedeliveries, err = definitionInt(path, obj, "maximumRedeliveries", false); err != nil {
+		return policy, err
+	}
+	redeliveryDelay, err := definitionInt(path, obj, "redeliveryDelay", false)
+	if err != nil {
+		return policy, err
+	}
+	policy.RedeliveryDelay = int64(redeliveryDelay)
+	maximumRedeliveryDelay, err := definitionInt(path, obj, "maximumRedeliveryDelay", false)
+	if err != nil {
+		return policy, err
+	}
+	policy.MaximumRedeliveryDelay = int64(maximumRedeliveryDelay)
+	if policy.BackOffMultiplier, err = definitionFloat(path, obj, "backOffMultiplier"); err != nil {
+		return policy, err
+	}
+	if policy.Jitter, err = definitionFloat(path, obj, "jitter"); err != nil {
+		return policy, err
+	}
+	if obj["retryWhile"] != nil {
+		if policy.RetryWhile, err = parseExpressionDefinition(path+".retryWhile", obj["retryWhile"]); err != nil {
+			return policy, err
+		}
+	}
+	if obj["retryOn"] != nil {
+		items, err := definitionList(path+".retryOn", obj["retryOn"])
+		if err != nil {
+			return policy, err
+		}
+		for i, item := range items {
+			matcher, err := parseMatcherDefinition(fmt.Sprintf("%s.retryOn[%d]", path, i), item)
+			if err != nil {
+				return policy, err
+			}
+			policy.RetryOn = append(policy.RetryOn, matcher)
+		}
+	}
+	return policy, nil
+}
+
+// parseExpressionDefinition parses {simple: "..."}, {constant: ...}, {func: "beanName"}
+// or {jsonpath: "$..."} ({jsonpath: {path: "$...", suppressExceptions: true, result: single|list}}).
+func parseExpressionDefinition(path string, v any) (expr.Definition, error) {
+	obj, err := definitionObject(path, v, string(expr.SimpleKind), expr.ConstantKind, expr.FuncKind, expr.JSONPathKind)
+	if err != nil {
+		return expr.Definition{}, err
+	}
+	if len(obj) != 1 {
+		return expr.Definition{}, definitionErr(path, "expected exactly one of: simple, constant, func, jsonpath")
+	}
+
+	if _, isConstant := obj[expr.ConstantKind]; isConstant {
+		return expr.Constant(obj[expr.ConstantKind]), nil
+	}
+	if _, isSimple := obj[string(expr.SimpleKind)]; isSimple {
+		simple, err := definitionString(path, obj, string(expr.SimpleKind), true)
+		if err != nil {
+			return expr.Definition{}, err
+		}
+		return expr.Simple(simple), nil
+	}
+	if jsonPath, isJSONPath := obj[expr.JSONPathKind]; isJSONPath {
+		return parseJSONPathDefinition(joinDefinitionPath(path, expr.JSONPathKind), jsonPath)
+	}
+	funcRef, err := definitionString(path, obj, expr.FuncKind, true)
+	if err != nil {
+		return expr.Definition{}, err
+	}
+	return expr.FuncRef(funcRef), nil
+}
+
+func parseJSONPathDefinition(path string, v any) (expr.Definition, error) {
+	if jsonPath, isString := v.(string); isString {
+		if strings.TrimSpace(jsonPath) == "" {
+			return expr.Definition{}, definitionErr(path, "required")
+		}
+		return expr.JSONPath(jsonPath), nil
+	}
+
+	obj, err := definitionObject(path, v, "path", "suppressExceptions", "result")
+	if err != nil {
+		return expr.Definition{}, err
+	}
+	jsonPath, err := definitionString(path, obj, "path", true)
+	if err != nil {
+		return expr.Definition{}, err
+	}
+	options := expr.JSONPathOptions{}
+	if options.SuppressExceptions, err = definitionBool(path, obj, "suppressExceptions"); err != nil {
+		return expr.Definition{}, err
+	}
+	result, err := definitionString(path, obj, "result", false)
+	if err != nil {
+		return expr.Definition{}, err
+	}
+	options.Result = expr.JSONPathResult(result)
+	return expr.JSONPathWithOptions(jsonPath, options), nil
+}
+
+// parseMatcherDefinition parses {any: true}, {equals: "..."}, {contains: "..."}, {regex: "..."},
+// {is: "error message"}, {predicate: {...}}, {anyOf: [...]}, {allOf: [...]} or {not: {...}}.
+func parseMatcherDefinition(path string, v any) (errs.Matcher, error) {
+	obj, err := definitionObject(path, v, "any", string(errs.MatchModeEquals), string(errs.MatchModeContains),
+		string(errs.MatchModeRegex), string(errs.MatchModeIs), string(errs.MatchModePredicate),
+		string(errs.MatchModeAnyOf), string(errs.MatchModeAllOf), string(errs.MatchModeNot))
+	if err != nil {
+		return errs.Matcher{}, err
+	}
+	if len(obj) != 1 {
+		return errs.Matcher{}, definitionErr(path, "expected exactly one of: any, equals, contains, regex, is, predicate, anyOf, allOf, not")
+	}
+
+	if _, isAny := obj["any"]; isAny {
+		anyErr, err := definitionBool(path, obj, "any")
+		if err != nil {
+			return errs.Matcher{}, err
+		}
+		if !anyErr {
+			return errs.Matcher{}, definitionErr(path+".any", "must be true")
+		}
+		return errs.Any(), nil
+	}
+
+	if predicate, isPredicate := obj[string(errs.MatchModePredicate)]; isPredicate {
+		definition, err := parseExpressionDefinition(path+".predicate", predicate)
+		if err != nil {
+			return errs.Matcher{}, err
+		}
+		return errs.Predicate(definition), nil
+	}
+
+	if operand, isNot := obj[string(errs.MatchModeNot)]; isNot {
+		matcher, err := parseMatcherDefinition(path+".not", operand)
+		if err != nil {
+			return errs.Matcher{}, err
+		}
+		return errs.Not(matcher), nil
+	}
+
+	for _, mode := range []errs.MatchMode{errs.MatchModeAnyOf, errs.MatchModeAllOf} {
+		if _, exists := obj[string(mode)]; exists {
+			items, err := definitionList(path+"."+string(mode), obj[string(mode)])
+			if err != nil {
+				return errs.Matcher{}, err
+			}
+			operands := make([]errs.Matcher, len(items))
+			for i, item := range items {
+				if operands[i], err = parseMatcherDefinition(fmt.Sprintf("%s.%s[%d]", path, mode, i), item); err != nil {
+					return errs.Matcher{}, err
+				}
+			}
+			return errs.Matcher{MatchMode: mode, Matchers: operands}, nil
+		}
+	}
+
+	for _, mode := range []errs.MatchMode{errs.MatchModeEquals, errs.MatchModeContains, errs.MatchModeRegex, errs.MatchModeIs} {
+		if _, exists := obj[string(mode)]; exists {
+			target, err := definitionString(path, obj, string(mode), true)
+			if err != nil {
+				return errs.Matcher{}, err
+			}
+			return errs.Matcher{MatchMode: mode, Target: target}, nil
+		}
+	}
+	return errs.Matcher{}, definitionErr(path, "unknown matcher")
+}
+
+func parseLogLevel(path, level string) (api.LogLevel, error) {
+	switch strings.ToLower(level) {
+	case "", "info":
+		return api.LogLevelInfo, nil
+	case "error":
+		return api.LogLevelError, nil
+	case "warn":
+		return api.LogLevelWarn, nil
+	case "debug":
+		return api.LogLevelDebug, nil
+	}
+	return 0, definitionErr(path, "unknown log level '%s', expected one of: error, warn, info, debug", level)
+}
+
+// definitionObject checks that v is an object and contains only allowed keys.
+func definitionObject(path string, v any, allowedKeys ...string) (map[string]any, error) {
+	m, isObject := v.(map[string]any)
+	if !isObject {
+		return nil, definitionErr(path, "expected object, but got %s", definitionType(v))
+	}
+	for _, k := range sortedKeys(m) {
+		allowed := false
+		for _, allowedKey := range allowedKeys {
+			if k == allowedKey {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, definitionErr(joinDefinitionPath(path, k), "unknown property, expected one of: %s", strings.Join(allowedKeys, ", "))
+		}
+	}
+	return m, nil
+}
+
+func definitionList(path string, v any) ([]any, error) {
+	if v == nil {
+		return nil, definitionErr(path, "required")
+	}
+	l, isList := v.([]any)
+	if !isList {
+		return nil, definitionErr(path, "expected list, but got %s", definitionType(v))
+	}
+	return l, nil
+}
+
+func definitionString(path string, obj map[string]any, key string, required bool) (string, error) {
+	v, exists := obj[key]
+	if !exists || v == nil {
+		if required {
+			return "", definitionErr(joinDefinitionPath(path, key), "required")
+		}
+		return "", nil
+	}
+	s, isString := v.(string)
+	if !isString {
+		return "", definitionErr(joinDefinitionPath(path, key), "expected string, but got %s", definitionType(v))
+	}
+	if required && strings.TrimSpace(s) == "" {
+		return "", definitionErr(joinDefinitionPath(path, key), "must be not empty string")
+	}
+	return s, nil
+}
+
+func definitionBool(path string, obj map[string]any, key string) (bool, error) {
+	v, exists := obj[key]
+	if !exists || v == nil {
+		return false, nil
+	}
+	b, isBool := v.(bool)
+	if !isBool {
+		return false, definitionErr(joinDefinitionPath(path, key), "expected boolean, but got %s", definitionType(v))
+	}
+	return b, nil
+}
+
+func definitionInt(path string, obj map[string]any, key string, required bool) (int, error) {
+	v, exists := obj[key]
+	if !exists || v == nil {
+		if required {
+			return 0, definitionErr(joinDefinitionPath(path, key), "required")
+		}
+		return 0, nil
+	}
+	i, isInt := v.(int)
+	if !isInt {
+		return 0, definitionErr(joinDefinitionPath(path, key), "expected integer, but got %s", definitionType(v))
+	}
+	return i, nil
+}
+
+// definitionFloat accepts both integer and floating point numbers.
+func definitionFloat(path string, obj map[string]any, key string) (float64, error) {
+	v, exists := obj[key]
+	if !exists || v == nil {
+		return 0, nil
+	}
+	switch f := v.(type) {
+	case float64:
+		return f, nil
+	case int:
+		return float64(f), nil
+	}
+	return 0, definitionErr(joinDefinitionPath(path, key), "expected number, but got %s", definitionType(v))
+}
+
+func definitionStringList(path string, v any) ([]string, error) {
+	items, err := definitionList(path, v)
+	if err != nil {
+		return nil, err
+	}
+	strs := make([]string, len(items))
+	for i, item := range items {
+		s, isString := item.(string)
+		if !isString {
+			return nil, definitionErr(fmt.Sprintf("%s[%d]", path, i), "expected string, but got %s", definitionType(item))
+		}
+		strs[i] = s
+	}
+	return strs, nil
+}
+
+func definitionParams(path string, v any) (map[string]any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	m, isObject := v.(map[string]any)
+	if !isObject {
+		return nil, definitionErr(path, "expected object, but got %s", definitionType(v))
+	}
+	return m, nil
+}
+
+func optString(obj map[string]any, key string) string {
+	s, _ := obj[key].(string)
+	return s
+}
+
+func definitionType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "list"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, float64:
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func joinDefinitionPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
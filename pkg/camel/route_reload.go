@@ -0,0 +1,416 @@
+package camel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RouteDrainTimeout is the maximum time to wait for in-flight exchanges before a reloaded route is swapped.
+var RouteDrainTimeout = 30 * time.Second
+
+// processorRemover is implemented by consumers that can detach a processor (route) bound to them.
+type processorRemover interface {
+	RemoveProcessor(processor api.Processor)
+}
+
+// ReloadRoute replaces the route with the same name by the new definition.
+// The new definition is compiled first; if compilation fails the old route is kept untouched (rollback).
+// If the runtime is started the route consumer is stopped, in-flight exchanges are drained, the new processor
+// tree is swapped in and the consumer is restarted. A route that does not exist yet is added (and started).
+func (rt *Runtime) ReloadRoute(routeDefinition *Route) error {
+	nr, err := rt.compileRoute(routeDefinition)
+	if err != nil {
+		rt.logger.Error(context.Background(), fmt.Sprintf("Failed to reload route '%s', keeping current version", routeDefinition.Name), slog.String("error", err.Error()))
+		return fmt.Errorf("failed to reload route '%s': %w", routeDefinition.Name, err)
+	}
+
+	rt.reloadMu.Lock()
+	defer rt.reloadMu.Unlock()
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if rt.status == RuntimeStatusStopped {
+		return fmt.Errorf("failed to reload route '%s': runtime '%s' is stopped", routeDefinition.Name, rt.name)
+	}
+
+	old, exists := rt.routes[routeDefinition.Name]
+	switch {
+	case !exists:
+		err = rt.startNewRoute(nr)
+	case rt.status != RuntimeStatusStarted:
+		// Consumers are not created yet, just replace the route
+		rt.addRoute(nr)
+	case old.from == nr.from:
+		err = rt.swapRoute(old, nr)
+	default:
+		err = rt.replaceRoute(old, nr)
+	}
+	if err != nil {
+		rt.logger.Error(context.Background(), fmt.Sprintf("Failed to reload route '%s'", routeDefinition.Name), slog.String("error", err.Error()))
+		return fmt.Errorf("failed to reload route '%s': %w", routeDefinition.Name, err)
+	}
+
+	rt.logger.Info(context.Background(), fmt.Sprintf("Route '%s' reloaded and consuming from: '%s'", routeDefinition.Name, routeDefinition.From))
+	return nil
+}
+
+// MustReloadRoute reloads the route, panics on error.
+func (rt *Runtime) MustReloadRoute(routeDefinition *Route) {
+	if err := rt.ReloadRoute(routeDefinition); err != nil {
+		panic(fmt.Errorf("camel: %w", err))
+	}
+}
+
+// RemoveRoute stops the route consumer and removes the route from the runtime.
+func (rt *Runtime) RemoveRoute(routeName string) error {
+	rt.reloadMu.Lock()
+	defer rt.reloadMu.Unlock()
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	r, exists := rt.routes[routeName]
+	if !exists {
+		return fmt.Errorf("failed to remove route '%s': route not found", routeName)
+	}
+	if err := rt.detachRoute(r); err != nil {
+		return fmt.Errorf("failed to remove route '%s': %w", routeName, err)
+	}
+	delete(rt.routes, routeName)
+
+	rt.logger.Info(context.Background(), fmt.Sprintf("Route '%s' removed", routeName))
+	return nil
+}
+
+// startNewRoute adds the route and starts its consumer if the runtime is started, must be called under rt.mu lock.
+func (rt *Runtime) startNewRoute(r *route) error {
+	rt.addRoute(r)
+	if rt.status != RuntimeStatusStarted {
+		return nil
+	}
+
+	if err := rt.createRouteConsumer(r); err != nil {
+		delete(rt.routes, r.name)
+		return err
+	}
+	if err := r.consumer.Start(); err != nil {
+		_ = rt.detachRoute(r)
+		delete(rt.routes, r.name)
+		return err
+	}
+	for _, policy := range r.policies {
+		policy.OnStart(r)
+	}
+	return nil
+}
+
+// swapRoute swaps the processor tree of the running route keeping its consumer, must be called under rt.mu lock.
+// The consumer is stopped and the route is marked as draining under the locks, then rt.mu is released while
+// the in-flight exchanges are drained; the exchanges received meanwhile wait for the swap (see route.begin).
+func (rt *Runtime) swapRoute(old, nr *route) error {
+	for _, policy := range old.policies {
+		policy.OnStop(old)
+	}
+
+	old.mu.Lock()
+	running := old.consumer != nil && !old.suspended
+	if running {
+		if err := old.consumer.Stop(); err != nil {
+			old.mu.Unlock()
+			return err
+		}
+	}
+	drained := make(chan struct{})
+	old.draining = drained
+	old.mu.Unlock()
+
+	rt.mu.Unlock()
+	if !old.drain(RouteDrainTimeout) {
+		rt.logger.Warn(context.Background(), fmt.Sprintf("Route '%s' reloaded with %d exchange(s) still in-flight", old.name, old.inflight.Load()))
+	}
+	rt.mu.Lock()
+
+	old.mu.Lock()
+	old.definition = nr.definition
+	old.producer = nr.producer
+	old.onCompletions = nr.onCompletions
+	old.keepOriginalMessage = nr.keepOriginalMessage
+	old.policies = nr.policies
+	old.draining = nil
+	close(drained)
+
+	// The runtime could be stopped while the route was drained
+	started := rt.status == RuntimeStatusStarted
+	if running && started && !old.suspended && old.consumer != nil {
+		if err := old.consumer.Start(); err != nil {
+			old.mu.Unlock()
+			return err
+		}
+	}
+	old.mu.Unlock()
+
+	if started {
+		for _, policy := range old.policies {
+			policy.OnInit(old)
+			policy.OnStart(old)
+		}
+	}
+	return nil
+}
+
+// replaceRoute replaces the route that consumes from another endpoint, must be called under rt.mu lock.
+// If the new route cannot be started, the old one is restored.
+func (rt *Runtime) replaceRoute(old, nr *route) error {
+	if err := rt.detachRoute(old); err != nil {
+		return err
+	}
+	delete(rt.routes, old.name)
+
+	if err := rt.startNewRoute(nr); err != nil {
+		rt.logger.Warn(context.Background(), fmt.Sprintf("Route '%s' rolled back to consume from: '%s'", old.name, old.from))
+		if restoreErr := rt.restoreRoute(old); restoreErr != nil {
+			return errors.Join(err, restoreErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (rt *Runtime) restoreRoute(r *route) error {
+	r.mu.Lock()
+	r.consumer = nil
+	r.suspended = false
+	r.detached = false
+	r.mu.Unlock()
+
+	return rt.startNewRoute(r)
+}
+
+// detachRoute stops the route consumer (unless it is shared with other routes), must be called under rt.mu lock.
+func (rt *Runtime) detachRoute(r *route) error {
+	if rt.status == RuntimeStatusStarted {
+		for _, policy := range r.policies {
+			policy.OnStop(r)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Exchanges sent by consumers that still refer to the route are rejected
+	r.suspended = true
+
+	if r.consumer == nil {
+		return nil
+	}
+	consumer := r.consumer
+	r.consumer = nil
+
+	if remover, canRemove := consumer.(processorRemover); canRemove {
+		remover.RemoveProcessor(r)
+	}
+
+	for _, other := range rt.routes {
+		if other != r && other.consumer == consumer {
+			return nil
+		}
+	}
+
+	for i, c := range rt.consumers {
+		if c == consumer {
+			rt.consumers = append(rt.consumers[:i], rt.consumers[i+1:]...)
+			break
+		}
+	}
+	return consumer.Stop()
+}
+
+type watchedFile struct {
+	modTime time.Time
+	size    int64
+	routes  []string
+}
+
+// RouteWatcher polls a directory with route definition documents (*.json, *.yaml, *.yml) and reloads routes
+// when documents are created, changed or deleted.
+type RouteWatcher struct {
+	rt       *Runtime
+	dir      string
+	interval time.Duration
+	files    map[string]watchedFile
+
+	done     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// WatchRoutes loads route definitions from the documents in dir and keeps watching the directory for changes
+// until the watcher or the runtime is stopped.
+// Documents that cannot be parsed or routes that cannot be compiled are logged and the current version of the routes
+// is kept. Routes of deleted documents (or removed from a document) are removed from the runtime.
+func (rt *Runtime) WatchRoutes(dir string, interval time.Duration) (*RouteWatcher, error) {
+	if interval <= 0 {
+		return nil, errors.New("watch interval must be positive")
+	}
+	if _, err := os.ReadDir(dir); err != nil {
+		return nil, fmt.Errorf("failed to watch routes: %w", err)
+	}
+
+	w := &RouteWatcher{
+		rt:       rt,
+		dir:      dir,
+		interval: interval,
+		files:    map[string]watchedFile{},
+		done:     make(chan struct{}),
+	}
+	if err := w.scan(); err != nil {
+		return nil, err
+	}
+
+	w.wg.Add(1)
+	go w.watch()
+
+	return w, nil
+}
+
+// Stop stops watching the directory, loaded routes are kept.
+func (w *RouteWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.done)
+	})
+	w.wg.Wait()
+}
+
+func (w *RouteWatcher) watch() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.rt.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.scan(); err != nil {
+				w.rt.logger.Error(context.Background(), fmt.Sprintf("Failed to scan route directory '%s'", w.dir), slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// scan reloads the routes of changed documents and removes the routes of deleted documents.
+func (w *RouteWatcher) scan() error {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	seen := map[string]bool{}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !isRouteDocument(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(w.dir, entry.Name())
+		seen[path] = true
+
+		info, err := entry.Info()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		prev, known := w.files[path]
+		if known && prev.modTime.Equal(info.ModTime()) && prev.size == info.Size() {
+			continue
+		}
+
+		current := watchedFile{modTime: info.ModTime(), size: info.Size(), routes: prev.routes}
+		routes, err := w.load(path, prev.routes)
+		if routes != nil {
+			current.routes = routes
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+		w.files[path] = current
+	}
+
+	deleted := make([]string, 0)
+	for path := range w.files {
+		if !seen[path] {
+			deleted = append(deleted, path)
+		}
+	}
+	sort.Strings(deleted)
+	for _, path := range deleted {
+		for _, name := range w.files[path].routes {
+			if err := w.rt.RemoveRoute(name); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		delete(w.files, path)
+	}
+
+	return errors.Join(errs...)
+}
+
+// load reloads the routes of the document, returns the names of the routes defined in the document
+// (nil if the document cannot be parsed).
+func (w *RouteWatcher) load(path string, prevRoutes []string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	definitions, err := ParseRoutes(f)
+	if err != nil {
+		w.rt.logger.Error(context.Background(), fmt.Sprintf("Failed to parse route document '%s', keeping current routes", path), slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var errs []error
+	names := make([]string, 0, len(definitions))
+	defined := map[string]bool{}
+	for _, definition := range definitions {
+		defined[definition.Name] = true
+		if err := w.rt.ReloadRoute(definition); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			if !slices.Contains(prevRoutes, definition.Name) {
+				continue
+			}
+		}
+		names = append(names, definition.Name)
+	}
+
+	for _, name := range prevRoutes {
+		if !defined[name] {
+			if err := w.rt.RemoveRoute(name); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return names, errors.Join(errs...)
+}
+
+func isRouteDocument(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
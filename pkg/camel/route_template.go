@@ -0,0 +1,393 @@
+package camel
+
+import (
+	"errors"
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+	"github.com/paveldanilin/go-camel/pkg/camel/template"
+)
+
+// RouteTemplateParam declares a parameter of the RouteTemplate.
+type RouteTemplateParam struct {
+	Name     string
+	Default  any
+	Required bool
+}
+
+// RouteTemplate is a parameterised route definition that can be instantiated many times.
+// Parameters are referred as ${param} placeholders in From and To URIs, log messages and simple expressions.
+// Placeholders that are not declared as parameters are left untouched (e.g. ${body} in log messages).
+type RouteTemplate struct {
+	Id     string
+	Params []RouteTemplateParam
+	From   string
+	Steps  []api.RouteStep
+	// ErrorHandler, OnExceptions, OnCompletions and intercepts are copied to every route instance (see Route).
+	ErrorHandler             *routestep.ErrorHandler
+	OnExceptions             []routestep.OnException
+	OnCompletions            []routestep.OnCompletion
+	Intercepts               []routestep.Intercept
+	InterceptFroms           []routestep.InterceptFrom
+	InterceptSendToEndpoints []routestep.InterceptSendToEndpoint
+}
+
+// RouteTemplateBuilder represents a RouteTemplate builder.
+type RouteTemplateBuilder struct {
+	template *RouteTemplate
+	err      error
+}
+
+func NewRouteTemplate(id, from string) *RouteTemplateBuilder {
+	if id == "" {
+		panic(fmt.Errorf("camel: 'id' must be not empty string"))
+	}
+	if from == "" {
+		panic(fmt.Errorf("camel: 'from' must be not empty string"))
+	}
+	return &RouteTemplateBuilder{
+		template: &RouteTemplate{
+			Id:   id,
+			From: from,
+		},
+	}
+}
+
+// Param declares a required parameter.
+func (tb *RouteTemplateBuilder) Param(name string) *RouteTemplateBuilder {
+	return tb.addParam(RouteTemplateParam{Name: name, Required: true})
+}
+
+// ParamDefault declares an optional parameter with the default value.
+func (tb *RouteTemplateBuilder) ParamDefault(name string, defaultValue any) *RouteTemplateBuilder {
+	return tb.addParam(RouteTemplateParam{Name: name, Default: defaultValue})
+}
+
+func (tb *RouteTemplateBuilder) addParam(param RouteTemplateParam) *RouteTemplateBuilder {
+	if tb.err != nil {
+		return tb
+	}
+	for _, p := range tb.template.Params {
+		if p.Name == param.Name {
+			tb.err = fmt.Errorf("camel: route template '%s': parameter '%s' already declared", tb.template.Id, param.Name)
+			return tb
+		}
+	}
+	tb.template.Params = append(tb.template.Params, param)
+	return tb
+}
+
+// Steps configures the template steps and the route clauses (error handler, onException, onCompletion, intercepts)
+// by means of RouteBuilder. Route policies are not supported since the policy instances would be shared by the routes.
+func (tb *RouteTemplateBuilder) Steps(configure func(b *RouteBuilder)) *RouteTemplateBuilder {
+	if tb.err != nil {
+		return tb
+	}
+
+	b := NewRoute(tb.template.Id, tb.template.From)
+	configure(b)
+
+	r, err := b.Build()
+	if err != nil {
+		tb.err = err
+		return tb
+	}
+	if len(r.Policies) > 0 {
+		tb.err = fmt.Errorf("camel: route template '%s': route policies are not supported", tb.template.Id)
+		return tb
+	}
+
+	tb.template.Steps = append(tb.template.Steps, r.Steps...)
+	if r.ErrorHandler != nil {
+		tb.template.ErrorHandler = r.ErrorHandler
+	}
+	tb.template.OnExceptions = append(tb.template.OnExceptions, r.OnExceptions...)
+	tb.template.OnCompletions = append(tb.template.OnCompletions, r.OnCompletions...)
+	tb.template.Intercepts = append(tb.template.Intercepts, r.Intercepts...)
+	tb.template.InterceptFroms = append(tb.template.InterceptFroms, r.InterceptFroms...)
+	tb.template.InterceptSendToEndpoints = append(tb.template.InterceptSendToEndpoints, r.InterceptSendToEndpoints...)
+	return tb
+}
+
+func (tb *RouteTemplateBuilder) Build() (*RouteTemplate, error) {
+	if tb.err != nil {
+		return nil, tb.err
+	}
+	if len(tb.template.Steps) == 0 {
+		return nil, fmt.Errorf("camel: route template '%s': no steps", tb.template.Id)
+	}
+	return tb.template, nil
+}
+
+// Instantiate creates a new Route definition replacing ${param} placeholders with the given values.
+func (t *RouteTemplate) Instantiate(routeName string, params map[string]any) (*Route, error) {
+	if routeName == "" {
+		return nil, errors.New("route name must be not empty string")
+	}
+
+	values := make(map[string]any, len(t.Params))
+	for _, p := range t.Params {
+		if v, exists := params[p.Name]; exists {
+			values[p.Name] = v
+		} else if p.Required {
+			return nil, fmt.Errorf("route template '%s': missing required parameter '%s'", t.Id, p.Name)
+		} else {
+			values[p.Name] = p.Default
+		}
+	}
+	for name := range params {
+		if _, declared := values[name]; !declared {
+			return nil, fmt.Errorf("route template '%s': unknown parameter '%s'", t.Id, name)
+		}
+	}
+
+	replace := func(s string) (string, error) {
+		return template.Replace(s, values)
+	}
+
+	from, err := replace(t.From)
+	if err != nil {
+		return nil, fmt.Errorf("route template '%s': from: %w", t.Id, err)
+	}
+	route := &Route{
+		Name: routeName,
+		From: from,
+	}
+	if err := t.instantiateRoute(route, replace); err != nil {
+		return nil, fmt.Errorf("route template '%s': %w", t.Id, err)
+	}
+	return route, nil
+}
+
+func (t *RouteTemplate) instantiateRoute(route *Route, replace func(string) (string, error)) error {
+	var err error
+
+	if route.Steps, err = instantiateSteps(t.Steps, replace); err != nil {
+		return err
+	}
+	if t.ErrorHandler != nil {
+		errorHandler, err := instantiateErrorHandler(*t.ErrorHandler, replace)
+		if err != nil {
+			return fmt.Errorf("errorHandler: %w", err)
+		}
+		route.ErrorHandler = &errorHandler
+	}
+	for _, onException := range t.OnExceptions {
+		if onException.ErrorMatcher, err = instantiateErrorMatcher(onException.ErrorMatcher, replace); err != nil {
+			return fmt.Errorf("onException: %w", err)
+		}
+		if onException.Steps, err = instantiateSteps(onException.Steps, replace); err != nil {
+			return fmt.Errorf("onException: %w", err)
+		}
+		route.OnExceptions = append(route.OnExceptions, onException)
+	}
+	for _, onCompletion := range t.OnCompletions {
+		if onCompletion.OnWhen, err = instantiateExpression(onCompletion.OnWhen, replace); err != nil {
+			return fmt.Errorf("onCompletion: %w", err)
+		}
+		if onCompletion.Steps, err = instantiateSteps(onCompletion.Steps, replace); err != nil {
+			return fmt.Errorf("onCompletion: %w", err)
+		}
+		route.OnCompletions = append(route.OnCompletions, onCompletion)
+	}
+	for _, intercept := range t.Intercepts {
+		if intercept.Steps, err = instantiateSteps(intercept.Steps, replace); err != nil {
+			return fmt.Errorf("intercept: %w", err)
+		}
+		route.Intercepts = append(route.Intercepts, intercept)
+	}
+	for _, intercept := range t.InterceptFroms {
+		if intercept.URIPattern, err = replace(intercept.URIPattern); err != nil {
+			return fmt.Errorf("interceptFrom: %w", err)
+		}
+		if intercept.Steps, err = instantiateSteps(intercept.Steps, replace); err != nil {
+			return fmt.Errorf("interceptFrom: %w", err)
+		}
+		route.InterceptFroms = append(route.InterceptFroms, intercept)
+	}
+	for _, intercept := range t.InterceptSendToEndpoints {
+		if intercept.URIPattern, err = replace(intercept.URIPattern); err != nil {
+			return fmt.Errorf("interceptSendToEndpoint: %w", err)
+		}
+		if intercept.Steps, err = instantiateSteps(intercept.Steps, replace); err != nil {
+			return fmt.Errorf("interceptSendToEndpoint: %w", err)
+		}
+		route.InterceptSendToEndpoints = append(route.InterceptSendToEndpoints, intercept)
+	}
+	return nil
+}
+
+// instantiateSteps copies the step tree replacing placeholders in URIs, log messages and simple expressions.
+// Steps without nested steps and placeholders support are shared between template instances.
+func instantiateSteps(steps []api.RouteStep, replace func(string) (string, error)) ([]api.RouteStep, error) {
+	if steps == nil {
+		return nil, nil
+	}
+
+	copies := make([]api.RouteStep, 0, len(steps))
+	for _, step := range steps {
+		cp, err := instantiateStep(step, replace)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", step.StepName(), err)
+		}
+		copies = append(copies, cp)
+	}
+	return copies, nil
+}
+
+func instantiateStep(step api.RouteStep, replace func(string) (string, error)) (api.RouteStep, error) {
+	var err error
+
+	switch t := step.(type) {
+	case *routestep.To:
+		cp := *t
+		cp.URI, err = replace(t.URI)
+		return &cp, err
+
+	case *routestep.Log:
+		cp := *t
+		cp.Msg, err = replace(t.Msg)
+		return &cp, err
+
+	case *routestep.SetBody:
+		cp := *t
+		cp.BodyValue, err = instantiateExpression(t.BodyValue, replace)
+		return &cp, err
+
+	case *routestep.SetHeader:
+		cp := *t
+		cp.HeaderValue, err = instantiateExpression(t.HeaderValue, replace)
+		return &cp, err
+
+	case *routestep.SetProperty:
+		cp := *t
+		cp.PropertyValue, err = instantiateExpression(t.PropertyValue, replace)
+		return &cp, err
+
+	case *routestep.Pipeline:
+		cp := *t
+		cp.Steps, err = instantiateSteps(t.Steps, replace)
+		return &cp, err
+
+	case *routestep.Loop:
+		cp := *t
+		if cp.Predicate, err = instantiateExpression(t.Predicate, replace); err != nil {
+			return nil, err
+		}
+		cp.Steps, err = instantiateSteps(t.Steps, replace)
+		return &cp, err
+
+	case *routestep.Choice:
+		cp := *t
+		cp.WhenCases = make([]routestep.ChoiceWhen, len(t.WhenCases))
+		for i, when := range t.WhenCases {
+			if when.Predicate, err = instantiateExpression(when.Predicate, replace); err != nil {
+				return nil, err
+			}
+			if when.Steps, err = instantiateSteps(when.Steps, replace); err != nil {
+				return nil, err
+			}
+			cp.WhenCases[i] = when
+		}
+		cp.Otherwise, err = instantiateSteps(t.Otherwise, replace)
+		return &cp, err
+
+	case *routestep.Try:
+		cp := *t
+		if cp.Steps, err = instantiateSteps(t.Steps, replace); err != nil {
+			return nil, err
+		}
+		cp.WhenCatches = make([]routestep.CatchWhen, len(t.WhenCatches))
+		for i, catch := range t.WhenCatches {
+			if catch.ErrorMatcher, err = instantiateErrorMatcher(catch.ErrorMatcher, replace); err != nil {
+				return nil, err
+			}
+			if catch.Steps, err = instantiateSteps(catch.Steps, replace); err != nil {
+				return nil, err
+			}
+			cp.WhenCatches[i] = catch
+		}
+		cp.FinallySteps, err = instantiateSteps(t.FinallySteps, replace)
+		return &cp, err
+
+	case *routestep.Multicast:
+		cp := *t
+		cp.Outputs = make([]routestep.OutputProcess, len(t.Outputs))
+		for i, output := range t.Outputs {
+			if output.Steps, err = instantiateSteps(output.Steps, replace); err != nil {
+				return nil, err
+			}
+			cp.Outputs[i] = output
+		}
+		return &cp, nil
+
+	case *routestep.ErrorHandlerScope:
+		cp := *t
+		if cp.ErrorHandler, err = instantiateErrorHandler(t.ErrorHandler, replace); err != nil {
+			return nil, err
+		}
+		cp.Steps, err = instantiateSteps(t.Steps, replace)
+		return &cp, err
+
+	case *routestep.Saga:
+		cp := *t
+		if cp.CompensationURI, err = replace(t.CompensationURI); err != nil {
+			return nil, err
+		}
+		if cp.CompletionURI, err = replace(t.CompletionURI); err != nil {
+			return nil, err
+		}
+		cp.Steps, err = instantiateSteps(t.Steps, replace)
+		return &cp, err
+	}
+
+	return step, nil
+}
+
+func instantiateErrorHandler(errorHandler routestep.ErrorHandler, replace func(string) (string, error)) (routestep.ErrorHandler, error) {
+	var err error
+
+	if errorHandler.DeadLetterURI, err = replace(errorHandler.DeadLetterURI); err != nil {
+		return errorHandler, err
+	}
+	errorHandler.Redelivery.RetryWhile, err = instantiateExpression(errorHandler.Redelivery.RetryWhile, replace)
+	return errorHandler, err
+}
+
+// instantiateErrorMatcher copies the matcher replacing placeholders in the predicates.
+func instantiateErrorMatcher(matcher errs.Matcher, replace func(string) (string, error)) (errs.Matcher, error) {
+	var err error
+
+	switch matcher.MatchMode {
+	case errs.MatchModePredicate:
+		matcher.Predicate, err = instantiateExpression(matcher.Predicate, replace)
+		return matcher, err
+
+	case errs.MatchModeAnyOf, errs.MatchModeAllOf, errs.MatchModeNot:
+		operands := make([]errs.Matcher, len(matcher.Matchers))
+		for i, operand := range matcher.Matchers {
+			if operands[i], err = instantiateErrorMatcher(operand, replace); err != nil {
+				return matcher, err
+			}
+		}
+		matcher.Matchers = operands
+	}
+	return matcher, nil
+}
+
+func instantiateExpression(def expr.Definition, replace func(string) (string, error)) (expr.Definition, error) {
+	if def.Kind != expr.SimpleKind {
+		return def, nil
+	}
+	simple, isString := def.Expression.(string)
+	if !isString {
+		return def, nil
+	}
+	simple, err := replace(simple)
+	if err != nil {
+		return def, err
+	}
+	return expr.Simple(simple), nil
+}
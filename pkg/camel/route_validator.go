@@ -0,0 +1,505 @@
+package camel
+
+import (
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+	"github.com/paveldanilin/go-camel/pkg/camel/template"
+	"github.com/paveldanilin/go-camel/pkg/camel/uri"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// RouteValidationError reports all problems found in the route definition.
+// Problem paths point at the offending step, e.g. 'sum/choice[1]/when[0]/to[0]'.
+type RouteValidationError struct {
+	Route    string
+	Problems []*RouteDefinitionError
+}
+
+func (e *RouteValidationError) Error() string {
+	problems := make([]string, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		problems = append(problems, fmt.Sprintf("%s: %s", p.Path, p.Msg))
+	}
+	return fmt.Sprintf("route '%s' is invalid: %s", e.Route, strings.Join(problems, "; "))
+}
+
+// routeValidator collects problems of the route definition.
+type routeValidator struct {
+	c        compilerConfig
+	problems []*RouteDefinitionError
+}
+
+// validateRoute checks the route definition against the runtime registries and returns RouteValidationError
+// with all found problems: unknown data formats, unregistered funcs and beans, invalid expressions and templates,
+// bad URIs, unknown components and unreachable steps.
+func validateRoute(c compilerConfig, routeDefinition *Route) error {
+	v := &routeValidator{c: c}
+
+	if routeDefinition.Name == "" {
+		v.problem("", "route name must be not empty string")
+	}
+	v.validateURI(routeDefinition.Name+"/from", routeDefinition.From)
+	if routeDefinition.ErrorHandler != nil {
+		v.validateErrorHandler(routeDefinition.Name+"/errorHandler", routeDefinition.ErrorHandler)
+	} else if c.errorHandler != nil {
+		v.validateErrorHandler(routeDefinition.Name+"/errorHandler", c.errorHandler)
+	}
+	// The runtime onException clauses are applied after the route ones
+	onExceptions := append(append([]routestep.OnException{}, routeDefinition.OnExceptions...), c.onExceptions...)
+	for i := range onExceptions {
+		v.validateOnException(fmt.Sprintf("%s/onException[%d]", routeDefinition.Name, i), &onExceptions[i])
+	}
+	for i := range routeDefinition.OnCompletions {
+		v.validateOnCompletion(fmt.Sprintf("%s/onCompletion[%d]", routeDefinition.Name, i), &routeDefinition.OnCompletions[i])
+	}
+	v.validateIntercepts(routeDefinition)
+
+	if len(routeDefinition.Steps) == 0 {
+		v.problem(routeDefinition.Name, "route has no steps")
+	}
+	v.validateSteps(routeDefinition.Name, routeDefinition.Steps)
+
+	if len(v.problems) > 0 {
+		return &RouteValidationError{Route: routeDefinition.Name, Problems: v.problems}
+	}
+	return nil
+}
+
+func (v *routeValidator) problem(path, format string, args ...any) {
+	v.problems = append(v.problems, &RouteDefinitionError{Path: path, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (v *routeValidator) validateSteps(path string, steps []api.RouteStep) {
+	for i, step := range steps {
+		stepPath := stepPath(path, step, i)
+
+		v.validateStep(stepPath, step)
+
+		for _, branch := range stepBranches(step) {
+			branchPath := branch.path(stepPath)
+			if len(branch.steps) == 0 {
+				v.problem(branchPath, "no steps")
+			}
+			v.validateSteps(branchPath, branch.steps)
+		}
+	}
+}
+
+func (v *routeValidator) validateStep(path string, step api.RouteStep) {
+	switch t := step.(type) {
+	case *routestep.SetBody:
+		v.validateExpression(path, t.BodyValue)
+
+	case *routestep.SetHeader:
+		if t.HeaderName == "" {
+			v.problem(path, "header name must be not empty string")
+		}
+		v.validateExpression(path, t.HeaderValue)
+
+	case *routestep.SetProperty:
+		if t.PropertyName == "" {
+			v.problem(path, "property name must be not empty string")
+		}
+		v.validateExpression(path, t.PropertyValue)
+
+	case *routestep.To:
+		v.validateURI(path, t.URI)
+
+	case *routestep.Log:
+		if template.HasVars(t.Msg) {
+			if _, err := template.Parse(t.Msg); err != nil {
+				v.problem(path, "invalid message template: %s", err)
+			}
+		}
+
+	case *routestep.Fn:
+		switch f := t.Func.(type) {
+		case func(*exchange.Exchange):
+		case string:
+			if v.c.funcRegistry.Func(f) == nil {
+				v.problem(path, "function not found in registry: %s", f)
+			}
+		default:
+			v.problem(path, "expected function signature 'fn(*Exchange)', but got %T", t.Func)
+		}
+
+	case *routestep.Marshal:
+		v.validateDataFormat(path, t.Format)
+
+	case *routestep.Unmarshal:
+		v.validateDataFormat(path, t.Format)
+		if t.TargetType == nil && t.TargetTypeRef != "" && v.c.beanRegistry.Bean(t.TargetTypeRef) == nil {
+			v.problem(path, "target type bean not found in registry: %s", t.TargetTypeRef)
+		}
+
+	case *routestep.Multicast:
+		if len(t.Outputs) == 0 {
+			v.problem(path, "no outputs")
+		}
+		if t.Timeout < 0 {
+			v.problem(path, "timeout must be not negative")
+		}
+		if t.Aggregator == nil && t.AggregatorRef != "" {
+			bean := v.c.beanRegistry.Bean(t.AggregatorRef)
+			if bean == nil {
+				v.problem(path, "aggregator bean not found in registry: %s", t.AggregatorRef)
+			} else if _, isAggregator := bean.(api.ExchangeAggregator); !isAggregator {
+				v.problem(path, "bean '%s' does not implement ExchangeAggregator", t.AggregatorRef)
+			}
+		}
+		if t.ExecutorService != "" {
+			if !t.Parallel {
+				v.problem(path, "executor service requires parallel processing")
+			}
+			v.validateExecutorProfile(path, t.ExecutorService)
+		}
+
+	case *routestep.Pipeline:
+		// Steps after the step that sets an error never run if the pipeline stops on error
+		for i, pipeStep := range t.Steps {
+			if _, isSetError := pipeStep.(*routestep.SetError); isSetError && t.StoOnError && i < len(t.Steps)-1 {
+				v.problem(fmt.Sprintf("%s/%s[%d]", path, stepKind(t.Steps[i+1]), i+1),
+					"unreachable step: pipeline stops on error set by the previous step")
+				break
+			}
+		}
+
+	case *routestep.Loop:
+		v.problem(path, "loop step is not supported")
+
+	case *routestep.Choice:
+		for i, when := range t.WhenCases {
+			whenPath := fmt.Sprintf("%s/when[%d]", path, i)
+			v.validateExpression(whenPath+"/predicate", when.Predicate)
+
+			if isAlwaysTrue(when.Predicate) && i < len(t.WhenCases)-1 {
+				v.problem(fmt.Sprintf("%s/when[%d]", path, i+1), "unreachable branch: previous predicate is always true")
+			}
+			if isAlwaysTrue(when.Predicate) && len(t.Otherwise) > 0 {
+				v.problem(path+"/otherwise", "unreachable branch: predicate of when[%d] is always true", i)
+			}
+			if isAlwaysTrue(when.Predicate) {
+				break
+			}
+		}
+
+	case *routestep.Try:
+		for i, catch := range t.WhenCatches {
+			v.validateErrMatcher(fmt.Sprintf("%s/catch[%d]", path, i), catch.ErrorMatcher)
+		}
+		for i, catch := range t.WhenCatches {
+			if catch.ErrorMatcher.IsAny() && i < len(t.WhenCatches)-1 {
+				v.problem(fmt.Sprintf("%s/catch[%d]", path, i+1), "unreachable branch: catch[%d] matches any error", i)
+				break
+			}
+		}
+
+	case *routestep.ConvertBody:
+		v.validateTargetType(path, t.TargetType, t.NamedType)
+
+	case *routestep.ConvertHeader:
+		v.validateTargetType(path, t.TargetType, t.NamedType)
+
+	case *routestep.ConvertProperty:
+		v.validateTargetType(path, t.TargetType, t.NamedType)
+
+	case *routestep.SetPattern:
+		if t.Pattern != exchange.InOnly && t.Pattern != exchange.InOut {
+			v.problem(path, "unknown exchange pattern: %s", t.Pattern)
+		}
+
+	case *routestep.Threads:
+		v.validateExecutorProfile(path, t.Profile)
+
+	case *routestep.ErrorHandlerScope:
+		v.validateErrorHandler(path, &t.ErrorHandler)
+
+	case *routestep.Saga:
+		switch t.Propagation {
+		case "", routestep.SagaPropagationRequired, routestep.SagaPropagationRequiresNew, routestep.SagaPropagationMandatory,
+			routestep.SagaPropagationSupports, routestep.SagaPropagationNever:
+		default:
+			v.problem(path, "unknown saga propagation: %s", t.Propagation)
+		}
+		if t.Timeout < 0 {
+			v.problem(path, "timeout must be not negative")
+		}
+		if t.CompensationURI != "" {
+			v.validateURI(path+"/compensation", t.CompensationURI)
+		}
+		if t.CompletionURI != "" {
+			v.validateURI(path+"/completion", t.CompletionURI)
+		}
+
+	case *routestep.RemoveHeader, *routestep.RemoveProperty, *routestep.Delay, *routestep.SetError:
+
+	default:
+		v.problem(path, "unknown route step: %T", step)
+	}
+}
+
+func (v *routeValidator) validateExpression(path string, def expr.Definition) {
+	if _, err := createExpression(v.c, def); err != nil {
+		v.problem(path, "invalid expression: %s", err)
+	}
+}
+
+func (v *routeValidator) validateOnException(path string, onException *routestep.OnException) {
+	if onException.Handled && onException.Continued {
+		v.problem(path, "handled and continued are mutually exclusive")
+	}
+	v.validateErrMatcher(path, onException.ErrorMatcher)
+	v.validateSteps(path, onException.Steps)
+}
+
+func (v *routeValidator) validateOnCompletion(path string, onCompletion *routestep.OnCompletion) {
+	switch onCompletion.Mode {
+	case "", routestep.OnCompletionAlways, routestep.OnCompleteOnly, routestep.OnFailureOnly:
+	default:
+		v.problem(path, "unknown onCompletion mode: %s", onCompletion.Mode)
+	}
+	if onCompletion.OnWhen.Kind != "" {
+		v.validateExpression(path+"/onWhen", onCompletion.OnWhen)
+	}
+	if len(onCompletion.Steps) == 0 {
+		v.problem(path, "onCompletion has no steps")
+	}
+	v.validateSteps(path, onCompletion.Steps)
+}
+
+// validateIntercepts checks the intercept clauses of the runtime and the route, the runtime ones go first.
+func (v *routeValidator) validateIntercepts(routeDefinition *Route) {
+	intercepts := append(append([]routestep.Intercept{}, v.c.intercepts...), routeDefinition.Intercepts...)
+	for i, intercept := range intercepts {
+		path := fmt.Sprintf("%s/intercept[%d]", routeDefinition.Name, i)
+		if len(intercept.Steps) == 0 {
+			v.problem(path, "intercept has no steps")
+		}
+		v.validateSteps(path, intercept.Steps)
+	}
+
+	interceptFroms := append(append([]routestep.InterceptFrom{}, v.c.interceptFroms...), routeDefinition.InterceptFroms...)
+	for i, intercept := range interceptFroms {
+		path := fmt.Sprintf("%s/interceptFrom[%d]", routeDefinition.Name, i)
+		if len(intercept.Steps) == 0 {
+			v.problem(path, "interceptFrom has no steps")
+		}
+		v.validateSteps(path, intercept.Steps)
+	}
+
+	interceptSendTos := append(append([]routestep.InterceptSendToEndpoint{}, v.c.interceptSendTos...), routeDefinition.InterceptSendToEndpoints...)
+	for i, intercept := range interceptSendTos {
+		path := fmt.Sprintf("%s/interceptSendToEndpoint[%d]", routeDefinition.Name, i)
+		if intercept.URIPattern == "" {
+			v.problem(path, "uri pattern must be not empty")
+		}
+		if len(intercept.Steps) == 0 {
+			v.problem(path, "interceptSendToEndpoint has no steps")
+		}
+		v.validateSteps(path, intercept.Steps)
+	}
+}
+
+func (v *routeValidator) validateErrorHandler(path string, errorHandler *routestep.ErrorHandler) {
+	policy := errorHandler.Redelivery
+	if policy.MaximumRedeliveries < -1 {
+		v.problem(path, "maximum redeliveries must be -1 (unlimited) or not negative")
+	}
+	if policy.RedeliveryDelay < 0 {
+		v.problem(path, "redelivery delay must be not negative")
+	}
+	if policy.MaximumRedeliveryDelay < 0 {
+		v.problem(path, "maximum redelivery delay must be not negative")
+	}
+	if policy.BackOffMultiplier < 0 {
+		v.problem(path, "back off multiplier must be not negative")
+	}
+	if policy.Jitter < 0 || policy.Jitter > 1 {
+		v.problem(path, "jitter must be in range [0..1]")
+	}
+	if policy.RetryWhile.Kind != "" {
+		v.validateExpression(path+"/retryWhile", policy.RetryWhile)
+	}
+	if errorHandler.DeadLetterURI != "" {
+		v.validateURI(path+"/deadLetter", errorHandler.DeadLetterURI)
+	} else if errorHandler.UseOriginalMessage {
+		v.problem(path, "original message requires dead letter channel")
+	}
+	for i, matcher := range policy.RetryOn {
+		v.validateErrMatcher(fmt.Sprintf("%s/retryOn[%d]", path, i), matcher)
+	}
+	for i, matcher := range policy.RetryOn {
+		if matcher.IsAny() && len(policy.RetryOn) > 1 {
+			v.problem(fmt.Sprintf("%s/retryOn[%d]", path, i), "matcher matches any error, other matchers are redundant")
+			break
+		}
+	}
+}
+
+func (v *routeValidator) validateDataFormat(path, format string) {
+	if v.c.dataFormatRegistry.DataFormat(format) == nil {
+		v.problem(path, "unknown data format: %s", format)
+	}
+}
+
+func (v *routeValidator) validateExecutorProfile(path, profile string) {
+	if profile == "" {
+		v.problem(path, "executor profile must be not empty string")
+		return
+	}
+	if _, exists := v.c.executorRegistry.Profile(profile); !exists {
+		v.problem(path, "executor profile not found: %s", profile)
+	}
+}
+
+func (v *routeValidator) validateTargetType(path string, targetType any, namedType string) {
+	if targetType != nil {
+		return
+	}
+	if namedType == "" {
+		v.problem(path, "no target type")
+		return
+	}
+	if _, exists := v.c.converterRegistry.Type(namedType); !exists {
+		v.problem(path, "unknown target type: %s", namedType)
+	}
+}
+
+// validateURI checks URI syntax and that the component is registered.
+// URIs with variables (${var}) are checked only for template syntax since they are resolved at runtime.
+func (v *routeValidator) validateURI(path, rawUri string) {
+	if rawUri == "" {
+		v.problem(path, "URI must be not empty string")
+		return
+	}
+	if template.HasVars(rawUri) {
+		if _, err := template.Parse(rawUri); err != nil {
+			v.problem(path, "invalid URI template '%s': %s", rawUri, err)
+		}
+		return
+	}
+
+	u, err := uri.Parse(rawUri, nil)
+	if err != nil {
+		v.problem(path, "invalid URI '%s': %s", rawUri, err)
+		return
+	}
+	if v.c.componentRegistry != nil && v.c.componentRegistry.Component(u.Component()) == nil {
+		v.problem(path, "unknown component '%s' in URI '%s'", u.Component(), rawUri)
+	}
+}
+
+func isAlwaysTrue(def expr.Definition) bool {
+	switch def.Kind {
+	case expr.ConstantKind:
+		return def.Expression == true
+	case expr.SimpleKind:
+		simple, isString := def.Expression.(string)
+		return isString && strings.TrimSpace(simple) == "true"
+	}
+	return false
+}
+
+func (v *routeValidator) validateErrMatcher(path string, matcher errs.Matcher) {
+	if matcher.IsAny() {
+		return
+	}
+	switch matcher.MatchMode {
+	case errs.MatchModeEquals, errs.MatchModeContains:
+	case errs.MatchModeIs:
+		if matcher.Err == nil && matcher.Target == "" {
+			v.problem(path, "error matcher: is: target error is not set")
+		}
+	case errs.MatchModeAs:
+		if matcher.Type == nil {
+			v.problem(path, "error matcher: as: target type is not set")
+		}
+	case errs.MatchModeRegex:
+		if _, err := regexp.Compile(matcher.Target); err != nil {
+			v.problem(path, "error matcher: invalid regex: %s", err)
+		}
+	case errs.MatchModePredicate:
+		v.validateExpression(path, matcher.Predicate)
+	case errs.MatchModeAnyOf, errs.MatchModeAllOf, errs.MatchModeNot:
+		if len(matcher.Matchers) == 0 {
+			v.problem(path, "error matcher: %s: no matchers", matcher.MatchMode)
+		} else if matcher.MatchMode == errs.MatchModeNot && len(matcher.Matchers) != 1 {
+			v.problem(path, "error matcher: not: expected exactly one matcher")
+		}
+		for _, operand := range matcher.Matchers {
+			v.validateErrMatcher(path, operand)
+		}
+	default:
+		v.problem(path, "error matcher: unknown mode: %s", matcher.MatchMode)
+	}
+}
+
+// stepKind returns the step kind as it is named in route definition documents (setBody, choice, to,...).
+func stepKind(step api.RouteStep) string {
+	switch step.(type) {
+	case *routestep.SetBody:
+		return "setBody"
+	case *routestep.SetHeader:
+		return "setHeader"
+	case *routestep.SetProperty:
+		return "setProperty"
+	case *routestep.RemoveHeader:
+		return "removeHeader"
+	case *routestep.RemoveProperty:
+		return "removeProperty"
+	case *routestep.SetPattern:
+		return "setPattern"
+	case *routestep.Threads:
+		return "threads"
+	case *routestep.ConvertBody:
+		return "convertBody"
+	case *routestep.ConvertHeader:
+		return "convertHeader"
+	case *routestep.ConvertProperty:
+		return "convertProperty"
+	case *routestep.To:
+		return "to"
+	case *routestep.Log:
+		return "log"
+	case *routestep.Fn:
+		return "fn"
+	case *routestep.Delay:
+		return "delay"
+	case *routestep.Marshal:
+		return "marshal"
+	case *routestep.Unmarshal:
+		return "unmarshal"
+	case *routestep.SetError:
+		return "setError"
+	case *routestep.Pipeline:
+		return "pipeline"
+	case *routestep.Loop:
+		return "loop"
+	case *routestep.Choice:
+		return "choice"
+	case *routestep.Try:
+		return "try"
+	case *routestep.Multicast:
+		return "multicast"
+	case *routestep.ErrorHandlerScope:
+		return "errorHandler"
+	case *routestep.Saga:
+		return "saga"
+	}
+
+	t := reflect.TypeOf(step)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	name := t.Name()
+	if name == "" {
+		return "step"
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
@@ -0,0 +1,148 @@
+package camel
+
+import (
+	"errors"
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+	"reflect"
+)
+
+// SkipSteps is used as a return value from WalkFunc to indicate that nested steps of the current step must be skipped.
+var SkipSteps = errors.New("skip nested steps")
+
+// WalkFunc is called for each step visited by WalkRoute, depth is 0 for top-level route steps.
+// If the function returns SkipSteps, WalkRoute skips nested steps of the current step.
+// Any other error stops walking and is returned by WalkRoute.
+type WalkFunc func(step api.RouteStep, depth int) error
+
+// WalkRoute walks the route step tree depth-first, calling fn for each step (including nested steps of
+// Choice, Try, Multicast, Pipeline, Loop, ErrorHandlerScope and Saga).
+func WalkRoute(r *Route, fn WalkFunc) error {
+	return walkSteps(r.Steps, 0, fn)
+}
+
+func walkSteps(steps []api.RouteStep, depth int, fn WalkFunc) error {
+	for _, step := range steps {
+		err := fn(step, depth)
+		if errors.Is(err, SkipSteps) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, branch := range stepBranches(step) {
+			if err := walkSteps(branch.steps, depth+1, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// stepBranch is a named group of nested steps, e.g. choice 'when[0]' or try 'catch[1]'.
+type stepBranch struct {
+	name  string
+	kind  stepBranchKind
+	label string // human-readable description of the branch (predicate, error matcher,...)
+	steps []api.RouteStep
+}
+
+// path returns the path of the branch of the step with the given path.
+func (b stepBranch) path(stepPath string) string {
+	if b.kind == branchSteps {
+		return stepPath
+	}
+	return stepPath + "/" + b.name
+}
+
+// stepPath returns the path of the i-th step of the steps with the given path, e.g. 'order/choice[1]'.
+func stepPath(path string, step api.RouteStep, i int) string {
+	return fmt.Sprintf("%s/%s[%d]", path, stepKind(step), i)
+}
+
+// collectStepPaths maps the steps and their nested steps to their paths (see stepPath).
+func collectStepPaths(path string, steps []api.RouteStep, paths map[api.RouteStep]string) {
+	for i, step := range steps {
+		if reflect.ValueOf(step).Kind() != reflect.Pointer {
+			continue
+		}
+		paths[step] = stepPath(path, step, i)
+		for _, branch := range stepBranches(step) {
+			collectStepPaths(branch.path(paths[step]), branch.steps, paths)
+		}
+	}
+}
+
+type stepBranchKind int
+
+const (
+	branchSteps stepBranchKind = iota
+	branchWhen
+	branchOtherwise
+	branchCatch
+	branchFinally
+	branchOutput
+)
+
+// stepBranches returns nested steps of the given step in the order of declaration.
+func stepBranches(step api.RouteStep) []stepBranch {
+	switch t := step.(type) {
+	case *routestep.Pipeline:
+		return []stepBranch{{name: "steps", kind: branchSteps, steps: t.Steps}}
+
+	case *routestep.Loop:
+		return []stepBranch{{name: "steps", kind: branchSteps, steps: t.Steps}}
+
+	case *routestep.ErrorHandlerScope:
+		return []stepBranch{{name: "steps", kind: branchSteps, steps: t.Steps}}
+
+	case *routestep.Saga:
+		return []stepBranch{{name: "steps", kind: branchSteps, steps: t.Steps}}
+
+	case *routestep.Choice:
+		branches := make([]stepBranch, 0, len(t.WhenCases)+1)
+		for i, when := range t.WhenCases {
+			branches = append(branches, stepBranch{
+				name:  fmt.Sprintf("when[%d]", i),
+				kind:  branchWhen,
+				label: when.Predicate.String(),
+				steps: when.Steps,
+			})
+		}
+		if len(t.Otherwise) > 0 {
+			branches = append(branches, stepBranch{name: "otherwise", kind: branchOtherwise, label: "otherwise", steps: t.Otherwise})
+		}
+		return branches
+
+	case *routestep.Try:
+		branches := []stepBranch{{name: "steps", kind: branchSteps, steps: t.Steps}}
+		for i, catch := range t.WhenCatches {
+			branches = append(branches, stepBranch{
+				name:  fmt.Sprintf("catch[%d]", i),
+				kind:  branchCatch,
+				label: catch.ErrorMatcher.String(),
+				steps: catch.Steps,
+			})
+		}
+		if len(t.FinallySteps) > 0 {
+			branches = append(branches, stepBranch{name: "finally", kind: branchFinally, label: "finally", steps: t.FinallySteps})
+		}
+		return branches
+
+	case *routestep.Multicast:
+		branches := make([]stepBranch, 0, len(t.Outputs))
+		for i, output := range t.Outputs {
+			branches = append(branches, stepBranch{
+				name:  fmt.Sprintf("output[%d]", i),
+				kind:  branchOutput,
+				label: fmt.Sprintf("output %d", i),
+				steps: output.Steps,
+			})
+		}
+		return branches
+	}
+
+	return nil
+}
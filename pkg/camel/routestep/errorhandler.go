@@ -0,0 +1,61 @@
+package routestep
+
+import (
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+)
+
+// RedeliveryPolicy defines how a failed step is redelivered (processed again).
+type RedeliveryPolicy struct {
+	// MaximumRedeliveries is the max number of redeliveries (zero - no redelivery, -1 - unlimited).
+	MaximumRedeliveries int
+	// RedeliveryDelay is the delay before the first redelivery in milliseconds.
+	RedeliveryDelay int64
+	// BackOffMultiplier multiplies the delay of each next redelivery (values <= 1 - constant delay).
+	BackOffMultiplier float64
+	// MaximumRedeliveryDelay caps the delay in milliseconds (zero - no cap).
+	MaximumRedeliveryDelay int64
+	// Jitter randomizes the delay by the given fraction [0..1], e.g. 0.2 gives delay +-20%.
+	Jitter float64
+	// RetryWhile is a predicate evaluated against the failed exchange, the step is redelivered while it is true
+	// (zero value - no predicate).
+	RetryWhile expr.Definition
+	// RetryOn limits redelivery to the matched errors (empty - any error).
+	RetryOn []errs.Matcher
+}
+
+// ErrorHandler defines how the errors of the route steps are handled.
+type ErrorHandler struct {
+	Redelivery RedeliveryPolicy
+	// DeadLetterURI is the endpoint the failed exchange is sent to once the redeliveries are exhausted,
+	// the error is handled then and the rest of the route is skipped (empty - the exchange keeps the error).
+	DeadLetterURI string
+	// UseOriginalMessage sends the message as it was received by the route to the dead letter endpoint,
+	// instead of the message modified by the steps.
+	UseOriginalMessage bool
+}
+
+// DeadLetterChannel returns the error handler that sends the failed exchanges to the dead letter endpoint.
+func DeadLetterChannel(uri string) ErrorHandler {
+	return ErrorHandler{DeadLetterURI: uri}
+}
+
+// ErrorHandlerScope applies the error handler to the nested steps, it overrides the route error handler.
+type ErrorHandlerScope struct {
+	Name         string
+	ErrorHandler ErrorHandler
+	Steps        []api.RouteStep
+}
+
+func (s *ErrorHandlerScope) StepName() string {
+	if s.Name == "" {
+		if s.ErrorHandler.DeadLetterURI != "" {
+			return fmt.Sprintf("errorHandler[maximumRedeliveries=%d;deadLetter=%s]",
+				s.ErrorHandler.Redelivery.MaximumRedeliveries, s.ErrorHandler.DeadLetterURI)
+		}
+		return fmt.Sprintf("errorHandler[maximumRedeliveries=%d]", s.ErrorHandler.Redelivery.MaximumRedeliveries)
+	}
+	return s.Name
+}
@@ -0,0 +1,27 @@
+package routestep
+
+import (
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+)
+
+// Intercept processes the steps before every step of the route, the nested steps included.
+// The intercepted step is skipped if the steps fail or stop the routing of the exchange.
+type Intercept struct {
+	Steps []api.RouteStep
+}
+
+// InterceptFrom processes the steps before the route consumes the exchange from the endpoint matched by URIPattern
+// (empty - any endpoint). The pattern is the exact URI, a wildcard (e.g. 'direct:*') or a regular expression.
+type InterceptFrom struct {
+	URIPattern string
+	Steps      []api.RouteStep
+}
+
+// InterceptSendToEndpoint processes the steps before the exchange is sent to the endpoint matched by URIPattern
+// (see InterceptFrom), whether the endpoint is the target of a step, a dead letter channel or a saga action.
+type InterceptSendToEndpoint struct {
+	URIPattern string
+	// SkipSendToOriginalEndpoint processes the steps instead of sending the exchange to the endpoint.
+	SkipSendToOriginalEndpoint bool
+	Steps                      []api.RouteStep
+}
@@ -15,11 +15,19 @@ func (out OutputProcess) StepName() string {
 }
 
 type Multicast struct {
-	Name        string
-	Parallel    bool
+	Name     string
+	Parallel bool
+	// StopOnError completes the multicast on the first failed output, the remaining outputs are cancelled.
 	StopOnError bool
-	Aggregator  api.ExchangeAggregator
-	Outputs     []OutputProcess
+	// Timeout limits the processing time of all outputs in milliseconds (zero - no limit).
+	Timeout    int64
+	Aggregator api.ExchangeAggregator
+	// AggregatorRef is a name of the bean (api.ExchangeAggregator), used when Aggregator is nil.
+	AggregatorRef string
+	// ExecutorService is a name of the executor profile used in parallel mode,
+	// if empty each output is processed in its own goroutine.
+	ExecutorService string
+	Outputs         []OutputProcess
 }
 
 func (s *Multicast) StepName() string {
@@ -0,0 +1,29 @@
+package routestep
+
+import (
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+)
+
+type OnCompletionMode string
+
+const (
+	// OnCompletionAlways processes the steps regardless of the outcome of the exchange.
+	OnCompletionAlways OnCompletionMode = "always"
+	// OnCompleteOnly processes the steps once the exchange succeeds.
+	OnCompleteOnly OnCompletionMode = "onCompleteOnly"
+	// OnFailureOnly processes the steps once the exchange fails.
+	OnFailureOnly OnCompletionMode = "onFailureOnly"
+)
+
+// OnCompletion processes the steps once the route is done with the exchange, the steps receive the copy
+// of the final exchange and their outcome does not affect it.
+type OnCompletion struct {
+	// Mode is the outcome of the exchange the steps are processed on (empty - OnCompletionAlways).
+	Mode OnCompletionMode
+	// OnWhen is the optional predicate evaluated against the final exchange.
+	OnWhen expr.Definition
+	// Parallel processes the steps in a separate goroutine, the route does not wait for them.
+	Parallel bool
+	Steps    []api.RouteStep
+}
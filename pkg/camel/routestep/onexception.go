@@ -0,0 +1,18 @@
+package routestep
+
+import (
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+)
+
+// OnException handles the errors of any route step matched by ErrorMatcher, the handler steps are processed
+// once the redeliveries of the failed step are exhausted (see ErrorHandler).
+// If neither Handled nor Continued is set, the error is rethrown after the handler steps.
+type OnException struct {
+	ErrorMatcher errs.Matcher
+	Steps        []api.RouteStep
+	// Handled clears the error and stops the routing of the exchange.
+	Handled bool
+	// Continued clears the error and continues the routing with the step next to the failed one.
+	Continued bool
+}
@@ -0,0 +1,45 @@
+package routestep
+
+import (
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+)
+
+type SagaPropagation string
+
+const (
+	// SagaPropagationRequired joins the active saga or creates a new one.
+	SagaPropagationRequired SagaPropagation = "required"
+	// SagaPropagationRequiresNew always creates a new saga, the active one is resumed after the block.
+	SagaPropagationRequiresNew SagaPropagation = "requiresNew"
+	// SagaPropagationMandatory joins the active saga, fails if there is no one.
+	SagaPropagationMandatory SagaPropagation = "mandatory"
+	// SagaPropagationSupports joins the active saga if any.
+	SagaPropagationSupports SagaPropagation = "supports"
+	// SagaPropagationNever fails if there is an active saga.
+	SagaPropagationNever SagaPropagation = "never"
+)
+
+// Saga processes the nested steps within a saga (long-running action): the saga created by the step is completed
+// once the steps succeed and compensated once they fail. The compensation and completion endpoints of every
+// saga participant receive the exchange the participant joined the saga with.
+type Saga struct {
+	Name        string
+	Propagation SagaPropagation
+	// Timeout compensates the created saga once it elapses, in milliseconds (zero - no timeout).
+	Timeout         int64
+	CompensationURI string
+	CompletionURI   string
+	Steps           []api.RouteStep
+}
+
+func (s *Saga) StepName() string {
+	if s.Name == "" {
+		propagation := s.Propagation
+		if propagation == "" {
+			propagation = SagaPropagationRequired
+		}
+		return fmt.Sprintf("saga[propagation=%s]", propagation)
+	}
+	return s.Name
+}
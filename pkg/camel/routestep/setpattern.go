@@ -0,0 +1,18 @@
+package routestep
+
+import (
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+)
+
+type SetPattern struct {
+	Name    string
+	Pattern exchange.ExchangePattern
+}
+
+func (s *SetPattern) StepName() string {
+	if s.Name == "" {
+		return fmt.Sprintf("setPattern[%s]", s.Pattern)
+	}
+	return s.Name
+}
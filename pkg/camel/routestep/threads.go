@@ -0,0 +1,17 @@
+package routestep
+
+import "fmt"
+
+// Threads hands the rest of the steps over to the pool of the executor profile.
+type Threads struct {
+	Name string
+	// Profile is a name of the executor profile registered by means of Runtime.RegisterExecutorProfile.
+	Profile string
+}
+
+func (s *Threads) StepName() string {
+	if s.Name == "" {
+		return fmt.Sprintf("threads[%s]", s.Profile)
+	}
+	return s.Name
+}
@@ -23,7 +23,7 @@ func (s *Try) StepName() string {
 	if s.Name == "" {
 		when := make([]string, len(s.WhenCatches))
 		for i, w := range s.WhenCatches {
-			when[i] = fmt.Sprintf("%s:%s", w.ErrorMatcher.MatchMode, w.ErrorMatcher.Target)
+			when[i] = w.ErrorMatcher.String()
 		}
 		return fmt.Sprintf("try[%s]", strings.Join(when, ";"))
 	}
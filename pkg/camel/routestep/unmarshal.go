@@ -6,10 +6,15 @@ type Unmarshal struct {
 	Name       string
 	Format     string
 	TargetType any
+	// TargetTypeRef is a name of the bean used as a target type prototype, used when TargetType is nil.
+	TargetTypeRef string
 }
 
 func (s *Unmarshal) StepName() string {
 	if s.Name == "" {
+		if s.TargetType == nil {
+			return fmt.Sprintf("unmarshal[format=%s;targetType=%s]", s.Format, s.TargetTypeRef)
+		}
 		return fmt.Sprintf("unmarshal[format=%s;targetType=%v]", s.Format, s.TargetType)
 	}
 	return s.Name
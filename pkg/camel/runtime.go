@@ -4,28 +4,47 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/paveldanilin/go-camel/internal/expression"
 	"github.com/paveldanilin/go-camel/pkg/camel/api"
 	"github.com/paveldanilin/go-camel/pkg/camel/component"
 	"github.com/paveldanilin/go-camel/pkg/camel/converter"
 	"github.com/paveldanilin/go-camel/pkg/camel/dataformat"
 	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/executor"
 	"github.com/paveldanilin/go-camel/pkg/camel/logger"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+	"github.com/paveldanilin/go-camel/pkg/camel/saga"
 	"github.com/paveldanilin/go-camel/pkg/camel/template"
 	"github.com/paveldanilin/go-camel/pkg/camel/uri"
 	"log/slog"
 	"os"
 	"reflect"
+	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 type EndpointRegistry interface {
-	Endpoint(string) api.Endpoint
+	ResolveEndpoint(uri string) (api.Endpoint, error)
 }
 
 type ExchangeFactoryAware interface {
 	SetExchangeFactory(f api.ExchangeFactory)
 }
 
+// ExecutorServiceAware is implemented by components that execute exchanges by means of runtime executor profiles.
+type ExecutorServiceAware interface {
+	SetExecutorServiceProvider(p api.ExecutorServiceProvider)
+}
+
+// ExecutorRegistry holds executor profiles, pools are created on demand and shut down on Runtime stop.
+type ExecutorRegistry interface {
+	RegisterProfile(profile executor.Profile) error
+	Profile(name string) (executor.Profile, bool)
+	ExecutorService(name string) (api.ExecutorService, error)
+	Shutdown()
+}
+
 type ConverterRegistry interface {
 	// Register registers new converter that MUST implement Converter interface.
 	Register(conv any) error
@@ -48,10 +67,32 @@ type FuncRegistry interface {
 	Func(name string) func(*exchange.Exchange)
 }
 
+// BeanRegistry holds named objects (aggregators, functions, type prototypes,...)
+// that can be referenced by name from route definitions.
+type BeanRegistry interface {
+	RegisterBean(name string, bean any) error
+	Bean(name string) any
+}
+
 type route struct {
-	name     string
-	from     string
-	producer api.Producer
+	mu         sync.RWMutex
+	definition *Route
+	name       string
+	from       string
+	producer   api.Producer
+	consumer   api.Consumer
+	policies   []api.RoutePolicy
+	// onCompletions are processed once the route is done with the exchange (see Route.OnCompletions)
+	onCompletions []api.Processor
+	suspended     bool
+	// detached is TRUE if the suspended route is removed from the processors of its shared consumer
+	detached bool
+	// draining is not nil while the route is drained before the swap (see Runtime.ReloadRoute),
+	// it is closed once the swap is done
+	draining chan struct{}
+	inflight atomic.Int64
+	// keepOriginalMessage stores the copy of the received message for the dead letter channel (see Route.ErrorHandler)
+	keepOriginalMessage bool
 }
 
 type RuntimeStatus string
@@ -62,21 +103,38 @@ const (
 )
 
 type Runtime struct {
-	mu             sync.RWMutex
+	mu sync.RWMutex
+	// reloadMu serializes the route reloads and removals, rt.mu is released while a reloaded route is drained
+	reloadMu       sync.Mutex
 	name           string
 	status         RuntimeStatus
 	env            api.Env
 	messageHistory bool
 
 	funcRegistry       FuncRegistry
+	beanRegistry       BeanRegistry
 	componentRegistry  ComponentRegistry
 	dataFormatRegistry DataFormatRegistry
 	exchangeFactory    api.ExchangeFactory
 	converterRegistry  ConverterRegistry
-
-	routes    map[string]*route
-	endpoints map[string]api.Endpoint
-	consumers []api.Consumer
+	executorRegistry   ExecutorRegistry
+	routePolicies      []api.RoutePolicy
+	errorHandler       *routestep.ErrorHandler
+	onExceptions       []routestep.OnException
+	sagaService        api.SagaService
+	intercepts         []routestep.Intercept
+	interceptFroms     []routestep.InterceptFrom
+	interceptSendTos   []routestep.InterceptSendToEndpoint
+	// simpleContext provides the simple expressions with the env, the converters and the registered functions
+	simpleContext *expression.SimpleContext
+
+	routes         map[string]*route
+	routeTemplates map[string]*RouteTemplate
+	endpointsMu    sync.Mutex
+	endpoints      map[string]api.Endpoint
+	consumers      []api.Consumer
+
+	producerTemplate *ProducerTemplate
 
 	logger api.Logger
 
@@ -89,11 +147,27 @@ type RuntimeConfig struct {
 	Env                api.Env
 	ExchangeFactory    api.ExchangeFactory
 	FuncRegistry       FuncRegistry
+	BeanRegistry       BeanRegistry
 	ComponentRegistry  ComponentRegistry
 	DataFormatRegistry DataFormatRegistry
 	ConverterRegistry  ConverterRegistry
+	ExecutorRegistry   ExecutorRegistry
 	Logger             api.Logger
 	MessageHistory     bool
+	// RoutePolicies are applied to every route registered in the Runtime (before route's own policies).
+	RoutePolicies []api.RoutePolicy
+	// ErrorHandler is used by every route registered in the Runtime unless the route has its own error handler.
+	ErrorHandler *routestep.ErrorHandler
+	// OnExceptions are applied to every route registered in the Runtime after the route onException clauses
+	// (see NewOnException).
+	OnExceptions []routestep.OnException
+	// SagaService coordinates the sagas of the Saga steps (in-memory by default).
+	SagaService api.SagaService
+	// Intercepts, InterceptFroms and InterceptSendToEndpoints are applied to every route registered in the Runtime
+	// before the route ones (see NewIntercept, NewInterceptFrom and NewInterceptSendToEndpoint).
+	Intercepts               []routestep.Intercept
+	InterceptFroms           []routestep.InterceptFrom
+	InterceptSendToEndpoints []routestep.InterceptSendToEndpoint
 }
 
 func NewRuntime(config RuntimeConfig) *Runtime {
@@ -103,17 +177,27 @@ func NewRuntime(config RuntimeConfig) *Runtime {
 		name:               config.Name,
 		env:                config.Env,
 		funcRegistry:       config.FuncRegistry,
+		beanRegistry:       config.BeanRegistry,
 		componentRegistry:  config.ComponentRegistry,
 		dataFormatRegistry: config.DataFormatRegistry,
 		exchangeFactory:    config.ExchangeFactory,
 		converterRegistry:  config.ConverterRegistry,
+		executorRegistry:   config.ExecutorRegistry,
 		logger:             config.Logger,
+		routePolicies:      config.RoutePolicies,
+		errorHandler:       config.ErrorHandler,
+		onExceptions:       config.OnExceptions,
+		sagaService:        config.SagaService,
+		intercepts:         config.Intercepts,
+		interceptFroms:     config.InterceptFroms,
+		interceptSendTos:   config.InterceptSendToEndpoints,
 
 		messageHistory: config.MessageHistory,
 
-		routes:    map[string]*route{},
-		endpoints: map[string]api.Endpoint{},
-		consumers: []api.Consumer{},
+		routes:         map[string]*route{},
+		routeTemplates: map[string]*RouteTemplate{},
+		endpoints:      map[string]api.Endpoint{},
+		consumers:      []api.Consumer{},
 
 		ctx:    ctx,
 		cancel: cancel,
@@ -122,14 +206,24 @@ func NewRuntime(config RuntimeConfig) *Runtime {
 	if runtime.name == "" {
 		runtime.name = "CamelRuntime"
 	}
+	runtime.producerTemplate = runtime.NewProducerTemplate()
 	//runtime.ctx = context.WithValue(runtime.ctx, "CamelRuntimeName", runtime.headerName)
 
 	if runtime.funcRegistry == nil {
 		runtime.funcRegistry = newFuncRegistry()
 	}
+	if runtime.beanRegistry == nil {
+		runtime.beanRegistry = newBeanRegistry()
+	}
 	if runtime.componentRegistry == nil {
 		runtime.componentRegistry = component.NewRegistry()
 	}
+	if runtime.executorRegistry == nil {
+		runtime.executorRegistry = executor.NewRegistry()
+	}
+	if runtime.sagaService == nil {
+		runtime.sagaService = saga.NewInMemoryService()
+	}
 
 	// register default DataFormat registry
 	if runtime.dataFormatRegistry == nil {
@@ -149,6 +243,7 @@ func NewRuntime(config RuntimeConfig) *Runtime {
 		runtime.converterRegistry.Register(converter.StringToInt())
 		runtime.converterRegistry.Register(converter.StringToDateTime())
 	}
+	runtime.simpleContext = expression.NewSimpleContext(runtime.env, runtime.converterRegistry)
 
 	return runtime
 }
@@ -169,6 +264,53 @@ func (rt *Runtime) MustRegisterFunc(name string, fn func(e *exchange.Exchange))
 	}
 }
 
+// RegisterExpressionFunction registers the function available to the simple expressions by name
+// (see expr.Simple), the function may return an error as the last result, e.g.
+//
+//	rt.RegisterExpressionFunction("discount", func(total float64) float64 { return total * 0.9 })
+func (rt *Runtime) RegisterExpressionFunction(name string, fn any) error {
+	return rt.simpleContext.RegisterFunction(name, fn)
+}
+
+func (rt *Runtime) MustRegisterExpressionFunction(name string, fn any) {
+	err := rt.RegisterExpressionFunction(name, fn)
+	if err != nil {
+		panic(fmt.Errorf("camel: %w", err))
+	}
+}
+
+// RegisterBean registers a named bean in the current Runtime.
+// Beans can be referenced by name from route definitions (aggregators, func expressions, unmarshal target types).
+func (rt *Runtime) RegisterBean(name string, bean any) error {
+	return rt.beanRegistry.RegisterBean(name, bean)
+}
+
+func (rt *Runtime) MustRegisterBean(name string, bean any) {
+	err := rt.RegisterBean(name, bean)
+	if err != nil {
+		panic(fmt.Errorf("camel: %w", err))
+	}
+}
+
+// RegisterExecutorProfile registers a named pool profile that can be referenced by concurrent EIPs
+// (parallel multicast, threads) and components (seda).
+// The 'default' profile can be overridden, otherwise executor.NewDefaultProfile is used.
+func (rt *Runtime) RegisterExecutorProfile(profile executor.Profile) error {
+	return rt.executorRegistry.RegisterProfile(profile)
+}
+
+func (rt *Runtime) MustRegisterExecutorProfile(profile executor.Profile) {
+	err := rt.RegisterExecutorProfile(profile)
+	if err != nil {
+		panic(fmt.Errorf("camel: %w", err))
+	}
+}
+
+// ExecutorService returns the pool of the executor profile.
+func (rt *Runtime) ExecutorService(profile string) (api.ExecutorService, error) {
+	return rt.executorRegistry.ExecutorService(profile)
+}
+
 // RegisterComponent register the given Component in the current Runtime.
 
 func (rt *Runtime) RegisterComponent(c api.Component) error {
@@ -179,6 +321,9 @@ func (rt *Runtime) RegisterComponent(c api.Component) error {
 	if ef, isExchangeFactoryAware := c.(ExchangeFactoryAware); isExchangeFactoryAware {
 		ef.SetExchangeFactory(rt)
 	}
+	if ea, isExecutorServiceAware := c.(ExecutorServiceAware); isExecutorServiceAware {
+		ea.SetExecutorServiceProvider(rt)
+	}
 	return nil
 }
 
@@ -205,30 +350,97 @@ func (rt *Runtime) MustRegisterDataFormat(name string, dataFormat api.DataFormat
 }
 
 func (rt *Runtime) RegisterRoute(routeDefinition *Route) error {
-	if _, exists := rt.routes[routeDefinition.Name]; exists {
-		rt.logger.Error(context.Background(), fmt.Sprintf("Route with name '%s' already registered", routeDefinition.Name))
-		return errors.New("route already registered: " + routeDefinition.Name)
+	return rt.registerRoutes([]*Route{routeDefinition})
+}
+
+// registerRoutes compiles all the route definitions first, then registers them,
+// thus no route is registered if any of the definitions fails.
+func (rt *Runtime) registerRoutes(routeDefinitions []*Route) error {
+	names := make(map[string]struct{}, len(routeDefinitions))
+	routes := make([]*route, 0, len(routeDefinitions))
+	for _, routeDefinition := range routeDefinitions {
+		if _, duplicate := names[routeDefinition.Name]; duplicate || rt.Route(routeDefinition.Name) != nil {
+			rt.logger.Error(context.Background(), fmt.Sprintf("Route with name '%s' already registered", routeDefinition.Name))
+			return errors.New("route already registered: " + routeDefinition.Name)
+		}
+		names[routeDefinition.Name] = struct{}{}
+
+		r, err := rt.compileRoute(routeDefinition)
+		if err != nil {
+			return err
+		}
+		routes = append(routes, r)
 	}
 
-	r, err := compileRoute(compilerConfig{
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	for _, r := range routes {
+		if _, exists := rt.routes[r.name]; exists {
+			return errors.New("route already registered: " + r.name)
+		}
+	}
+	for _, r := range routes {
+		rt.addRoute(r)
+		rt.logger.Info(context.Background(), fmt.Sprintf("Route '%s' registered and consuming from: '%s'", r.name, r.from))
+	}
+
+	return nil
+}
+
+func (rt *Runtime) compilerConfig() compilerConfig {
+	return compilerConfig{
 		logger:             rt.logger,
 		env:                rt.env,
 		funcRegistry:       rt.funcRegistry,
+		beanRegistry:       rt.beanRegistry,
 		dataFormatRegistry: rt.dataFormatRegistry,
 		converterRegistry:  rt.converterRegistry,
+		componentRegistry:  rt.componentRegistry,
+		executorRegistry:   rt.executorRegistry,
 		endpointRegistry:   rt,
 		preProcessor:       rt.preProcessor,
 		postProcessor:      rt.postProcessor,
-	}, routeDefinition)
+		errorHandler:       rt.errorHandler,
+		onExceptions:       rt.onExceptions,
+		sagaService:        rt.sagaService,
+		intercepts:         rt.intercepts,
+		interceptFroms:     rt.interceptFroms,
+		interceptSendTos:   rt.interceptSendTos,
+		simpleContext:      rt.simpleContext,
+	}
+}
+
+// ValidateRoute checks the route definition without registering it.
+// All found problems are reported at once by means of RouteValidationError.
+func (rt *Runtime) ValidateRoute(routeDefinition *Route) error {
+	return validateRoute(rt.compilerConfig(), routeDefinition)
+}
+
+// compileRoute validates and compiles the route definition and initializes route policies.
+func (rt *Runtime) compileRoute(routeDefinition *Route) (*route, error) {
+	if err := rt.ValidateRoute(routeDefinition); err != nil {
+		rt.logger.Error(context.Background(), "Route validation failed", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	r, err := compileRoute(rt.compilerConfig(), routeDefinition)
 	if err != nil {
 		rt.logger.Error(context.Background(), "Route compilation failed", slog.String("error", err.Error()))
-		return err
+		return nil, err
 	}
 
-	rt.routes[routeDefinition.Name] = r
-	rt.logger.Info(context.Background(), fmt.Sprintf("Route '%s' registered and consuming from: '%s'", routeDefinition.Name, routeDefinition.From))
+	r.definition = routeDefinition
+	r.policies = append(append([]api.RoutePolicy{}, rt.routePolicies...), routeDefinition.Policies...)
+	return r, nil
+}
 
-	return nil
+// addRoute adds compiled route to the runtime, must be called under rt.mu lock.
+func (rt *Runtime) addRoute(r *route) {
+	for _, policy := range r.policies {
+		policy.OnInit(r)
+	}
+	rt.routes[r.name] = r
 }
 
 func (rt *Runtime) MustRegisterRoute(routeDefinition *Route) {
@@ -238,13 +450,85 @@ func (rt *Runtime) MustRegisterRoute(routeDefinition *Route) {
 	}
 }
 
-func (rt *Runtime) Endpoint(uri string) api.Endpoint {
-	if endpoint, exists := rt.endpoints[uri]; exists {
-		return endpoint
+// RegisterRouteTemplate registers the given RouteTemplate, see AddRouteFromTemplate.
+func (rt *Runtime) RegisterRouteTemplate(routeTemplate *RouteTemplate) error {
+	if _, exists := rt.routeTemplates[routeTemplate.Id]; exists {
+		return errors.New("route template already registered: " + routeTemplate.Id)
 	}
+	rt.routeTemplates[routeTemplate.Id] = routeTemplate
 	return nil
 }
 
+func (rt *Runtime) MustRegisterRouteTemplate(routeTemplate *RouteTemplate) {
+	err := rt.RegisterRouteTemplate(routeTemplate)
+	if err != nil {
+		panic(fmt.Errorf("camel: %w", err))
+	}
+}
+
+// AddRouteFromTemplate instantiates the route template with the given params and registers a new route.
+func (rt *Runtime) AddRouteFromTemplate(templateId, routeName string, params map[string]any) error {
+	routeTemplate, exists := rt.routeTemplates[templateId]
+	if !exists {
+		return errors.New("route template not found: " + templateId)
+	}
+
+	routeDefinition, err := routeTemplate.Instantiate(routeName, params)
+	if err != nil {
+		return err
+	}
+
+	return rt.RegisterRoute(routeDefinition)
+}
+
+func (rt *Runtime) MustAddRouteFromTemplate(templateId, routeName string, params map[string]any) {
+	err := rt.AddRouteFromTemplate(templateId, routeName, params)
+	if err != nil {
+		panic(fmt.Errorf("camel: %w", err))
+	}
+}
+
+// Endpoint returns the endpoint for the given URI or nil if the endpoint cannot be resolved (see ResolveEndpoint).
+func (rt *Runtime) Endpoint(uri string) api.Endpoint {
+	endpoint, _ := rt.ResolveEndpoint(uri)
+	return endpoint
+}
+
+// ResolveEndpoint returns the endpoint for the given URI.
+// The endpoint is created on demand by the URI component and cached,
+// URIs are normalized, so the order of URI parameters does not matter.
+func (rt *Runtime) ResolveEndpoint(rawUri string) (api.Endpoint, error) {
+	key, err := uri.Normalize(rawUri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URI '%s': %w", rawUri, err)
+	}
+
+	rt.endpointsMu.Lock()
+	defer rt.endpointsMu.Unlock()
+
+	if endpoint, exists := rt.endpoints[key]; exists {
+		return endpoint, nil
+	}
+
+	parsedUri, err := uri.Parse(key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URI '%s': %w", rawUri, err)
+	}
+
+	component := rt.componentRegistry.Component(parsedUri.Component())
+	if component == nil {
+		return nil, fmt.Errorf("component '%s' not found for URI '%s'", parsedUri.Component(), rawUri)
+	}
+
+	endpoint, err := component.CreateEndpoint(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create endpoint for URI '%s': %w", rawUri, err)
+	}
+	rt.endpoints[key] = endpoint
+
+	return endpoint, nil
+}
+
 func (rt *Runtime) NewExchange(c context.Context) *exchange.Exchange {
 	var newExchange *exchange.Exchange
 	if rt.exchangeFactory == nil {
@@ -270,20 +554,16 @@ func (rt *Runtime) postProcessor(e *exchange.Exchange) {
 
 }
 
+// Send sends the InOut message to the endpoint and returns the processed exchange.
+// See ProducerTemplate for a richer API.
 func (rt *Runtime) Send(ctx context.Context, uri string, body any, headers map[string]any) (*exchange.Exchange, error) {
-	endpoint := rt.Endpoint(uri)
-	if endpoint == nil {
-		return nil, errors.New("endpoint not found for uri: " + uri)
-	}
-
-	producer, err := endpoint.CreateProducer()
+	producer, err := rt.producerTemplate.producer(uri)
 	if err != nil {
 		return nil, err
 	}
 
-	exchangeCopy := rt.NewExchange(ctx)
-	exchangeCopy.Message().Body = body
-	exchangeCopy.Message().Headers().SetAll(headers)
+	exchangeCopy := rt.producerTemplate.newExchange(ctx, body, headers)
+	exchangeCopy.SetPattern(exchange.InOut)
 
 	producer.Process(exchangeCopy)
 
@@ -298,7 +578,7 @@ func (rt *Runtime) SendBody(ctx context.Context, uri string, body any) (*exchang
 	if exchangeCopy.Error() != nil {
 		return nil, exchangeCopy.Error()
 	}
-	return exchangeCopy.Message(), nil
+	return exchangeCopy.Reply(), nil
 }
 
 func (rt *Runtime) SendHeaders(ctx context.Context, uri string, headers map[string]any) (*exchange.Message, error) {
@@ -309,16 +589,104 @@ func (rt *Runtime) SendHeaders(ctx context.Context, uri string, headers map[stri
 	if exchangeCopy.Error() != nil {
 		return nil, exchangeCopy.Error()
 	}
-	return exchangeCopy.Message(), nil
+	return exchangeCopy.Reply(), nil
+}
+
+// isConsumerInUse checks if the consumer is used by any route of the runtime.
+func (rt *Runtime) isConsumerInUse(consumer api.Consumer) bool {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+
+	for _, r := range rt.routes {
+		if r.consumer == consumer {
+			return true
+		}
+	}
+	return false
 }
 
 func (rt *Runtime) Route(routeId string) *route {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+
 	if r, exists := rt.routes[routeId]; exists {
 		return r
 	}
 	return nil
 }
 
+// RouteDefinitions returns definitions of all registered routes ordered by route name.
+func (rt *Runtime) RouteDefinitions() []*Route {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+
+	names := make([]string, 0, len(rt.routes))
+	for name := range rt.routes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	definitions := make([]*Route, 0, len(names))
+	for _, name := range names {
+		definitions = append(definitions, rt.routes[name].definition)
+	}
+	return definitions
+}
+
+// resolveRouteFrom resolves variables (${var_name}) in route.from by means of Runtime env.
+func (rt *Runtime) resolveRouteFrom(r *route) (string, error) {
+	routeFrom := r.from
+	routeFromVars, err := template.Vars(routeFrom)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve variables in route '%s' from '%s': %w", r.name, routeFrom, err)
+	}
+	if len(routeFromVars) == 0 {
+		return routeFrom, nil
+	}
+
+	if rt.env == nil {
+		return "", fmt.Errorf("failed to resolve variables in route '%s' from '%s': env is nil", r.name, routeFrom)
+	}
+	varNamesAndValues := make(map[string]any, len(routeFromVars))
+	for _, varName := range routeFromVars {
+		if varValue, varExists := rt.env.LookupVar(varName); varExists {
+			varNamesAndValues[varName] = varValue
+		} else {
+			// TODO: error
+		}
+	}
+	routeFrom, err = template.Render(routeFrom, varNamesAndValues)
+	if err != nil {
+		return "", fmt.Errorf("failed to interpolate variables in route '%s' from dynamic '%s': %w", r.name, r.from, err)
+	}
+	return routeFrom, nil
+}
+
+// createRouteConsumer resolves the route endpoint and creates (but does not start) the route consumer.
+// Must be called under rt.mu lock.
+func (rt *Runtime) createRouteConsumer(r *route) error {
+	routeFrom, err := rt.resolveRouteFrom(r)
+	if err != nil {
+		return err
+	}
+
+	// Resolve/create endpoint
+	endpoint, err := rt.ResolveEndpoint(routeFrom)
+	if err != nil {
+		return fmt.Errorf("failed to resolve endpoint in route '%s' that consumes from '%s': %w", r.name, routeFrom, err)
+	}
+
+	// Create consumer
+	consumer, err := endpoint.CreateConsumer(r)
+	if err != nil {
+		return fmt.Errorf("failed to create consumer in step '%s' that consumes from '%s': %w", r.name, routeFrom, err)
+	}
+	r.consumer = consumer
+	rt.consumers = append(rt.consumers, consumer)
+
+	return nil
+}
+
 func (rt *Runtime) Start() error {
 	rt.mu.Lock()
 	defer rt.mu.Unlock()
@@ -331,59 +699,9 @@ func (rt *Runtime) Start() error {
 	rt.logger.Info(context.Background(), fmt.Sprintf("Camel runtime '%s' starting...", rt.name))
 
 	for _, r := range rt.routes {
-		// Check if route.from contains variables (${var_name}), resolve values
-		routeFrom := r.from
-		routeFromVars, err := template.Vars(routeFrom)
-		if err != nil {
-			return fmt.Errorf("failed to resolve variables in route '%s' from '%s': %w", r.name, routeFrom, err)
-		}
-		// resolve variables
-		if len(routeFromVars) > 0 {
-			if rt.env == nil {
-				return fmt.Errorf("failed to resolve variables in route '%s' from '%s': env is nil", r.name, routeFrom)
-			}
-			varNamesAndValues := make(map[string]any, len(routeFromVars))
-			for _, varName := range routeFromVars {
-				if varValue, varExists := rt.env.LookupVar(varName); varExists {
-					varNamesAndValues[varName] = varValue
-				} else {
-					// TODO: error
-				}
-			}
-			routeFrom, err = template.Render(routeFrom, varNamesAndValues)
-			if err != nil {
-				return fmt.Errorf("failed to interpolate variables in route '%s' from dynamic '%s': %w", r.name, r.from, err)
-			}
-		}
-
-		// Parse route.from URI and locate component
-		fromUri, err := uri.Parse(routeFrom, nil)
-		if err != nil {
-			return fmt.Errorf("invalid URI format in route '%s' that consumes from '%s': %w", r.name, routeFrom, err)
-		}
-
-		// Resolve component
-		component := rt.componentRegistry.Component(fromUri.Component())
-		if component == nil {
-			return fmt.Errorf("component '%s' not found in step '%s' that consumes from '%s'", fromUri.Component(), r.name, routeFrom)
-		}
-
-		// Resolve/create endpoint
-		endpoint, endpointExists := rt.endpoints[routeFrom]
-		if !endpointExists {
-			endpoint, err = component.CreateEndpoint(routeFrom)
-			if err != nil {
-				return fmt.Errorf("failed to create endpoint in step '%s' that consumes from '%s': %w", r.name, routeFrom, err)
-			}
-			rt.endpoints[routeFrom] = endpoint
-		}
-
-		// Create consumer
-		consumer, err := endpoint.CreateConsumer(r.producer)
-		if err != nil {
-			return fmt.Errorf("failed to create consumer in step '%s' that consumes from '%s': %w", r.name, routeFrom, err)
+		if err := rt.createRouteConsumer(r); err != nil {
+			return err
 		}
-		rt.consumers = append(rt.consumers, consumer)
 	}
 
 	// Start consumers
@@ -393,6 +711,12 @@ func (rt *Runtime) Start() error {
 		}
 	}
 
+	for _, r := range rt.routes {
+		for _, policy := range r.policies {
+			policy.OnStart(r)
+		}
+	}
+
 	rt.logger.Info(context.Background(), fmt.Sprintf("Camel runtime '%s' started", rt.name))
 	rt.status = RuntimeStatusStarted
 
@@ -412,6 +736,12 @@ func (rt *Runtime) Stop() error {
 
 	rt.cancel()
 
+	for _, r := range rt.routes {
+		for _, policy := range r.policies {
+			policy.OnStop(r)
+		}
+	}
+
 	for _, consumer := range rt.consumers {
 		if err := consumer.Stop(); err != nil {
 			return err
@@ -419,7 +749,11 @@ func (rt *Runtime) Stop() error {
 	}
 
 	rt.consumers = nil
-	rt.endpoints = nil
+	rt.endpointsMu.Lock()
+	rt.endpoints = map[string]api.Endpoint{}
+	rt.endpointsMu.Unlock()
+	rt.producerTemplate.Stop()
+	rt.executorRegistry.Shutdown()
 	rt.routes = nil
 
 	rt.logger.Info(context.Background(), fmt.Sprintf("Camel runetime '%s' stopped", rt.name))
@@ -0,0 +1,178 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/paveldanilin/go-camel/internal/processor"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"sync"
+	"time"
+)
+
+// ErrSagaCompensated is returned when a compensated saga is completed or joined.
+var ErrSagaCompensated = errors.New("saga is compensated")
+
+// ErrSagaCompleted is returned when a completed saga is compensated or joined.
+var ErrSagaCompleted = errors.New("saga is completed")
+
+// InMemoryService keeps the active sagas in memory, the sagas are lost once the process exits.
+type InMemoryService struct {
+	mu    sync.Mutex
+	sagas map[string]*inMemoryCoordinator
+}
+
+func NewInMemoryService() *InMemoryService {
+	return &InMemoryService{
+		sagas: map[string]*inMemoryCoordinator{},
+	}
+}
+
+func (s *InMemoryService) NewSaga(timeout time.Duration) (api.SagaCoordinator, error) {
+	c := &inMemoryCoordinator{
+		id:      uuid.NewString(),
+		service: s,
+	}
+
+	s.mu.Lock()
+	s.sagas[c.id] = c
+	s.mu.Unlock()
+
+	if timeout > 0 {
+		// The compensation errors of the expired saga have no one to be reported to
+		c.timer = time.AfterFunc(timeout, func() { _ = c.Compensate() })
+	}
+	return c, nil
+}
+
+func (s *InMemoryService) Saga(id string) (api.SagaCoordinator, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, exists := s.sagas[id]
+	return c, exists
+}
+
+func (s *InMemoryService) remove(id string) {
+	s.mu.Lock()
+	delete(s.sagas, id)
+	s.mu.Unlock()
+}
+
+type sagaStatus int
+
+const (
+	sagaActive sagaStatus = iota
+	sagaCompensated
+	sagaCompleted
+)
+
+type sagaParticipant struct {
+	step     api.SagaStep
+	exchange *exchange.Exchange
+}
+
+type inMemoryCoordinator struct {
+	mu           sync.Mutex
+	id           string
+	status       sagaStatus
+	participants []sagaParticipant
+	timer        *time.Timer
+	service      *InMemoryService
+}
+
+func (c *inMemoryCoordinator) Id() string {
+	return c.id
+}
+
+// BeginStep keeps the copy of the exchange, the copy is not bound to the exchange context,
+// thus the actions can be processed once the exchange is done (e.g. on timeout).
+func (c *inMemoryCoordinator) BeginStep(e *exchange.Exchange, step api.SagaStep) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.statusErr(); err != nil {
+		return err
+	}
+	c.participants = append(c.participants, sagaParticipant{
+		step:     step,
+		exchange: e.CopyWithContext(context.Background()),
+	})
+	return nil
+}
+
+func (c *inMemoryCoordinator) Compensate() error {
+	participants, err := c.finish(sagaCompensated)
+	if err != nil || participants == nil {
+		return err
+	}
+
+	var errs []error
+	for i := len(participants) - 1; i >= 0; i-- {
+		if err := c.process(participants[i].step.Compensation, participants[i].exchange); err != nil {
+			errs = append(errs, fmt.Errorf("compensation: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *inMemoryCoordinator) Complete() error {
+	participants, err := c.finish(sagaCompleted)
+	if err != nil || participants == nil {
+		return err
+	}
+
+	var errs []error
+	for _, participant := range participants {
+		if err := c.process(participant.step.Completion, participant.exchange); err != nil {
+			errs = append(errs, fmt.Errorf("completion: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// finish moves the active saga to the given status and returns its participants,
+// finishing the saga with the same status twice is a no-op (nil participants).
+func (c *inMemoryCoordinator) finish(status sagaStatus) ([]sagaParticipant, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.status == status {
+		return nil, nil
+	}
+	if err := c.statusErr(); err != nil {
+		return nil, err
+	}
+
+	c.status = status
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.service.remove(c.id)
+
+	participants := c.participants
+	if participants == nil {
+		participants = []sagaParticipant{}
+	}
+	return participants, nil
+}
+
+func (c *inMemoryCoordinator) statusErr() error {
+	switch c.status {
+	case sagaCompensated:
+		return fmt.Errorf("saga %s: %w", c.id, ErrSagaCompensated)
+	case sagaCompleted:
+		return fmt.Errorf("saga %s: %w", c.id, ErrSagaCompleted)
+	}
+	return nil
+}
+
+func (c *inMemoryCoordinator) process(action api.Processor, e *exchange.Exchange) error {
+	if action == nil {
+		return nil
+	}
+	processor.Invoke(action, e)
+	return e.Error()
+}
@@ -0,0 +1,109 @@
+package saga
+
+import (
+	"errors"
+	"github.com/paveldanilin/go-camel/internal/eip/fn"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recorder struct {
+	mu      sync.Mutex
+	actions []string
+}
+
+func (r *recorder) action(name string, err error) api.Processor {
+	return fn.NewProcessor("test", "action", func(e *exchange.Exchange) {
+		r.mu.Lock()
+		r.actions = append(r.actions, name)
+		r.mu.Unlock()
+		if err != nil {
+			e.SetError(err)
+		}
+	})
+}
+
+func (r *recorder) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.actions...)
+}
+
+func equalActions(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestInMemoryService_Compensate(t *testing.T) {
+	service := NewInMemoryService()
+	r := &recorder{}
+
+	saga, _ := service.NewSaga(0)
+	for _, name := range []string{"a", "b", "c"} {
+		if err := saga.BeginStep(exchange.NewExchange(nil), api.SagaStep{Compensation: r.action(name, nil)}); err != nil {
+			t.Fatalf("TestInMemoryService_Compensate(): unexpected error: %s", err)
+		}
+	}
+
+	if err := saga.Compensate(); err != nil {
+		t.Fatalf("TestInMemoryService_Compensate(): unexpected error: %s", err)
+	}
+	if got := r.recorded(); !equalActions(got, []string{"c", "b", "a"}) {
+		t.Fatalf("TestInMemoryService_Compensate() = %v; want [c b a]", got)
+	}
+	if _, exists := service.Saga(saga.Id()); exists {
+		t.Fatalf("TestInMemoryService_Compensate(): expected saga to be removed")
+	}
+	if err := saga.Complete(); !errors.Is(err, ErrSagaCompensated) {
+		t.Fatalf("TestInMemoryService_Compensate() = %v; want %v", err, ErrSagaCompensated)
+	}
+}
+
+func TestInMemoryService_Complete(t *testing.T) {
+	service := NewInMemoryService()
+	r := &recorder{}
+
+	saga, _ := service.NewSaga(0)
+	_ = saga.BeginStep(exchange.NewExchange(nil), api.SagaStep{Completion: r.action("a", nil)})
+	_ = saga.BeginStep(exchange.NewExchange(nil), api.SagaStep{Completion: r.action("b", errors.New("failed"))})
+
+	err := saga.Complete()
+	if err == nil || err.Error() != "completion: failed" {
+		t.Fatalf("TestInMemoryService_Complete() = %v; want completion error", err)
+	}
+	if got := r.recorded(); !equalActions(got, []string{"a", "b"}) {
+		t.Fatalf("TestInMemoryService_Complete() = %v; want [a b]", got)
+	}
+	if err := saga.BeginStep(exchange.NewExchange(nil), api.SagaStep{}); !errors.Is(err, ErrSagaCompleted) {
+		t.Fatalf("TestInMemoryService_Complete() = %v; want %v", err, ErrSagaCompleted)
+	}
+}
+
+func TestInMemoryService_Timeout(t *testing.T) {
+	service := NewInMemoryService()
+	compensated := make(chan struct{})
+
+	saga, _ := service.NewSaga(10 * time.Millisecond)
+	_ = saga.BeginStep(exchange.NewExchange(nil), api.SagaStep{Compensation: fn.NewProcessor("test", "action", func(e *exchange.Exchange) {
+		close(compensated)
+	})})
+
+	select {
+	case <-compensated:
+	case <-time.After(time.Second):
+		t.Fatalf("TestInMemoryService_Timeout(): expected saga to be compensated")
+	}
+	if err := saga.Complete(); !errors.Is(err, ErrSagaCompensated) {
+		t.Fatalf("TestInMemoryService_Timeout() = %v; want %v", err, ErrSagaCompensated)
+	}
+}
@@ -195,6 +195,45 @@ func Vars(input string) ([]string, error) {
 	return varNames, nil
 }
 
+// Replace substitutes only variables (${var_name}) that exist in values, other variables are left untouched.
+// A nil value is substituted with an empty string.
+//
+//	Example: Replace("direct:${customer}?x=${header.x}", {"customer": "acme"}) -> "direct:acme?x=${header.x}"
+func Replace(input string, values map[string]any) (string, error) {
+	var builder strings.Builder
+	runes := []rune(input)
+	n := len(runes)
+	i := 0
+
+	for i < n {
+		if runes[i] == '$' && i+1 < n && runes[i+1] == '{' {
+			varStart := i
+			i += 2
+			pathStart := i
+			for ; i < n && runes[i] != '}'; i++ {
+			}
+			if i == n {
+				return "", fmt.Errorf("unclosed variable at position %d", pathStart-2)
+			}
+			path := string(runes[pathStart:i])
+			i++
+
+			if value, exists := values[path]; exists {
+				if value != nil {
+					builder.WriteString(fmt.Sprintf("%v", value))
+				}
+			} else {
+				builder.WriteString(string(runes[varStart:i]))
+			}
+			continue
+		}
+		builder.WriteRune(runes[i])
+		i++
+	}
+
+	return builder.String(), nil
+}
+
 func Render(input string, data map[string]any) (string, error) {
 	t, err := Parse(input)
 	if err != nil {
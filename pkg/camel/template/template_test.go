@@ -123,3 +123,19 @@ func TestVars(t *testing.T) {
 		t.Fatalf("TestVars(): expected 2 variables, but got %d", len(vars))
 	}
 }
+
+func TestReplace(t *testing.T) {
+	result, err := Replace("direct:${customer}-orders${suffix}?limit=${limit}&x=${header.x}", map[string]any{
+		"customer": "acme",
+		"suffix":   nil,
+		"limit":    10,
+	})
+	if err != nil {
+		t.Fatalf("TestReplace(): %s", err)
+	}
+
+	expected := "direct:acme-orders?limit=10&x=${header.x}"
+	if result != expected {
+		t.Errorf("TestReplace() = %s; want %s", result, expected)
+	}
+}
@@ -2,6 +2,7 @@ package uri
 
 import (
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -179,6 +180,49 @@ func Parse(uri string, opts *ParseOptions) (*URI, error) {
 	return u, nil
 }
 
+// Normalize returns canonical form of the URI: query parameters are sorted by name
+// (the order of repeated parameters is kept), thus URIs that differ only in the parameters order are equal.
+//
+//	Example: "timer:foo?period=100&delay=5" -> "timer:foo?delay=5&period=100"
+func Normalize(uri string) (string, error) {
+	uri = strings.TrimSpace(uri)
+	if _, err := url.Parse(uri); err != nil {
+		return "", err
+	}
+
+	base, fragment, hasFragment := strings.Cut(uri, "#")
+	base, query, _ := strings.Cut(base, "?")
+
+	pairs := make([]string, 0)
+	for _, pair := range strings.Split(query, "&") {
+		if pair != "" {
+			pairs = append(pairs, pair)
+		}
+	}
+	paramName := func(pair string) string {
+		name, _, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			return unescaped
+		}
+		return name
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return paramName(pairs[i]) < paramName(pairs[j])
+	})
+
+	var b strings.Builder
+	b.WriteString(base)
+	if len(pairs) > 0 {
+		b.WriteString("?")
+		b.WriteString(strings.Join(pairs, "&"))
+	}
+	if hasFragment {
+		b.WriteString("#")
+		b.WriteString(fragment)
+	}
+	return b.String(), nil
+}
+
 // parse decodes Camel-like URI and returns map[string]string.
 // Input examples:
 //   - "timer:foo?period=1000"
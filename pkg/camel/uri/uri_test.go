@@ -111,3 +111,27 @@ func TestParseURI_RegularURL(t *testing.T) {
 		t.Errorf("Expected param x '%s', but got '%s", expectedParam, uri.MustParam("x"))
 	}
 }
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{uri: "direct:foo", want: "direct:foo"},
+		{uri: " direct:foo? ", want: "direct:foo"},
+		{uri: "timer:foo?period=100&delay=5", want: "timer:foo?delay=5&period=100"},
+		{uri: "timer:foo?delay=5&period=100", want: "timer:foo?delay=5&period=100"},
+		{uri: "kafka:topic?b=2&a=1&b=1", want: "kafka:topic?a=1&b=2&b=1"},
+		{uri: "http://host:8080/a?y=1&x=2#frag", want: "http://host:8080/a?x=2&y=1#frag"},
+	}
+
+	for _, tt := range tests {
+		got, err := Normalize(tt.uri)
+		if err != nil {
+			t.Fatalf("Normalize(%q): unexpected error: %s", tt.uri, err)
+		}
+		if got != tt.want {
+			t.Errorf("Normalize(%q): expected '%s', but got '%s'", tt.uri, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"context"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/timer"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"testing"
+)
+
+func TestRuntime_ResolveEndpoint(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(timer.NewComponent())
+
+	endpoint, err := testCamelRuntime.ResolveEndpoint("timer:tick?interval=100ms&delay=5")
+	if err != nil {
+		t.Fatalf("TestRuntime_ResolveEndpoint(): failed to resolve endpoint: %s", err)
+	}
+	if same := testCamelRuntime.Endpoint("timer:tick?delay=5&interval=100ms"); same != endpoint {
+		t.Fatalf("TestRuntime_ResolveEndpoint(): expected cached endpoint regardless of parameters order")
+	}
+
+	if _, err := testCamelRuntime.ResolveEndpoint("kafka:orders"); err == nil {
+		t.Fatalf("TestRuntime_ResolveEndpoint(): expected error for unknown component")
+	}
+}
+
+func TestRoute_ToRegistrationOrder(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	// 'greet' sends to 'direct:name' that is consumed by the route registered later
+	greet, err := camel.NewRoute("greet", "direct:greet").
+		To("", "direct:name").
+		SetBody("", expr.Simple("'Hello, ' + body")).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_ToRegistrationOrder(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(greet)
+
+	name, err := camel.NewRoute("name", "direct:name").
+		SetBody("", expr.Constant("Camel")).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_ToRegistrationOrder(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(name)
+
+	err = testCamelRuntime.Start()
+	if err != nil {
+		t.Fatalf("TestRoute_ToRegistrationOrder(): failed to start camel runtime: %s", err)
+	}
+
+	result, err := testCamelRuntime.SendBody(context.TODO(), "direct:greet", nil)
+	if err != nil {
+		t.Fatalf("TestRoute_ToRegistrationOrder(): failed to call route: %s", err)
+	}
+	if result.Body != "Hello, Camel" {
+		t.Fatalf("TestRoute_ToRegistrationOrder(): expected body %v, but got %v", "Hello, Camel", result.Body)
+	}
+}
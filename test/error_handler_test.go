@@ -0,0 +1,211 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRoute_ErrorHandlerRedelivery(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	var prepared, calls atomic.Int64
+	flaky, err := camel.NewRoute("flaky", "direct:flaky").
+		Func("", func(e *exchange.Exchange) {
+			if calls.Add(1) <= 2 {
+				e.SetError(errors.New("service unavailable"))
+			}
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_ErrorHandlerRedelivery(): failed to build route: %s", err)
+	}
+	route, err := camel.NewRoute("order", "direct:order").
+		ErrorHandler(routestep.ErrorHandler{Redelivery: routestep.RedeliveryPolicy{
+			MaximumRedeliveries: 3,
+			RedeliveryDelay:     1,
+			BackOffMultiplier:   2,
+			RetryOn:             []errs.Matcher{errs.Contains("unavailable")},
+		}}).
+		Func("", func(e *exchange.Exchange) {
+			prepared.Add(1)
+		}).
+		To("", "direct:flaky").
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_ErrorHandlerRedelivery(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(flaky)
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRoute_ErrorHandlerRedelivery(): failed to start camel runtime: %s", err)
+	}
+
+	e, err := testCamelRuntime.Send(context.TODO(), "direct:order", nil, nil)
+	if err != nil {
+		t.Fatalf("TestRoute_ErrorHandlerRedelivery(): unexpected error: %s", err)
+	}
+	if calls.Load() != 3 || prepared.Load() != 1 {
+		t.Fatalf("TestRoute_ErrorHandlerRedelivery(): expected 3 deliveries of the failed step and 1 of the previous step, but got %d and %d",
+			calls.Load(), prepared.Load())
+	}
+	if counter, _ := e.Message().Header(exchange.CamelHeaderRedeliveryCounter); counter != 2 {
+		t.Fatalf("TestRoute_ErrorHandlerRedelivery(): expected redelivery counter %d, but got %v", 2, counter)
+	}
+}
+
+func TestRoute_ErrorHandlerScope(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	var routeCalls, scopeCalls atomic.Int64
+	route, err := camel.NewRoute("scope", "direct:scope").
+		ErrorHandler(routestep.ErrorHandler{Redelivery: routestep.RedeliveryPolicy{MaximumRedeliveries: 1}}).
+		Func("", func(e *exchange.Exchange) {
+			if routeCalls.Add(1) == 1 {
+				e.SetError(errors.New("route step failed"))
+			}
+		}).
+		ErrorHandlerScope("", routestep.ErrorHandler{Redelivery: routestep.RedeliveryPolicy{MaximumRedeliveries: 4}}, func(b *camel.RouteBuilder) {
+			b.Func("", func(e *exchange.Exchange) {
+				scopeCalls.Add(1)
+				e.SetError(errors.New("scope step failed"))
+			})
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_ErrorHandlerScope(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRoute_ErrorHandlerScope(): failed to start camel runtime: %s", err)
+	}
+
+	_, err = testCamelRuntime.Send(context.TODO(), "direct:scope", nil, nil)
+	if err == nil || err.Error() != "scope step failed" {
+		t.Fatalf("TestRoute_ErrorHandlerScope(): expected error 'scope step failed', but got %v", err)
+	}
+	if routeCalls.Load() != 2 || scopeCalls.Load() != 5 {
+		t.Fatalf("TestRoute_ErrorHandlerScope(): expected 2 route step and 5 scope step deliveries, but got %d and %d",
+			routeCalls.Load(), scopeCalls.Load())
+	}
+}
+
+func TestRoute_DeadLetterChannel(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	var deadLetters []*exchange.Message
+	var skipped atomic.Int64
+	dlq, err := camel.NewRoute("dlq", "direct:dlq").
+		Func("", func(e *exchange.Exchange) {
+			deadLetters = append(deadLetters, e.Message().Copy())
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_DeadLetterChannel(): failed to build route: %s", err)
+	}
+	errorHandler := routestep.DeadLetterChannel("direct:dlq")
+	errorHandler.Redelivery.MaximumRedeliveries = 2
+	errorHandler.UseOriginalMessage = true
+	route, err := camel.NewRoute("order", "direct:order").
+		ErrorHandler(errorHandler).
+		SetBody("", expr.Constant("modified")).
+		SetError("fail", errors.New("service unavailable")).
+		Func("", func(e *exchange.Exchange) {
+			skipped.Add(1)
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_DeadLetterChannel(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(dlq)
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRoute_DeadLetterChannel(): failed to start camel runtime: %s", err)
+	}
+
+	e, err := testCamelRuntime.Send(context.TODO(), "direct:order", "original", nil)
+	if err != nil {
+		t.Fatalf("TestRoute_DeadLetterChannel(): expected handled error, but got: %s", err)
+	}
+	if handled, _ := e.Property(exchange.CamelPropertyErrorHandled); handled != true {
+		t.Fatalf("TestRoute_DeadLetterChannel(): expected exchange marked handled")
+	}
+	if skipped.Load() != 0 {
+		t.Fatalf("TestRoute_DeadLetterChannel(): expected the rest of the route to be skipped")
+	}
+	if len(deadLetters) != 1 {
+		t.Fatalf("TestRoute_DeadLetterChannel(): expected 1 dead letter, but got %d", len(deadLetters))
+	}
+	deadLetter := deadLetters[0]
+	if deadLetter.Body != "original" {
+		t.Fatalf("TestRoute_DeadLetterChannel(): expected original body, but got %v", deadLetter.Body)
+	}
+	for header, want := range map[string]any{
+		exchange.CamelHeaderFailureRoute: "order",
+		exchange.CamelHeaderFailureStep:  "fail",
+		exchange.CamelHeaderFailureError: "service unavailable",
+	} {
+		if got, _ := deadLetter.Header(header); got != want {
+			t.Fatalf("TestRoute_DeadLetterChannel(): expected header %s=%v, but got %v", header, want, got)
+		}
+	}
+	if !deadLetter.HasHeader(exchange.CamelHeaderFailureTime) {
+		t.Fatalf("TestRoute_DeadLetterChannel(): expected header %s", exchange.CamelHeaderFailureTime)
+	}
+}
+
+func TestRuntime_ErrorHandler(t *testing.T) {
+	errorHandler := routestep.DeadLetterChannel("direct:dlq")
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime", ErrorHandler: &errorHandler})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	var deadLetters atomic.Int64
+	dlq, err := camel.NewRoute("dlq", "direct:dlq").
+		Func("", func(e *exchange.Exchange) {
+			deadLetters.Add(1)
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRuntime_ErrorHandler(): failed to build route: %s", err)
+	}
+	route, err := camel.NewRoute("order", "direct:order").
+		SetError("", errors.New("failed")).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRuntime_ErrorHandler(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(dlq)
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRuntime_ErrorHandler(): failed to start camel runtime: %s", err)
+	}
+
+	if _, err := testCamelRuntime.Send(context.TODO(), "direct:order", nil, nil); err != nil {
+		t.Fatalf("TestRuntime_ErrorHandler(): expected handled error, but got: %s", err)
+	}
+	if deadLetters.Load() != 1 {
+		t.Fatalf("TestRuntime_ErrorHandler(): expected 1 dead letter, but got %d", deadLetters.Load())
+	}
+}
@@ -0,0 +1,175 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/seda"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/executor"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// concurrencyProbe records the max number of exchanges processed at the same time.
+type concurrencyProbe struct {
+	running    atomic.Int64
+	maxRunning atomic.Int64
+}
+
+func (p *concurrencyProbe) process(_ *exchange.Exchange) {
+	n := p.running.Add(1)
+	for {
+		current := p.maxRunning.Load()
+		if n <= current || p.maxRunning.CompareAndSwap(current, n) {
+			break
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	p.running.Add(-1)
+}
+
+func TestMulticast_ExecutorService(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+	testCamelRuntime.MustRegisterExecutorProfile(executor.Profile{
+		Name:            "fragile",
+		MaxConcurrency:  2,
+		QueueSize:       10,
+		RejectionPolicy: executor.RejectBlock,
+	})
+
+	defer testCamelRuntime.Stop()
+
+	probe := &concurrencyProbe{}
+	mb := camel.NewRoute("fanOut", "direct:fanOut").
+		Multicast("").
+		ParallelProcessing().
+		ExecutorService("fragile")
+	for i := 0; i < 6; i++ {
+		mb.Process(func(b *camel.RouteBuilder) {
+			b.Func("", probe.process)
+		})
+	}
+	route, err := mb.EndMulticast().Build()
+	if err != nil {
+		t.Fatalf("TestMulticast_ExecutorService(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestMulticast_ExecutorService(): failed to start camel runtime: %s", err)
+	}
+
+	if _, err := testCamelRuntime.SendBody(context.TODO(), "direct:fanOut", nil); err != nil {
+		t.Fatalf("TestMulticast_ExecutorService(): failed to call route: %s", err)
+	}
+	if probe.maxRunning.Load() != 2 {
+		t.Fatalf("TestMulticast_ExecutorService(): expected max %d concurrent outputs, but got %d", 2, probe.maxRunning.Load())
+	}
+}
+
+func TestRoute_Threads(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(seda.NewComponent())
+	testCamelRuntime.MustRegisterExecutorProfile(executor.Profile{
+		Name:            "single",
+		MaxConcurrency:  1,
+		QueueSize:       10,
+		RejectionPolicy: executor.RejectAbort,
+	})
+
+	defer testCamelRuntime.Stop()
+
+	probe := &concurrencyProbe{}
+	route, err := camel.NewRoute("capped", "seda:capped?concurrentConsumers=4").
+		Threads("", "single").
+		Func("", probe.process).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_Threads(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRoute_Threads(): failed to start camel runtime: %s", err)
+	}
+
+	pt := testCamelRuntime.NewProducerTemplate()
+	futures := make([]*camel.Future, 4)
+	for i := range futures {
+		futures[i] = pt.AsyncRequest(context.TODO(), "seda:capped?concurrentConsumers=4", i, nil)
+	}
+	for _, f := range futures {
+		if _, err := f.Get(context.TODO()); err != nil {
+			t.Fatalf("TestRoute_Threads(): failed to request: %s", err)
+		}
+	}
+	if probe.maxRunning.Load() != 1 {
+		t.Fatalf("TestRoute_Threads(): expected max %d concurrent exchanges, but got %d", 1, probe.maxRunning.Load())
+	}
+}
+
+func TestSeda_ExecutorService(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(seda.NewComponent())
+	testCamelRuntime.MustRegisterExecutorProfile(executor.Profile{
+		Name:            "pair",
+		MaxConcurrency:  2,
+		QueueSize:       10,
+		RejectionPolicy: executor.RejectBlock,
+	})
+
+	defer testCamelRuntime.Stop()
+
+	probe := &concurrencyProbe{}
+	route, err := camel.NewRoute("pooled", "seda:pooled?executorService=pair").
+		Func("", probe.process).
+		Build()
+	if err != nil {
+		t.Fatalf("TestSeda_ExecutorService(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestSeda_ExecutorService(): failed to start camel runtime: %s", err)
+	}
+
+	pt := testCamelRuntime.NewProducerTemplate()
+	futures := make([]*camel.Future, 6)
+	for i := range futures {
+		futures[i] = pt.AsyncRequest(context.TODO(), "seda:pooled?executorService=pair", i, nil)
+	}
+	for _, f := range futures {
+		if _, err := f.Get(context.TODO()); err != nil {
+			t.Fatalf("TestSeda_ExecutorService(): failed to request: %s", err)
+		}
+	}
+	if probe.maxRunning.Load() != 2 {
+		t.Fatalf("TestSeda_ExecutorService(): expected max %d concurrent exchanges, but got %d", 2, probe.maxRunning.Load())
+	}
+}
+
+func TestRoute_ThreadsUnknownProfile(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	route, err := camel.NewRoute("unknownProfile", "direct:unknownProfile").
+		Threads("", "unknown").
+		SetBody("", expr.Constant("ok")).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_ThreadsUnknownProfile(): failed to build route: %s", err)
+	}
+
+	var validationErr *camel.RouteValidationError
+	if err := testCamelRuntime.ValidateRoute(route); !errors.As(err, &validationErr) {
+		t.Fatalf("TestRoute_ThreadsUnknownProfile(): expected RouteValidationError, but got %v", err)
+	}
+	if validationErr.Problems[0].Path != "unknownProfile/threads[0]" {
+		t.Fatalf("TestRoute_ThreadsUnknownProfile(): expected problem at %s, but got %s", "unknownProfile/threads[0]", validationErr.Problems[0].Path)
+	}
+}
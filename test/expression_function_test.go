@@ -0,0 +1,48 @@
+package test
+
+import (
+	"context"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/env"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"testing"
+)
+
+func TestRuntime_RegisterExpressionFunction(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{
+		Name: "CamelTestRuntime",
+		Env:  env.NewMapEnv(map[string]string{"CURRENCY": "EUR"}),
+	})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+	testCamelRuntime.MustRegisterExpressionFunction("discount", func(total int) int {
+		return total * 9 / 10
+	})
+
+	defer testCamelRuntime.Stop()
+
+	route, err := camel.NewRoute("order", "direct:order").
+		SetHeader("", "total", expr.Simple("discount(bodyAs('int'))")).
+		SetBody("", expr.Simple("toString(header.total) + ' ' + env('CURRENCY')")).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRuntime_RegisterExpressionFunction(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRuntime_RegisterExpressionFunction(): failed to start camel runtime: %s", err)
+	}
+
+	e, err := testCamelRuntime.Send(context.TODO(), "direct:order", "200", nil)
+	if err != nil {
+		t.Fatalf("TestRuntime_RegisterExpressionFunction(): unexpected error: %s", err)
+	}
+	if e.Message().Body != "180 EUR" {
+		t.Fatalf("TestRuntime_RegisterExpressionFunction(): expected body '180 EUR', got %v", e.Message().Body)
+	}
+
+	if err := testCamelRuntime.RegisterExpressionFunction("uuid", func() string { return "" }); err == nil {
+		t.Fatalf("TestRuntime_RegisterExpressionFunction(): expected error for the library function name")
+	}
+}
@@ -0,0 +1,191 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+	"testing"
+)
+
+func TestRoute_Intercept(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	var intercepted []string
+	route, err := camel.NewRoute("order", "direct:order").
+		Intercept(func(b *camel.RouteBuilder) {
+			b.Func("", func(e *exchange.Exchange) {
+				intercepted = append(intercepted, e.Message().Body.(string))
+			})
+		}).
+		SetBody("", expr.Constant("a")).
+		Choice("").
+		When(expr.Simple("body == 'a'"), func(b *camel.RouteBuilder) {
+			b.SetBody("", expr.Constant("b"))
+		}).
+		EndChoice().
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_Intercept(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRoute_Intercept(): failed to start camel runtime: %s", err)
+	}
+
+	e, err := testCamelRuntime.Send(context.TODO(), "direct:order", "", nil)
+	if err != nil {
+		t.Fatalf("TestRoute_Intercept(): unexpected error: %s", err)
+	}
+	if e.Message().Body != "b" {
+		t.Fatalf("TestRoute_Intercept(): expected body 'b', got %v", e.Message().Body)
+	}
+	// setBody, choice and the nested setBody are intercepted
+	want := []string{"", "a", "a"}
+	if len(intercepted) != len(want) || intercepted[0] != want[0] || intercepted[1] != want[1] || intercepted[2] != want[2] {
+		t.Fatalf("TestRoute_Intercept(): expected %v, got %v", want, intercepted)
+	}
+}
+
+func TestRoute_Intercept_Failed(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	route, err := camel.NewRoute("order", "direct:order").
+		Intercept(func(b *camel.RouteBuilder) {
+			b.SetError("", errors.New("access denied"))
+		}).
+		SetBody("", expr.Constant("processed")).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_Intercept_Failed(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRoute_Intercept_Failed(): failed to start camel runtime: %s", err)
+	}
+
+	e, err := testCamelRuntime.Send(context.TODO(), "direct:order", "", nil)
+	if err == nil || err.Error() != "access denied" {
+		t.Fatalf("TestRoute_Intercept_Failed(): expected error 'access denied', got %v", err)
+	}
+	if e.Message().Body != "" {
+		t.Fatalf("TestRoute_Intercept_Failed(): expected the intercepted step to be skipped, got body %v", e.Message().Body)
+	}
+}
+
+func TestRuntime_InterceptFrom(t *testing.T) {
+	interceptFrom, err := camel.NewInterceptFrom("direct:order*", func(b *camel.RouteBuilder) {
+		b.SetHeader("", "auth", expr.Constant("token"))
+	})
+	if err != nil {
+		t.Fatalf("TestRuntime_InterceptFrom(): failed to build interceptFrom: %s", err)
+	}
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{
+		Name:           "CamelTestRuntime",
+		InterceptFroms: []routestep.InterceptFrom{interceptFrom},
+	})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	for _, r := range [][2]string{{"order", "direct:order.new"}, {"invoice", "direct:invoice"}} {
+		route, err := camel.NewRoute(r[0], r[1]).
+			SetBody("", expr.Simple("header.auth")).
+			Build()
+		if err != nil {
+			t.Fatalf("TestRuntime_InterceptFrom(): failed to build route: %s", err)
+		}
+		testCamelRuntime.MustRegisterRoute(route)
+	}
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRuntime_InterceptFrom(): failed to start camel runtime: %s", err)
+	}
+
+	e, err := testCamelRuntime.Send(context.TODO(), "direct:order.new", "", nil)
+	if err != nil {
+		t.Fatalf("TestRuntime_InterceptFrom(): unexpected error: %s", err)
+	}
+	if e.Message().Body != "token" {
+		t.Fatalf("TestRuntime_InterceptFrom(): expected body 'token', got %v", e.Message().Body)
+	}
+	if uri, _ := e.Property(exchange.CamelPropertyInterceptedEndpoint); uri != "direct:order.new" {
+		t.Fatalf("TestRuntime_InterceptFrom(): expected intercepted endpoint 'direct:order.new', got %v", uri)
+	}
+
+	e, err = testCamelRuntime.Send(context.TODO(), "direct:invoice", "", nil)
+	if err != nil {
+		t.Fatalf("TestRuntime_InterceptFrom(): unexpected error: %s", err)
+	}
+	if e.Message().Body != nil {
+		t.Fatalf("TestRuntime_InterceptFrom(): expected the invoice route not to be intercepted, got body %v", e.Message().Body)
+	}
+}
+
+func TestRoute_InterceptSendToEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		skip     bool
+		wantBody string
+	}{
+		{name: "send to original endpoint", wantBody: "audited by token"},
+		{name: "skip send to original endpoint", skip: true, wantBody: "order"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+			testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+			defer testCamelRuntime.Stop()
+
+			b := camel.NewRoute("order", "direct:order")
+			intercept := b.InterceptSendToEndpoint("direct:audit.*", func(b *camel.RouteBuilder) {
+				b.SetHeader("", "auth", expr.Constant("token"))
+			})
+			if tt.skip {
+				intercept.SkipSendToOriginalEndpoint()
+			}
+			route, err := intercept.EndInterceptSendToEndpoint().
+				To("", "direct:audit.log").
+				Build()
+			if err != nil {
+				t.Fatalf("TestRoute_InterceptSendToEndpoint(): failed to build route: %s", err)
+			}
+			testCamelRuntime.MustRegisterRoute(route)
+
+			auditRoute, err := camel.NewRoute("audit", "direct:audit.log").
+				SetBody("", expr.Simple("'audited by ' + header.auth")).
+				Build()
+			if err != nil {
+				t.Fatalf("TestRoute_InterceptSendToEndpoint(): failed to build route: %s", err)
+			}
+			testCamelRuntime.MustRegisterRoute(auditRoute)
+
+			if err := testCamelRuntime.Start(); err != nil {
+				t.Fatalf("TestRoute_InterceptSendToEndpoint(): failed to start camel runtime: %s", err)
+			}
+
+			e, err := testCamelRuntime.Send(context.TODO(), "direct:order", "order", nil)
+			if err != nil {
+				t.Fatalf("TestRoute_InterceptSendToEndpoint(): unexpected error: %s", err)
+			}
+			if e.Message().Body != tt.wantBody {
+				t.Fatalf("TestRoute_InterceptSendToEndpoint(): expected body '%s', got %v", tt.wantBody, e.Message().Body)
+			}
+			if uri, _ := e.Property(exchange.CamelPropertyInterceptedEndpoint); uri != "direct:audit.log" {
+				t.Fatalf("TestRoute_InterceptSendToEndpoint(): expected intercepted endpoint 'direct:audit.log', got %v", uri)
+			}
+		})
+	}
+}
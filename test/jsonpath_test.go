@@ -0,0 +1,61 @@
+package test
+
+import (
+	"context"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"reflect"
+	"testing"
+)
+
+func TestJSONPath_Choice(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	route, err := camel.NewRoute("order", "direct:order").
+		Choice("bulk order").
+		When(expr.JSONPath("$.order.lines[?(@.qty > 1)]"), func(b *camel.RouteBuilder) {
+			b.SetHeader("", "kind", expr.Constant("bulk")).
+				SetBody("", expr.JSONPath("$.order.lines[?(@.qty > 1)].sku"))
+		}).
+		Otherwise(func(b *camel.RouteBuilder) {
+			b.SetHeader("", "kind", expr.Constant("single")).
+				SetBody("", expr.JSONPathWithOptions("$.order.coupon", expr.JSONPathOptions{SuppressExceptions: true}))
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("TestJSONPath_Choice(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestJSONPath_Choice(): failed to start camel runtime: %s", err)
+	}
+
+	e, err := testCamelRuntime.Send(context.TODO(), "direct:order",
+		[]byte(`{"order": {"lines": [{"sku": "A", "qty": 1}, {"sku": "B", "qty": 2}]}}`), nil)
+	if err != nil {
+		t.Fatalf("TestJSONPath_Choice(): unexpected error: %s", err)
+	}
+	if kind, _ := e.Message().Header("kind"); kind != "bulk" {
+		t.Fatalf("TestJSONPath_Choice(): expected header kind 'bulk', got %v", kind)
+	}
+	if !reflect.DeepEqual(e.Message().Body, []any{"B"}) {
+		t.Fatalf("TestJSONPath_Choice(): expected body [B], got %v", e.Message().Body)
+	}
+
+	e, err = testCamelRuntime.Send(context.TODO(), "direct:order",
+		map[string]any{"order": map[string]any{"lines": []map[string]any{{"sku": "A", "qty": 1}}}}, nil)
+	if err != nil {
+		t.Fatalf("TestJSONPath_Choice(): unexpected error: %s", err)
+	}
+	if kind, _ := e.Message().Header("kind"); kind != "single" {
+		t.Fatalf("TestJSONPath_Choice(): expected header kind 'single', got %v", kind)
+	}
+	if e.Message().Body != nil {
+		t.Fatalf("TestJSONPath_Choice(): expected nil body, got %v", e.Message().Body)
+	}
+}
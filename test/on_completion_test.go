@@ -0,0 +1,161 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+	"testing"
+	"time"
+)
+
+func TestRoute_OnCompletion(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    routestep.OnCompletionMode
+		fail    bool
+		wantRun bool
+	}{
+		{name: "always on success", mode: "", wantRun: true},
+		{name: "always on failure", mode: routestep.OnCompletionAlways, fail: true, wantRun: true},
+		{name: "complete only on success", mode: routestep.OnCompleteOnly, wantRun: true},
+		{name: "complete only on failure", mode: routestep.OnCompleteOnly, fail: true},
+		{name: "failure only on success", mode: routestep.OnFailureOnly},
+		{name: "failure only on failure", mode: routestep.OnFailureOnly, fail: true, wantRun: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+			testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+			defer testCamelRuntime.Stop()
+
+			var completed *exchange.Exchange
+			route, err := camel.NewRoute("order", "direct:order").
+				OnCompletion(tt.mode, func(b *camel.RouteBuilder) {
+					b.Func("", func(e *exchange.Exchange) {
+						completed = e
+						e.Message().Body = "changed by onCompletion"
+					})
+				}).EndOnCompletion().
+				SetBody("", expr.Constant("processed")).
+				Func("", func(e *exchange.Exchange) {
+					if tt.fail {
+						e.SetError(errors.New("failed"))
+					}
+				}).
+				Build()
+			if err != nil {
+				t.Fatalf("TestRoute_OnCompletion(): failed to build route: %s", err)
+			}
+			testCamelRuntime.MustRegisterRoute(route)
+
+			if err := testCamelRuntime.Start(); err != nil {
+				t.Fatalf("TestRoute_OnCompletion(): failed to start camel runtime: %s", err)
+			}
+
+			e, _ := testCamelRuntime.Send(context.TODO(), "direct:order", "", nil)
+			if (completed != nil) != tt.wantRun {
+				t.Fatalf("TestRoute_OnCompletion(): onCompletion processed = %v, want %v", completed != nil, tt.wantRun)
+			}
+			if e.Message().Body != "processed" {
+				t.Fatalf("TestRoute_OnCompletion(): expected body 'processed', got %v", e.Message().Body)
+			}
+			if completed != nil && completed.Id() == e.Id() {
+				t.Fatalf("TestRoute_OnCompletion(): expected onCompletion to process a copy of the exchange")
+			}
+		})
+	}
+}
+
+func TestRoute_OnCompletion_OnWhenParallel(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	completed := make(chan any, 2)
+	route, err := camel.NewRoute("order", "direct:order").
+		OnCompletion(routestep.OnCompletionAlways, func(b *camel.RouteBuilder) {
+			b.Func("", func(e *exchange.Exchange) {
+				completed <- e.Message().Body
+			})
+		}).OnWhen(expr.Simple("body == 'vip'")).ParallelProcessing().EndOnCompletion().
+		LogInfo("", "${body}").
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_OnCompletion_OnWhenParallel(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRoute_OnCompletion_OnWhenParallel(): failed to start camel runtime: %s", err)
+	}
+
+	for _, body := range []string{"regular", "vip"} {
+		if _, err := testCamelRuntime.Send(context.TODO(), "direct:order", body, nil); err != nil {
+			t.Fatalf("TestRoute_OnCompletion_OnWhenParallel(): unexpected error: %s", err)
+		}
+	}
+
+	select {
+	case body := <-completed:
+		if body != "vip" {
+			t.Fatalf("TestRoute_OnCompletion_OnWhenParallel(): expected body 'vip', got %v", body)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("TestRoute_OnCompletion_OnWhenParallel(): onCompletion is not processed")
+	}
+	select {
+	case body := <-completed:
+		t.Fatalf("TestRoute_OnCompletion_OnWhenParallel(): unexpected onCompletion for %v", body)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestExchange_AddOnCompletion(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	var outcomes []string
+	orderRoute, err := camel.NewRoute("order", "direct:order").
+		To("", "direct:store").
+		SetError("", errors.New("failed")).
+		Build()
+	if err != nil {
+		t.Fatalf("TestExchange_AddOnCompletion(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(orderRoute)
+	storeRoute, err := camel.NewRoute("store", "direct:store").
+		Func("", func(e *exchange.Exchange) {
+			// The callback is called once the first route is done with the exchange
+			e.AddOnCompletion(func(e *exchange.Exchange) {
+				if e.IsError() {
+					outcomes = append(outcomes, "rollback")
+				} else {
+					outcomes = append(outcomes, "commit")
+				}
+			})
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("TestExchange_AddOnCompletion(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(storeRoute)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestExchange_AddOnCompletion(): failed to start camel runtime: %s", err)
+	}
+
+	if _, err := testCamelRuntime.Send(context.TODO(), "direct:order", "", nil); err == nil {
+		t.Fatalf("TestExchange_AddOnCompletion(): expected error")
+	}
+	if len(outcomes) != 1 || outcomes[0] != "rollback" {
+		t.Fatalf("TestExchange_AddOnCompletion(): expected [rollback], got %v", outcomes)
+	}
+}
@@ -0,0 +1,255 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+	"testing"
+)
+
+func TestRoute_OnException(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(b *camel.OnExceptionBuilder)
+		wantBody  string
+		wantError bool
+	}{
+		{name: "handled", configure: func(b *camel.OnExceptionBuilder) { b.Handled(true) }, wantBody: "handled"},
+		{name: "continued", configure: func(b *camel.OnExceptionBuilder) { b.Continued(true) }, wantBody: "handled;next"},
+		{name: "rethrown", configure: func(b *camel.OnExceptionBuilder) {}, wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+			testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+			defer testCamelRuntime.Stop()
+
+			b := camel.NewRoute("order", "direct:order")
+			b.OnException(errs.Any(), func(b *camel.RouteBuilder) {
+				b.SetBody("", expr.Constant("any"))
+			}).Handled(true)
+			onException := b.OnException(errs.Contains("refused"), func(b *camel.RouteBuilder) {
+				b.SetBody("", expr.Constant("handled"))
+			})
+			tt.configure(onException)
+			route, err := onException.EndOnException().
+				SetError("", errors.New("connection refused")).
+				Func("", func(e *exchange.Exchange) {
+					e.Message().Body = e.Message().Body.(string) + ";next"
+				}).
+				Build()
+			if err != nil {
+				t.Fatalf("TestRoute_OnException(): failed to build route: %s", err)
+			}
+			testCamelRuntime.MustRegisterRoute(route)
+
+			if err := testCamelRuntime.Start(); err != nil {
+				t.Fatalf("TestRoute_OnException(): failed to start camel runtime: %s", err)
+			}
+
+			e, err := testCamelRuntime.Send(context.TODO(), "direct:order", "", nil)
+			if tt.wantError {
+				if err == nil {
+					t.Fatalf("TestRoute_OnException(): expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TestRoute_OnException(): unexpected error: %s", err)
+			}
+			if e.Message().Body != tt.wantBody {
+				t.Fatalf("TestRoute_OnException(): expected body %q, but got %v", tt.wantBody, e.Message().Body)
+			}
+		})
+	}
+}
+
+func TestRoute_OnExceptionTryCatch(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	route, err := camel.NewRoute("order", "direct:order").
+		OnException(errs.Any(), func(b *camel.RouteBuilder) {
+			b.SetBody("", expr.Constant("onException"))
+		}).Handled(true).EndOnException().
+		Try("", func(b *camel.RouteBuilder) {
+			b.SetError("", errors.New("caught"))
+		}).
+		Catch(errs.Equals("caught"), func(b *camel.RouteBuilder) {
+			b.SetBody("", expr.Constant("catch"))
+		}).
+		EndTry().
+		Try("", func(b *camel.RouteBuilder) {
+			b.SetError("", errors.New("not caught"))
+		}).
+		Catch(errs.Equals("caught"), func(b *camel.RouteBuilder) {
+			b.SetBody("", expr.Constant("catch"))
+		}).
+		EndTry().
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_OnExceptionTryCatch(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRoute_OnExceptionTryCatch(): failed to start camel runtime: %s", err)
+	}
+
+	e, err := testCamelRuntime.Send(context.TODO(), "direct:order", nil, nil)
+	if err != nil {
+		t.Fatalf("TestRoute_OnExceptionTryCatch(): unexpected error: %s", err)
+	}
+	if e.Message().Body != "onException" {
+		t.Fatalf("TestRoute_OnExceptionTryCatch(): expected body %q, but got %v", "onException", e.Message().Body)
+	}
+}
+
+func TestRuntime_OnException(t *testing.T) {
+	runtimeOnException, err := camel.NewOnException(errs.Contains("refused"), func(b *camel.RouteBuilder) {
+		b.SetBody("", expr.Constant("runtime"))
+	}).Handled(true).Build()
+	if err != nil {
+		t.Fatalf("TestRuntime_OnException(): failed to build onException: %s", err)
+	}
+
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{
+		Name:         "CamelTestRuntime",
+		OnExceptions: []routestep.OnException{runtimeOnException},
+	})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	plain, err := camel.NewRoute("plain", "direct:plain").
+		SetError("", errors.New("connection refused")).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRuntime_OnException(): failed to build route: %s", err)
+	}
+	// The route clause with the same specificity goes before the runtime one
+	own, err := camel.NewRoute("own", "direct:own").
+		OnException(errs.Contains("connection"), func(b *camel.RouteBuilder) {
+			b.SetBody("", expr.Constant("route"))
+		}).Handled(true).EndOnException().
+		SetError("", errors.New("connection refused")).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRuntime_OnException(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(plain)
+	testCamelRuntime.MustRegisterRoute(own)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRuntime_OnException(): failed to start camel runtime: %s", err)
+	}
+
+	for uri, want := range map[string]string{"direct:plain": "runtime", "direct:own": "route"} {
+		e, err := testCamelRuntime.Send(context.TODO(), uri, nil, nil)
+		if err != nil {
+			t.Fatalf("TestRuntime_OnException(): %s: unexpected error: %s", uri, err)
+		}
+		if e.Message().Body != want {
+			t.Fatalf("TestRuntime_OnException(): %s: expected body %q, but got %v", uri, want, e.Message().Body)
+		}
+	}
+}
+
+func TestRoute_OnException_CompositeStep(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(b *camel.RouteBuilder)
+	}{
+		{name: "choice predicate", configure: func(b *camel.RouteBuilder) {
+			b.Choice("").
+				When(expr.Func(func(e *exchange.Exchange) (any, error) {
+					return nil, errors.New("predicate boom")
+				}), func(b *camel.RouteBuilder) {
+					b.SetBody("", expr.Constant("when"))
+				}).
+				EndChoice()
+		}},
+		{name: "multicast timeout", configure: func(b *camel.RouteBuilder) {
+			b.Multicast("").ParallelProcessing().Timeout(20).
+				Process(func(b *camel.RouteBuilder) {
+					b.Delay("", 200)
+				}).
+				EndMulticast()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+			testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+			defer testCamelRuntime.Stop()
+
+			b := camel.NewRoute("order", "direct:order").
+				OnException(errs.Any(), func(b *camel.RouteBuilder) {
+					b.SetBody("", expr.Constant("handled"))
+				}).Handled(true).EndOnException()
+			tt.configure(b)
+			route, err := b.Build()
+			if err != nil {
+				t.Fatalf("TestRoute_OnException_CompositeStep(): failed to build route: %s", err)
+			}
+			testCamelRuntime.MustRegisterRoute(route)
+
+			if err := testCamelRuntime.Start(); err != nil {
+				t.Fatalf("TestRoute_OnException_CompositeStep(): failed to start camel runtime: %s", err)
+			}
+
+			e, err := testCamelRuntime.Send(context.TODO(), "direct:order", "", nil)
+			if err != nil {
+				t.Fatalf("TestRoute_OnException_CompositeStep(): unexpected error: %s", err)
+			}
+			if e.Message().Body != "handled" {
+				t.Fatalf("TestRoute_OnException_CompositeStep(): expected body 'handled', but got %v", e.Message().Body)
+			}
+		})
+	}
+}
+
+func TestRoute_OnException_NestedStepHandledOnce(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	var handled int
+	route, err := camel.NewRoute("order", "direct:order").
+		OnException(errs.Any(), func(b *camel.RouteBuilder) {
+			b.Func("", func(e *exchange.Exchange) {
+				handled++
+			})
+		}).EndOnException().
+		Choice("").
+		When(expr.Constant(true), func(b *camel.RouteBuilder) {
+			b.SetError("", errors.New("nested boom"))
+		}).
+		EndChoice().
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_OnException_NestedStepHandledOnce(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRoute_OnException_NestedStepHandledOnce(): failed to start camel runtime: %s", err)
+	}
+
+	if _, err := testCamelRuntime.Send(context.TODO(), "direct:order", "", nil); err == nil {
+		t.Fatalf("TestRoute_OnException_NestedStepHandledOnce(): expected error")
+	}
+	if handled != 1 {
+		t.Fatalf("TestRoute_OnException_NestedStepHandledOnce(): expected onException handler to run once, but got %d", handled)
+	}
+}
@@ -0,0 +1,113 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/seda"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/timer"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/policy"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRoute_SuspendSharedConsumer(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(timer.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	var firstTicks, secondTicks atomic.Int64
+	for name, ticks := range map[string]*atomic.Int64{"first": &firstTicks, "second": &secondTicks} {
+		route, err := camel.NewRoute(name, "timer:tick?interval=5ms").
+			Func("", func(e *exchange.Exchange) {
+				ticks.Add(1)
+			}).
+			Build()
+		if err != nil {
+			t.Fatalf("TestRoute_SuspendSharedConsumer(): failed to build route: %s", err)
+		}
+		testCamelRuntime.MustRegisterRoute(route)
+	}
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRoute_SuspendSharedConsumer(): failed to start camel runtime: %s", err)
+	}
+
+	if err := testCamelRuntime.Route("first").Suspend(); err != nil {
+		t.Fatalf("TestRoute_SuspendSharedConsumer(): failed to suspend route: %s", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	first, second := firstTicks.Load(), secondTicks.Load()
+	time.Sleep(50 * time.Millisecond)
+
+	if firstTicks.Load() != first {
+		t.Fatalf("TestRoute_SuspendSharedConsumer(): suspended route keeps consuming")
+	}
+	if secondTicks.Load() == second {
+		t.Fatalf("TestRoute_SuspendSharedConsumer(): route sharing the consumer with the suspended one stopped consuming")
+	}
+
+	if err := testCamelRuntime.Route("first").Resume(); err != nil {
+		t.Fatalf("TestRoute_SuspendSharedConsumer(): failed to resume route: %s", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if firstTicks.Load() == first {
+		t.Fatalf("TestRoute_SuspendSharedConsumer(): resumed route does not consume")
+	}
+}
+
+func TestRoute_ThrottledSedaRoute(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(seda.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	route, err := camel.NewRoute("throttled", "seda:throttled?concurrentConsumers=4").
+		RoutePolicy(policy.NewThrottlingInflight(1, 1)).
+		Delay("", 2).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_ThrottledSedaRoute(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRoute_ThrottledSedaRoute(): failed to start camel runtime: %s", err)
+	}
+
+	pt := testCamelRuntime.NewProducerTemplate()
+	request := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_, err := pt.RequestBody(ctx, "seda:throttled?concurrentConsumers=4", "order")
+		return err
+	}
+
+	// The exchanges taken by the workers while the route is suspended are rejected, the others must be processed
+	for round := 0; round < 10; round++ {
+		var wg sync.WaitGroup
+		errs := make(chan error, 8)
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := request(); err != nil && !errors.Is(err, camel.ErrRouteSuspended) {
+					errs <- err
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Fatalf("TestRoute_ThrottledSedaRoute(): round %d: unexpected error: %s", round, err)
+		}
+	}
+
+	if err := request(); err != nil {
+		t.Fatalf("TestRoute_ThrottledSedaRoute(): expected the route to be resumed, but got error: %s", err)
+	}
+}
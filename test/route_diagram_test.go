@@ -0,0 +1,144 @@
+package test
+
+import (
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"strings"
+	"testing"
+)
+
+func diagramRoutes(t *testing.T) []*camel.Route {
+	order, err := camel.NewRoute("order", "direct:order").
+		Choice("big order?").
+		When(expr.Simple("body.total > 100"), func(b *camel.RouteBuilder) {
+			b.To("to billing", "direct:billing")
+		}).
+		Otherwise(func(b *camel.RouteBuilder) {
+			b.LogInfo("small", "small order")
+		}).
+		Try("notify", func(b *camel.RouteBuilder) {
+			b.SetHeader("set channel", "channel", expr.Constant("email"))
+		}).
+		Catch(errs.Any(), func(b *camel.RouteBuilder) {
+			b.LogError("notify failed", "${error}")
+		}).
+		EndTry().
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build 'order' route: %s", err)
+	}
+
+	billing, err := camel.NewRoute("billing", "direct:billing").
+		SetBody("invoice", expr.Constant("invoice")).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build 'billing' route: %s", err)
+	}
+
+	return []*camel.Route{order, billing}
+}
+
+func TestWalkRoute(t *testing.T) {
+	routes := diagramRoutes(t)
+
+	var visited []string
+	err := camel.WalkRoute(routes[0], func(step api.RouteStep, depth int) error {
+		visited = append(visited, strings.Repeat("-", depth)+step.StepName())
+		if step.StepName() == "notify" {
+			return camel.SkipSteps
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("TestWalkRoute(): %s", err)
+	}
+
+	want := "big order?;-to billing;-small;notify"
+	if got := strings.Join(visited, ";"); got != want {
+		t.Fatalf("TestWalkRoute(): expected %s, but got %s", want, got)
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var b strings.Builder
+	err := camel.WriteDOT(&b, diagramRoutes(t)...)
+	if err != nil {
+		t.Fatalf("TestWriteDOT(): %s", err)
+	}
+	dot := b.String()
+
+	for _, want := range []string{
+		`digraph routes {`,
+		`label="order";`,
+		`n2 [label="big order?", shape=diamond, style=solid];`,
+		`n2 -> n3 [label="simple:body.total > 100"];`,
+		`n2 -> n4 [label="otherwise"];`,
+		`n5 -> n7 [label="catch equals:*", style=dashed];`,
+		`n3 -> n8 [label="direct", style=dashed];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("TestWriteDOT(): expected %q in:\n%s", want, dot)
+		}
+	}
+}
+
+func TestWriteDOT_PredicateLabels(t *testing.T) {
+	route, err := camel.NewRoute("order", "direct:order").
+		Choice("").
+		When(expr.JSONPath("$.order[?(@.express)]"), func(b *camel.RouteBuilder) {
+			b.To("", "direct:express")
+		}).
+		When(expr.Func(func(e *exchange.Exchange) (any, error) { return true, nil }), func(b *camel.RouteBuilder) {
+			b.To("", "direct:inline")
+		}).
+		When(expr.FuncRef("isBulk"), func(b *camel.RouteBuilder) {
+			b.To("", "direct:bulk")
+		}).
+		EndChoice().
+		Build()
+	if err != nil {
+		t.Fatalf("TestWriteDOT_PredicateLabels(): failed to build route: %s", err)
+	}
+
+	var b strings.Builder
+	if err := camel.WriteDOT(&b, route); err != nil {
+		t.Fatalf("TestWriteDOT_PredicateLabels(): %s", err)
+	}
+	dot := b.String()
+
+	for _, want := range []string{
+		`[label="jsonpath:$.order[?(@.express)]"];`,
+		`[label="func"];`,
+		`[label="func:isBulk"];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("TestWriteDOT_PredicateLabels(): expected %q in:\n%s", want, dot)
+		}
+	}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	var b strings.Builder
+	err := camel.WriteMermaid(&b, diagramRoutes(t)...)
+	if err != nil {
+		t.Fatalf("TestWriteMermaid(): %s", err)
+	}
+	mermaid := b.String()
+
+	for _, want := range []string{
+		`flowchart TD`,
+		`subgraph route0["order"]`,
+		`n1(["direct:order"])`,
+		`n2{"big order?"}`,
+		`n2 -->|"simple:body.total > 100"| n3`,
+		`n5 -.->|"catch equals:*"| n7`,
+		`n3 -.->|"direct"| n8`,
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("TestWriteMermaid(): expected %q in:\n%s", want, mermaid)
+		}
+	}
+}
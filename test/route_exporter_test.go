@@ -0,0 +1,165 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"github.com/paveldanilin/go-camel/pkg/camel/policy"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+	"testing"
+)
+
+func TestMarshalRoutes_RoundTrip(t *testing.T) {
+	route, err := camel.NewRoute("order", "direct:order").
+		ErrorHandler(routestep.ErrorHandler{Redelivery: routestep.RedeliveryPolicy{
+			MaximumRedeliveries: 3,
+			RedeliveryDelay:     100,
+			BackOffMultiplier:   2,
+			Jitter:              0.1,
+			RetryOn:             []errs.Matcher{errs.Contains("timeout")},
+		}}).
+		OnException(errs.Contains("refused"), func(b *camel.RouteBuilder) {
+			b.SetBody("", expr.Constant("refused")).To("", "direct:failed")
+		}).Handled(true).EndOnException().
+		OnException(errs.Any(), func(b *camel.RouteBuilder) {}).Continued(true).EndOnException().
+		OnCompletion(routestep.OnFailureOnly, func(b *camel.RouteBuilder) {
+			b.To("", "direct:audit")
+		}).OnWhen(expr.Simple("header.total > 0")).ParallelProcessing().EndOnCompletion().
+		Intercept(func(b *camel.RouteBuilder) {
+			b.LogDebug("", "intercepted ${body}")
+		}).
+		InterceptFrom("direct:*", func(b *camel.RouteBuilder) {
+			b.SetHeader("", "source", expr.Constant("direct"))
+		}).
+		InterceptSendToEndpoint("direct:fail.*", func(b *camel.RouteBuilder) {
+			b.SetHeader("", "auth", expr.Constant("token"))
+		}).SkipSendToOriginalEndpoint().EndInterceptSendToEndpoint().
+		SetHeader("", "total", expr.Simple("body.qty * body.price")).
+		Choice("check total").
+		When(expr.Simple("header.total > 100"), func(b *camel.RouteBuilder) {
+			b.SetProperty("", "discount", expr.Constant(0.1))
+		}).
+		When(expr.JSONPath("$.lines[?(@.qty > 1)]"), func(b *camel.RouteBuilder) {
+			b.SetProperty("", "skus", expr.JSONPathWithOptions("$.lines[*].sku", expr.JSONPathOptions{
+				SuppressExceptions: true,
+				Result:             expr.JSONPathResultList,
+			}))
+		}).
+		Otherwise(func(b *camel.RouteBuilder) {
+			b.LogDebug("", "no discount for ${header.total}")
+		}).
+		Try("send", func(b *camel.RouteBuilder) {
+			b.ErrorHandlerScope("", routestep.ErrorHandler{
+				Redelivery: routestep.RedeliveryPolicy{
+					MaximumRedeliveries:    -1,
+					MaximumRedeliveryDelay: 1000,
+					RetryWhile:             expr.Simple("header.CAMEL_REDELIVERY_COUNTER < 5"),
+				},
+				DeadLetterURI:      "direct:billingFailed",
+				UseOriginalMessage: true,
+			}, func(b *camel.RouteBuilder) {
+				b.To("", "direct:billing")
+			})
+		}).
+		Catch(errs.Contains("timeout"), func(b *camel.RouteBuilder) {
+			b.Delay("", 100).Func("", "retry")
+		}).
+		Catch(errs.AllOf(errs.Matcher{MatchMode: errs.MatchModeIs, Target: "declined"},
+			errs.Not(errs.AnyOf(errs.Matches("^fatal"), errs.Predicate(expr.Simple("header.total > 1000"))))),
+			func(b *camel.RouteBuilder) {
+				b.SetBody("", expr.Simple("exception.Error()"))
+			}).
+		Finally(func(b *camel.RouteBuilder) {
+			b.RemoveHeader("", "total")
+		}).
+		Saga("", func(b *camel.RouteBuilder) {
+			b.To("", "direct:reserve")
+		}).
+		Propagation(routestep.SagaPropagationRequiresNew).Timeout(30000).
+		Compensation("direct:cancel").Completion("direct:complete").
+		EndSaga().
+		Multicast("notify").ParallelProcessing().Timeout(500).AggregatorRef("collect").
+		Process(func(b *camel.RouteBuilder) {
+			b.Marshal("", "json").To("", "direct:audit")
+		}).
+		Process(func(b *camel.RouteBuilder) {
+			b.Pipeline("", true, func(b *camel.RouteBuilder) {
+				b.SetBody("", expr.FuncRef("summary")).SetError("", errors.New("failed"))
+			})
+		}).
+		EndMulticast().
+		Build()
+	if err != nil {
+		t.Fatalf("TestMarshalRoutes_RoundTrip(): failed to build route: %s", err)
+	}
+
+	for _, format := range []camel.DocumentFormat{camel.DocumentFormatJSON, camel.DocumentFormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			exported, err := camel.MarshalRoutes(format, route)
+			if err != nil {
+				t.Fatalf("TestMarshalRoutes_RoundTrip(): failed to export route: %s", err)
+			}
+
+			parsed, err := camel.ParseRoutes(bytes.NewReader(exported))
+			if err != nil {
+				t.Fatalf("TestMarshalRoutes_RoundTrip(): failed to parse exported route: %s\n%s", err, exported)
+			}
+
+			reExported, err := camel.MarshalRoutes(format, parsed...)
+			if err != nil {
+				t.Fatalf("TestMarshalRoutes_RoundTrip(): failed to export parsed route: %s", err)
+			}
+
+			if !bytes.Equal(exported, reExported) {
+				t.Fatalf("TestMarshalRoutes_RoundTrip(): documents differ:\n%s\n---\n%s", exported, reExported)
+			}
+		})
+	}
+}
+
+func TestMarshalRoutes_InlineFunc(t *testing.T) {
+	route, err := camel.NewRoute("inline", "direct:inline").
+		Choice("").
+		When(expr.Simple("true"), func(b *camel.RouteBuilder) {
+			b.Func("", func(e *exchange.Exchange) {})
+		}).
+		EndChoice().
+		Build()
+	if err != nil {
+		t.Fatalf("TestMarshalRoutes_InlineFunc(): failed to build route: %s", err)
+	}
+
+	_, err = camel.MarshalRoutes(camel.DocumentFormatYAML, route)
+
+	var definitionErr *camel.RouteDefinitionError
+	if !errors.As(err, &definitionErr) {
+		t.Fatalf("TestMarshalRoutes_InlineFunc(): expected RouteDefinitionError, but got %v", err)
+	}
+	wantPath := "routes[0].steps[0].choice.when[0].steps[0].fn.func"
+	if definitionErr.Path != wantPath {
+		t.Fatalf("TestMarshalRoutes_InlineFunc(): expected path %s, but got %s", wantPath, definitionErr.Path)
+	}
+}
+
+func TestMarshalRoutes_Policies(t *testing.T) {
+	route, err := camel.NewRoute("throttled", "direct:throttled").
+		RoutePolicy(policy.NewThrottlingInflight(10, 5)).
+		SetBody("", expr.Constant("ok")).
+		Build()
+	if err != nil {
+		t.Fatalf("TestMarshalRoutes_Policies(): failed to build route: %s", err)
+	}
+
+	_, err = camel.MarshalRoutes(camel.DocumentFormatYAML, route)
+
+	var definitionErr *camel.RouteDefinitionError
+	if !errors.As(err, &definitionErr) {
+		t.Fatalf("TestMarshalRoutes_Policies(): expected RouteDefinitionError, but got %v", err)
+	}
+	if wantPath := "routes[0].policies"; definitionErr.Path != wantPath {
+		t.Fatalf("TestMarshalRoutes_Policies(): expected path %s, but got %s", wantPath, definitionErr.Path)
+	}
+}
@@ -0,0 +1,165 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"strings"
+	"testing"
+)
+
+const sumRoutesYAML = `
+routes:
+  - name: sum
+    from: direct:sum
+    steps:
+      - setBody:
+          name: calc sum
+          value: {simple: "header.a + header.b"}
+      - choice:
+          when:
+            - predicate: {simple: "body > 10"}
+              steps:
+                - fn: {func: x10}
+          otherwise:
+            - setHeader:
+                header: small
+                value: {constant: true}
+`
+
+const sumRoutesJSON = `{
+  "routes": [{
+    "name": "sum",
+    "from": "direct:sum",
+    "steps": [
+      {"setBody": {"name": "calc sum", "value": {"simple": "header.a + header.b"}}},
+      {"choice": {
+        "when": [{"predicate": {"simple": "body > 10"}, "steps": [{"fn": {"func": "x10"}}]}],
+        "otherwise": [{"setHeader": {"header": "small", "value": {"constant": true}}}]
+      }}
+    ]
+  }]
+}`
+
+func TestRuntime_LoadRoutes(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+	}{
+		{name: "YAML", document: sumRoutesYAML},
+		{name: "JSON", document: sumRoutesJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+			testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+			testCamelRuntime.MustRegisterFunc("x10", func(e *exchange.Exchange) {
+				e.Message().Body = e.Message().Body.(int) * 10
+			})
+
+			defer testCamelRuntime.Stop()
+
+			err := testCamelRuntime.LoadRoutes(strings.NewReader(tt.document))
+			if err != nil {
+				t.Fatalf("TestRuntime_LoadRoutes(): failed to load routes: %s", err)
+			}
+
+			err = testCamelRuntime.Start()
+			if err != nil {
+				t.Fatalf("TestRuntime_LoadRoutes(): failed to start camel runtime: %s", err)
+			}
+
+			result, err := testCamelRuntime.SendHeaders(context.TODO(), "direct:sum", exchange.Map{"a": 5, "b": 6})
+			if err != nil {
+				t.Fatalf("TestRuntime_LoadRoutes(): failed to call route: %s", err)
+			}
+			if result.Body != 110 {
+				t.Fatalf("TestRuntime_LoadRoutes(): expected result %v, but got %v", 110, result.Body)
+			}
+
+			result, err = testCamelRuntime.SendHeaders(context.TODO(), "direct:sum", exchange.Map{"a": 1, "b": 2})
+			if err != nil {
+				t.Fatalf("TestRuntime_LoadRoutes(): failed to call route: %s", err)
+			}
+			if !result.HasHeader("small") {
+				t.Fatalf("TestRuntime_LoadRoutes(): expected header 'small'")
+			}
+		})
+	}
+}
+
+func TestRuntime_LoadRoutes_Atomic(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	err := testCamelRuntime.LoadRoutes(strings.NewReader(`
+routes:
+  - name: valid
+    from: direct:valid
+    steps:
+      - setBody: {value: {constant: 1}}
+  - name: invalid
+    from: direct:invalid
+    steps:
+      - fn: {func: missing}
+`))
+	if err == nil {
+		t.Fatalf("TestRuntime_LoadRoutes_Atomic(): expected error for unknown func")
+	}
+	if routes := testCamelRuntime.RouteDefinitions(); len(routes) != 0 {
+		t.Fatalf("TestRuntime_LoadRoutes_Atomic(): expected no registered routes, but got %d", len(routes))
+	}
+}
+
+func TestParseRoutes_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		wantPath string
+	}{
+		{
+			name:     "unknown step",
+			document: `{"routes": [{"name": "r", "from": "direct:r", "steps": [{"setBody": {"value": {"constant": 1}}}, {"jump": {}}]}]}`,
+			wantPath: "routes[0].steps[1].jump",
+		},
+		{
+			name:     "missing uri",
+			document: `{"routes": [{"name": "r", "from": "direct:r", "steps": [{"choice": {"when": [{"predicate": {"simple": "true"}, "steps": [{"to": {}}]}]}}]}]}`,
+			wantPath: "routes[0].steps[0].choice.when[0].steps[0].to.uri",
+		},
+		{
+			name:     "unknown property",
+			document: "routes:\n  - name: r\n    from: direct:r\n    steps:\n      - log: {message: hi, lvl: info}\n",
+			wantPath: "routes[0].steps[0].log.lvl",
+		},
+		{
+			name:     "invalid expression",
+			document: "routes:\n  - name: r\n    from: direct:r\n    steps:\n      - setBody: {value: {simple: 1}}\n",
+			wantPath: "routes[0].steps[0].setBody.value.simple",
+		},
+		{
+			name:     "invalid jsonpath options",
+			document: "routes:\n  - name: r\n    from: direct:r\n    steps:\n      - setBody: {value: {jsonpath: {path: $.id, single: true}}}\n",
+			wantPath: "routes[0].steps[0].setBody.value.jsonpath.single",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := camel.ParseRoutes(strings.NewReader(tt.document))
+
+			var definitionErr *camel.RouteDefinitionError
+			if !errors.As(err, &definitionErr) {
+				t.Fatalf("TestParseRoutes_Error(): expected RouteDefinitionError, but got %v", err)
+			}
+			if definitionErr.Path != tt.wantPath {
+				t.Fatalf("TestParseRoutes_Error(): expected path %s, but got %s (%s)", tt.wantPath, definitionErr.Path, definitionErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const greetRouteYAML = `
+routes:
+  - name: greet
+    from: direct:greet
+    steps:
+      - setBody:
+          value: %s
+`
+
+func writeRouteDocument(t *testing.T, path, value string) {
+	t.Helper()
+
+	document := []byte(fmt.Sprintf(greetRouteYAML, value))
+	if err := os.WriteFile(path, document, 0o644); err != nil {
+		t.Fatalf("failed to write route document: %s", err)
+	}
+}
+
+func waitBody(rt *camel.Runtime, uri string, want any) any {
+	var body any
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		result, err := rt.SendBody(context.TODO(), uri, nil)
+		if err == nil && result.Body == want {
+			return result.Body
+		}
+		if err == nil {
+			body = result.Body
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return body
+}
+
+func TestRuntime_WatchRoutes(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	err := testCamelRuntime.Start()
+	if err != nil {
+		t.Fatalf("TestRuntime_WatchRoutes(): failed to start camel runtime: %s", err)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "greet.yaml")
+	writeRouteDocument(t, path, `{constant: "v1"}`)
+
+	watcher, err := testCamelRuntime.WatchRoutes(dir, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("TestRuntime_WatchRoutes(): failed to watch routes: %s", err)
+	}
+	defer watcher.Stop()
+
+	if body := waitBody(testCamelRuntime, "direct:greet", "v1"); body != "v1" {
+		t.Fatalf("TestRuntime_WatchRoutes(): expected body %v, but got %v", "v1", body)
+	}
+
+	// changed document
+	writeRouteDocument(t, path, `{constant: "version-2"}`)
+	if body := waitBody(testCamelRuntime, "direct:greet", "version-2"); body != "version-2" {
+		t.Fatalf("TestRuntime_WatchRoutes(): expected body %v, but got %v", "version-2", body)
+	}
+
+	// invalid expression, route must be kept
+	writeRouteDocument(t, path, `{simple: "body +"}`)
+	time.Sleep(100 * time.Millisecond)
+	if body := waitBody(testCamelRuntime, "direct:greet", "version-2"); body != "version-2" {
+		t.Fatalf("TestRuntime_WatchRoutes(): expected body %v after failed reload, but got %v", "version-2", body)
+	}
+
+	// deleted document
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("TestRuntime_WatchRoutes(): failed to remove route document: %s", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for testCamelRuntime.Route("greet") != nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if testCamelRuntime.Route("greet") != nil {
+		t.Fatalf("TestRuntime_WatchRoutes(): expected route 'greet' to be removed")
+	}
+}
+
+func TestRuntime_ReloadRoute(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	route, err := camel.NewRoute("greet", "direct:greet").
+		SetBody("", expr.Constant("v1")).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRuntime_ReloadRoute(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	defer testCamelRuntime.Stop()
+
+	err = testCamelRuntime.Start()
+	if err != nil {
+		t.Fatalf("TestRuntime_ReloadRoute(): failed to start camel runtime: %s", err)
+	}
+
+	// consume from another endpoint
+	route, err = camel.NewRoute("greet", "direct:hello").
+		SetBody("", expr.Constant("v2")).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRuntime_ReloadRoute(): failed to build route: %s", err)
+	}
+	err = testCamelRuntime.ReloadRoute(route)
+	if err != nil {
+		t.Fatalf("TestRuntime_ReloadRoute(): failed to reload route: %s", err)
+	}
+
+	result, err := testCamelRuntime.SendBody(context.TODO(), "direct:hello", nil)
+	if err != nil {
+		t.Fatalf("TestRuntime_ReloadRoute(): failed to call route: %s", err)
+	}
+	if result.Body != "v2" {
+		t.Fatalf("TestRuntime_ReloadRoute(): expected body %v, but got %v", "v2", result.Body)
+	}
+
+	_, err = testCamelRuntime.SendBody(context.TODO(), "direct:greet", nil)
+	if !errors.Is(err, direct.ErrNoConsumers) {
+		t.Fatalf("TestRuntime_ReloadRoute(): expected old route to be detached, but got %v", err)
+	}
+}
+
+// suspendResumePolicy suspends and resumes the route once an exchange is done.
+type suspendResumePolicy struct{}
+
+func (p suspendResumePolicy) OnInit(api.RouteController)                              {}
+func (p suspendResumePolicy) OnStart(api.RouteController)                             {}
+func (p suspendResumePolicy) OnStop(api.RouteController)                              {}
+func (p suspendResumePolicy) OnExchangeBegin(api.RouteController, *exchange.Exchange) {}
+func (p suspendResumePolicy) OnExchangeDone(r api.RouteController, _ *exchange.Exchange) {
+	_ = r.Suspend()
+	_ = r.Resume()
+}
+
+func TestRuntime_ReloadRoute_Drain(t *testing.T) {
+	drainTimeout := camel.RouteDrainTimeout
+	camel.RouteDrainTimeout = 5 * time.Second
+	defer func() { camel.RouteDrainTimeout = drainTimeout }()
+
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	route, err := camel.NewRoute("slow", "direct:slow").
+		RoutePolicy(suspendResumePolicy{}).
+		Func("", func(e *exchange.Exchange) {
+			close(entered)
+			<-release
+			e.Message().Body = "v1"
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRuntime_ReloadRoute_Drain(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	defer testCamelRuntime.Stop()
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRuntime_ReloadRoute_Drain(): failed to start camel runtime: %s", err)
+	}
+
+	sent := make(chan any, 1)
+	go func() {
+		result, _ := testCamelRuntime.SendBody(context.TODO(), "direct:slow", nil)
+		sent <- result.Body
+	}()
+	<-entered
+
+	route, err = camel.NewRoute("slow", "direct:slow").
+		RoutePolicy(suspendResumePolicy{}).
+		SetBody("", expr.Constant("v2")).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRuntime_ReloadRoute_Drain(): failed to build route: %s", err)
+	}
+	reloaded := make(chan error, 1)
+	go func() {
+		reloaded <- testCamelRuntime.ReloadRoute(route)
+	}()
+
+	// The runtime is not locked while the route is drained
+	time.Sleep(50 * time.Millisecond)
+	lookup := make(chan struct{})
+	go func() {
+		testCamelRuntime.Route("slow")
+		close(lookup)
+	}()
+	select {
+	case <-lookup:
+	case <-time.After(time.Second):
+		t.Fatalf("TestRuntime_ReloadRoute_Drain(): runtime is locked while the route is drained")
+	}
+
+	close(release)
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatalf("TestRuntime_ReloadRoute_Drain(): failed to reload route: %s", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("TestRuntime_ReloadRoute_Drain(): reload is not done once the route is drained")
+	}
+	if body := <-sent; body != "v1" {
+		t.Fatalf("TestRuntime_ReloadRoute_Drain(): expected in-flight body v1, but got %v", body)
+	}
+
+	result, err := testCamelRuntime.SendBody(context.TODO(), "direct:slow", nil)
+	if err != nil {
+		t.Fatalf("TestRuntime_ReloadRoute_Drain(): failed to call route: %s", err)
+	}
+	if result.Body != "v2" {
+		t.Fatalf("TestRuntime_ReloadRoute_Drain(): expected body v2, but got %v", result.Body)
+	}
+}
@@ -0,0 +1,144 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+	"testing"
+)
+
+func TestRuntime_AddRouteFromTemplate(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	routeTemplate, err := camel.NewRouteTemplate("discount", "direct:${customer}-discount").
+		Param("customer").
+		ParamDefault("threshold", 100).
+		ParamDefault("discount", 0.1).
+		Steps(func(b *camel.RouteBuilder) {
+			b.Choice("").
+				When(expr.Simple("body > ${threshold}"), func(b *camel.RouteBuilder) {
+					b.SetBody("", expr.Simple("body * (1 - ${discount})"))
+				}).
+				EndChoice().
+				LogDebug("", "${customer}: ${body}")
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRuntime_AddRouteFromTemplate(): failed to build route template: %s", err)
+	}
+	testCamelRuntime.MustRegisterRouteTemplate(routeTemplate)
+
+	testCamelRuntime.MustAddRouteFromTemplate("discount", "acme-discount", map[string]any{"customer": "acme"})
+	testCamelRuntime.MustAddRouteFromTemplate("discount", "globex-discount", map[string]any{
+		"customer":  "globex",
+		"threshold": 10,
+		"discount":  0.5,
+	})
+
+	err = testCamelRuntime.AddRouteFromTemplate("discount", "no-customer", nil)
+	if err == nil {
+		t.Fatalf("TestRuntime_AddRouteFromTemplate(): expected error for missing required parameter")
+	}
+
+	err = testCamelRuntime.Start()
+	if err != nil {
+		t.Fatalf("TestRuntime_AddRouteFromTemplate(): failed to start camel runtime: %s", err)
+	}
+
+	tests := []struct {
+		uri        string
+		body       int
+		wantResult any
+	}{
+		{uri: "direct:acme-discount", body: 50, wantResult: 50},
+		{uri: "direct:acme-discount", body: 200, wantResult: 180.0},
+		{uri: "direct:globex-discount", body: 50, wantResult: 25.0},
+	}
+
+	for _, tt := range tests {
+		result, err := testCamelRuntime.SendBody(context.TODO(), tt.uri, tt.body)
+		if err != nil {
+			t.Fatalf("TestRuntime_AddRouteFromTemplate(): failed to call route %s: %s", tt.uri, err)
+		}
+		if result.Body != tt.wantResult {
+			t.Errorf("TestRuntime_AddRouteFromTemplate(): %s: expected result %v, but got %v", tt.uri, tt.wantResult, result.Body)
+		}
+	}
+}
+
+func TestRuntime_AddRouteFromTemplate_Clauses(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	routeTemplate, err := camel.NewRouteTemplate("order", "direct:${customer}-order").
+		Param("customer").
+		ParamDefault("limit", 100).
+		Steps(func(b *camel.RouteBuilder) {
+			b.OnException(errs.Contains("rejected"), func(b *camel.RouteBuilder) {
+				b.SetBody("", expr.Simple("'${customer}: rejected'"))
+			}).Handled(true).EndOnException().
+				Try("", func(b *camel.RouteBuilder) {
+					b.SetError("", errors.New("order failed"))
+				}).
+				Catch(errs.Predicate(expr.Simple("body > ${limit}")), func(b *camel.RouteBuilder) {
+					b.SetError("", errors.New("order rejected"))
+				}).
+				Catch(errs.Any(), func(b *camel.RouteBuilder) {
+					b.SetBody("", expr.Constant("accepted"))
+				}).
+				EndTry().
+				ErrorHandlerScope("", routestep.DeadLetterChannel("direct:${customer}-dlq"), func(b *camel.RouteBuilder) {
+					b.LogDebug("", "${customer}: ${body}")
+				})
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRuntime_AddRouteFromTemplate_Clauses(): failed to build route template: %s", err)
+	}
+
+	route, err := routeTemplate.Instantiate("acme-order", map[string]any{"customer": "acme"})
+	if err != nil {
+		t.Fatalf("TestRuntime_AddRouteFromTemplate_Clauses(): failed to instantiate route: %s", err)
+	}
+	if uri := route.Steps[1].(*routestep.ErrorHandlerScope).ErrorHandler.DeadLetterURI; uri != "direct:acme-dlq" {
+		t.Fatalf("TestRuntime_AddRouteFromTemplate_Clauses(): expected dead letter 'direct:acme-dlq', but got %s", uri)
+	}
+	if uri := routeTemplate.Steps[1].(*routestep.ErrorHandlerScope).ErrorHandler.DeadLetterURI; uri != "direct:${customer}-dlq" {
+		t.Fatalf("TestRuntime_AddRouteFromTemplate_Clauses(): template step is modified: %s", uri)
+	}
+
+	testCamelRuntime.MustRegisterRouteTemplate(routeTemplate)
+	testCamelRuntime.MustAddRouteFromTemplate("order", "acme-order", map[string]any{"customer": "acme"})
+	testCamelRuntime.MustAddRouteFromTemplate("order", "globex-order", map[string]any{"customer": "globex", "limit": 10})
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRuntime_AddRouteFromTemplate_Clauses(): failed to start camel runtime: %s", err)
+	}
+
+	tests := []struct {
+		uri        string
+		wantResult any
+	}{
+		{uri: "direct:acme-order", wantResult: "accepted"},
+		{uri: "direct:globex-order", wantResult: "globex: rejected"},
+	}
+
+	for _, tt := range tests {
+		result, err := testCamelRuntime.SendBody(context.TODO(), tt.uri, 50)
+		if err != nil {
+			t.Fatalf("TestRuntime_AddRouteFromTemplate_Clauses(): failed to call route %s: %s", tt.uri, err)
+		}
+		if result.Body != tt.wantResult {
+			t.Errorf("TestRuntime_AddRouteFromTemplate_Clauses(): %s: expected result %v, but got %v", tt.uri, tt.wantResult, result.Body)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
 	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
 	"github.com/paveldanilin/go-camel/pkg/camel/converter"
 	"github.com/paveldanilin/go-camel/pkg/camel/env"
@@ -293,3 +294,65 @@ func TestRoute_DynamicFrom(t *testing.T) {
 		t.Fatalf("TestRoute_SetHeader(): expected result %v, but got %v", wantResult, result.Body)
 	}
 }
+
+type countingPolicy struct {
+	init, start, stop, begin, done int
+}
+
+func (p *countingPolicy) OnInit(_ api.RouteController)  { p.init++ }
+func (p *countingPolicy) OnStart(_ api.RouteController) { p.start++ }
+func (p *countingPolicy) OnStop(_ api.RouteController)  { p.stop++ }
+func (p *countingPolicy) OnExchangeBegin(_ api.RouteController, _ *exchange.Exchange) {
+	p.begin++
+}
+func (p *countingPolicy) OnExchangeDone(_ api.RouteController, _ *exchange.Exchange) {
+	p.done++
+}
+
+func TestRoute_RoutePolicy(t *testing.T) {
+	globalPolicy := &countingPolicy{}
+	routePolicy := &countingPolicy{}
+
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{
+		Name:          "CamelTestRuntime",
+		RoutePolicies: []api.RoutePolicy{globalPolicy},
+	})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	route, err := camel.NewRoute("hello", "direct:hello").
+		RoutePolicy(routePolicy).
+		SetBody("", expr.Constant("hello")).
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_RoutePolicy(): failed to build 'hello' route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	err = testCamelRuntime.Start()
+	if err != nil {
+		t.Fatalf("TestRoute_RoutePolicy(): failed to start camel runtime: %s", err)
+	}
+
+	_, err = testCamelRuntime.SendBody(context.TODO(), "direct:hello", nil)
+	if err != nil {
+		t.Fatalf("TestRoute_RoutePolicy(): failed to call route: %s", err)
+	}
+
+	// Suspended route rejects exchanges
+	err = testCamelRuntime.Route("hello").Suspend()
+	if err != nil {
+		t.Fatalf("TestRoute_RoutePolicy(): failed to suspend route: %s", err)
+	}
+	_, err = testCamelRuntime.SendBody(context.TODO(), "direct:hello", nil)
+	if !errors.Is(err, camel.ErrRouteSuspended) {
+		t.Fatalf("TestRoute_RoutePolicy(): expected error %v, but got %v", camel.ErrRouteSuspended, err)
+	}
+
+	testCamelRuntime.Stop()
+
+	for _, p := range []*countingPolicy{globalPolicy, routePolicy} {
+		if p.init != 1 || p.start != 1 || p.stop != 1 || p.begin != 1 || p.done != 1 {
+			t.Errorf("TestRoute_RoutePolicy(): unexpected policy calls %+v", *p)
+		}
+	}
+}
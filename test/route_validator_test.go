@@ -0,0 +1,88 @@
+package test
+
+import (
+	"errors"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+	"testing"
+)
+
+func TestRuntime_ValidateRoute(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	route, err := camel.NewRoute("invalid", "direct:invalid").
+		ErrorHandler(routestep.ErrorHandler{Redelivery: routestep.RedeliveryPolicy{MaximumRedeliveries: 3, Jitter: 2}}).
+		OnException(errs.Any(), func(b *camel.RouteBuilder) {
+			b.To("", "kafka:failed")
+		}).Handled(true).Continued(true).EndOnException().
+		Marshal("", "csv").
+		Choice("").
+		When(expr.Simple("body > 1"), func(b *camel.RouteBuilder) {
+			b.SetBody("", expr.Simple("body +"))
+		}).
+		When(expr.Simple("true"), func(b *camel.RouteBuilder) {
+			b.To("", "kafka:orders")
+		}).
+		When(expr.Simple("body > 10"), func(b *camel.RouteBuilder) {
+			b.Func("", "notRegistered")
+		}).
+		EndChoice().
+		Try("", func(b *camel.RouteBuilder) {
+			b.Log("", api.LogLevelInfo, "body=${body}, header=${header.x")
+		}).
+		Catch(errs.Matcher{Target: "*"}, func(b *camel.RouteBuilder) {
+			b.SetBody("", expr.Constant(1))
+		}).
+		Catch(errs.Matcher{MatchMode: errs.MatchModeContains, Target: "timeout"}, func(b *camel.RouteBuilder) {
+			b.SetBody("", expr.Constant(2))
+		}).
+		Catch(errs.AnyOf(errs.Matches("(")), func(b *camel.RouteBuilder) {
+			b.SetBody("", expr.Constant(3))
+		}).
+		EndTry().
+		Build()
+	if err != nil {
+		t.Fatalf("TestRuntime_ValidateRoute(): failed to build route: %s", err)
+	}
+
+	err = testCamelRuntime.RegisterRoute(route)
+	var validationErr *camel.RouteValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("TestRuntime_ValidateRoute(): expected RouteValidationError, but got %v", err)
+	}
+
+	wantPaths := []string{
+		"invalid/errorHandler",
+		"invalid/onException[0]",
+		"invalid/onException[0]/to[0]",
+		"invalid/marshal[0]",
+		"invalid/choice[1]/when[0]/setBody[0]",
+		"invalid/choice[1]/when[2]",
+		"invalid/choice[1]/when[1]/to[0]",
+		"invalid/choice[1]/when[2]/fn[0]",
+		"invalid/try[2]/catch[1]",
+		"invalid/try[2]/catch[2]",
+		"invalid/try[2]/log[0]",
+	}
+	gotPaths := map[string]bool{}
+	for _, problem := range validationErr.Problems {
+		gotPaths[problem.Path] = true
+	}
+	for _, path := range wantPaths {
+		if !gotPaths[path] {
+			t.Errorf("TestRuntime_ValidateRoute(): expected problem at '%s', got: %s", path, validationErr)
+		}
+	}
+	if len(validationErr.Problems) != len(wantPaths) {
+		t.Errorf("TestRuntime_ValidateRoute(): expected %d problems, but got %d: %s", len(wantPaths), len(validationErr.Problems), validationErr)
+	}
+
+	if testCamelRuntime.Route("invalid") != nil {
+		t.Fatalf("TestRuntime_ValidateRoute(): invalid route must not be registered")
+	}
+}
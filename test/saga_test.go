@@ -0,0 +1,97 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/routestep"
+	"sync"
+	"testing"
+)
+
+func TestRoute_Saga(t *testing.T) {
+	tests := []struct {
+		name        string
+		paymentFail bool
+		wantActions []string
+	}{
+		{name: "completed", wantActions: []string{"reserve", "pay", "order completed", "stock completed"}},
+		{name: "compensated", paymentFail: true, wantActions: []string{"reserve", "pay", "release stock", "cancel order"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+			testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+			defer testCamelRuntime.Stop()
+
+			var mu sync.Mutex
+			var actions []string
+			action := func(name string) func(e *exchange.Exchange) {
+				return func(e *exchange.Exchange) {
+					mu.Lock()
+					actions = append(actions, name)
+					mu.Unlock()
+				}
+			}
+
+			routes := []*camel.RouteBuilder{
+				camel.NewRoute("order", "direct:order").
+					Saga("order saga", func(b *camel.RouteBuilder) {
+						b.To("", "direct:reserve").To("", "direct:pay")
+					}).
+					Compensation("direct:cancelOrder").
+					Completion("direct:orderCompleted").
+					EndSaga(),
+				camel.NewRoute("reserve", "direct:reserve").
+					Saga("", func(b *camel.RouteBuilder) {
+						b.Func("", action("reserve"))
+					}).
+					Propagation(routestep.SagaPropagationMandatory).
+					Compensation("direct:releaseStock").
+					Completion("direct:stockCompleted").
+					EndSaga(),
+				camel.NewRoute("pay", "direct:pay").
+					Func("", action("pay")).
+					Func("", func(e *exchange.Exchange) {
+						if tt.paymentFail {
+							e.SetError(errors.New("payment declined"))
+						}
+					}),
+				camel.NewRoute("cancelOrder", "direct:cancelOrder").Func("", action("cancel order")),
+				camel.NewRoute("orderCompleted", "direct:orderCompleted").Func("", action("order completed")),
+				camel.NewRoute("releaseStock", "direct:releaseStock").Func("", action("release stock")),
+				camel.NewRoute("stockCompleted", "direct:stockCompleted").Func("", action("stock completed")),
+			}
+			for _, b := range routes {
+				route, err := b.Build()
+				if err != nil {
+					t.Fatalf("TestRoute_Saga(): failed to build route: %s", err)
+				}
+				testCamelRuntime.MustRegisterRoute(route)
+			}
+
+			if err := testCamelRuntime.Start(); err != nil {
+				t.Fatalf("TestRoute_Saga(): failed to start camel runtime: %s", err)
+			}
+
+			_, err := testCamelRuntime.Send(context.TODO(), "direct:order", nil, nil)
+			if (err != nil) != tt.paymentFail {
+				t.Fatalf("TestRoute_Saga(): unexpected error: %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(actions) != len(tt.wantActions) {
+				t.Fatalf("TestRoute_Saga(): expected actions %v, but got %v", tt.wantActions, actions)
+			}
+			for i := range actions {
+				if actions[i] != tt.wantActions[i] {
+					t.Fatalf("TestRoute_Saga(): expected actions %v, but got %v", tt.wantActions, actions)
+				}
+			}
+		})
+	}
+}
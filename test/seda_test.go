@@ -0,0 +1,206 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/api"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/seda"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"testing"
+	"time"
+)
+
+func TestSeda_ExchangePattern(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+	testCamelRuntime.MustRegisterComponent(seda.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	processed := make(chan any, 10)
+
+	slow, err := camel.NewRoute("slow", "seda:slow").
+		Delay("", 50).
+		SetBody("", expr.Simple("'processed ' + body")).
+		Func("", func(e *exchange.Exchange) {
+			processed <- e.Message().Body
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("TestSeda_ExchangePattern(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(slow)
+
+	fireAndForget, err := camel.NewRoute("fireAndForget", "direct:fireAndForget").
+		SetPattern("", exchange.InOnly).
+		To("", "seda:slow").
+		Build()
+	if err != nil {
+		t.Fatalf("TestSeda_ExchangePattern(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(fireAndForget)
+
+	err = testCamelRuntime.Start()
+	if err != nil {
+		t.Fatalf("TestSeda_ExchangePattern(): failed to start camel runtime: %s", err)
+	}
+
+	pt := testCamelRuntime.NewProducerTemplate()
+
+	// InOut: waits for the reply
+	body, err := pt.RequestBody(context.TODO(), "seda:slow", "a")
+	if err != nil {
+		t.Fatalf("TestSeda_ExchangePattern(): failed to request: %s", err)
+	}
+	if body != "processed a" {
+		t.Fatalf("TestSeda_ExchangePattern(): expected reply %v, but got %v", "processed a", body)
+	}
+	<-processed
+
+	// InOnly: returns immediately
+	start := time.Now()
+	if err := pt.SendBody(context.TODO(), "seda:slow", "b"); err != nil {
+		t.Fatalf("TestSeda_ExchangePattern(): failed to send: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 50*time.Millisecond {
+		t.Fatalf("TestSeda_ExchangePattern(): InOnly send must not wait for processing, took %s", elapsed)
+	}
+	select {
+	case body := <-processed:
+		if body != "processed b" {
+			t.Fatalf("TestSeda_ExchangePattern(): expected processed body %v, but got %v", "processed b", body)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("TestSeda_ExchangePattern(): InOnly message was not processed")
+	}
+
+	// SetPattern step switches the exchange to InOnly, so the reply of 'seda:slow' is not awaited
+	result, err := testCamelRuntime.SendBody(context.TODO(), "direct:fireAndForget", "c")
+	if err != nil {
+		t.Fatalf("TestSeda_ExchangePattern(): failed to call route: %s", err)
+	}
+	if result.Body != "c" {
+		t.Fatalf("TestSeda_ExchangePattern(): expected body %v, but got %v", "c", result.Body)
+	}
+	<-processed
+}
+
+func TestSeda_ProcessAsync(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(seda.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	echo, err := camel.NewRoute("echo", "seda:echo?concurrentConsumers=4").
+		Delay("", 20).
+		SetBody("", expr.Simple("'echo ' + body")).
+		Build()
+	if err != nil {
+		t.Fatalf("TestSeda_ProcessAsync(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(echo)
+
+	err = testCamelRuntime.Start()
+	if err != nil {
+		t.Fatalf("TestSeda_ProcessAsync(): failed to start camel runtime: %s", err)
+	}
+
+	endpoint, err := testCamelRuntime.ResolveEndpoint("seda:echo?concurrentConsumers=4")
+	if err != nil {
+		t.Fatalf("TestSeda_ProcessAsync(): failed to resolve endpoint: %s", err)
+	}
+	producer, err := endpoint.CreateProducer()
+	if err != nil {
+		t.Fatalf("TestSeda_ProcessAsync(): failed to create producer: %s", err)
+	}
+
+	// InOut exchanges are parked until the reply without holding the caller goroutine
+	exchanges := make([]*exchange.Exchange, 4)
+	replies := make(chan struct{}, len(exchanges))
+	start := time.Now()
+	for i := range exchanges {
+		exchanges[i] = testCamelRuntime.NewExchange(context.TODO())
+		exchanges[i].SetPattern(exchange.InOut)
+		exchanges[i].Message().Body = fmt.Sprint(i)
+		api.ToAsync(producer).ProcessAsync(exchanges[i], func() {
+			replies <- struct{}{}
+		})
+	}
+	if elapsed := time.Since(start); elapsed >= 20*time.Millisecond {
+		t.Fatalf("TestSeda_ProcessAsync(): ProcessAsync must not wait for the reply, took %s", elapsed)
+	}
+
+	for range exchanges {
+		select {
+		case <-replies:
+		case <-time.After(time.Second):
+			t.Fatalf("TestSeda_ProcessAsync(): reply was not received")
+		}
+	}
+	for i, e := range exchanges {
+		if e.IsError() {
+			t.Fatalf("TestSeda_ProcessAsync(): unexpected error: %s", e.Error())
+		}
+		if expected := fmt.Sprintf("echo %d", i); e.Message().Body != expected {
+			t.Fatalf("TestSeda_ProcessAsync(): expected body %v, but got %v", expected, e.Message().Body)
+		}
+	}
+}
+
+func TestSeda_SharedQueue(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(seda.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	processed := make(chan any, 10)
+
+	orders, err := camel.NewRoute("orders", "seda:orders?concurrentConsumers=4&size=10").
+		SetBody("", expr.Simple("'processed ' + body")).
+		Func("", func(e *exchange.Exchange) {
+			processed <- e.Message().Body
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("TestSeda_SharedQueue(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(orders)
+
+	err = testCamelRuntime.Start()
+	if err != nil {
+		t.Fatalf("TestSeda_SharedQueue(): failed to start camel runtime: %s", err)
+	}
+
+	pt := testCamelRuntime.NewProducerTemplate()
+
+	// The producer refers to the queue by name only
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	body, err := pt.RequestBody(ctx, "seda:orders", "a")
+	if err != nil {
+		t.Fatalf("TestSeda_SharedQueue(): failed to request: %s", err)
+	}
+	if body != "processed a" {
+		t.Fatalf("TestSeda_SharedQueue(): expected reply %v, but got %v", "processed a", body)
+	}
+	<-processed
+
+	if err := pt.SendBody(context.TODO(), "seda:orders?timeout=5s", "b"); err != nil {
+		t.Fatalf("TestSeda_SharedQueue(): failed to send: %s", err)
+	}
+	select {
+	case body := <-processed:
+		if body != "processed b" {
+			t.Fatalf("TestSeda_SharedQueue(): expected processed body %v, but got %v", "processed b", body)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("TestSeda_SharedQueue(): InOnly message was not processed")
+	}
+
+	if _, err := testCamelRuntime.ResolveEndpoint("seda:orders?size=20"); err == nil {
+		t.Fatalf("TestSeda_SharedQueue(): expected error for the queue size mismatch")
+	}
+}
@@ -0,0 +1,105 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"strings"
+	"testing"
+)
+
+func TestRoute_StepError(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	route, err := camel.NewRoute("order", "direct:order").
+		SetHeader("", "total", expr.Constant(10)).
+		Choice("").
+		When(expr.Simple("header.total > 5"), func(b *camel.RouteBuilder) {
+			b.SetBody("", expr.Constant("big")).
+				SetError("reject", errors.New("rejected"))
+		}).
+		EndChoice().
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_StepError(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRoute_StepError(): failed to start camel runtime: %s", err)
+	}
+
+	_, err = testCamelRuntime.Send(context.TODO(), "direct:order", nil, nil)
+
+	var stepErr *errs.StepError
+	if !errors.As(err, &stepErr) {
+		t.Fatalf("TestRoute_StepError(): expected StepError, but got %v", err)
+	}
+	if stepErr.RouteName != "order" || stepErr.StepName != "reject" || stepErr.StepPath != "order/choice[1]/when[0]/setError[1]" {
+		t.Fatalf("TestRoute_StepError(): unexpected failure context: route=%s step=%s path=%s",
+			stepErr.RouteName, stepErr.StepName, stepErr.StepPath)
+	}
+	if stepErr.ExchangeId == "" || err.Error() != "rejected" {
+		t.Fatalf("TestRoute_StepError(): expected exchange id and error 'rejected', but got %q and %q", stepErr.ExchangeId, err)
+	}
+}
+
+func TestRoute_StepErrorPanic(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	route, err := camel.NewRoute("order", "direct:order").
+		Try("", func(b *camel.RouteBuilder) {
+			b.SetError("", errors.New("first"))
+		}).
+		Catch(errs.Any(), func(b *camel.RouteBuilder) {
+			b.SetHeader("", "first", expr.Simple("exception.step"))
+		}).
+		EndTry().
+		Try("", func(b *camel.RouteBuilder) {
+			b.Func("explode", func(e *exchange.Exchange) {
+				panic("boom")
+			})
+		}).
+		Catch(errs.Equals("boom"), func(b *camel.RouteBuilder) {
+			b.SetHeader("", "step", expr.Simple("exception.step")).
+				SetHeader("", "stack", expr.Simple("exception.stack")).
+				SetHeader("", "history", expr.Simple("len(errorHistory)"))
+		}).
+		EndTry().
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_StepErrorPanic(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRoute_StepErrorPanic(): failed to start camel runtime: %s", err)
+	}
+
+	e, err := testCamelRuntime.Send(context.TODO(), "direct:order", nil, nil)
+	if err != nil {
+		t.Fatalf("TestRoute_StepErrorPanic(): unexpected error: %s", err)
+	}
+	if step, _ := e.Message().Header("step"); step != "explode" {
+		t.Fatalf("TestRoute_StepErrorPanic(): expected failed step %q, but got %v", "explode", step)
+	}
+	if stack, _ := e.Message().Header("stack"); !strings.Contains(stack.(string), "TestRoute_StepErrorPanic") {
+		t.Fatalf("TestRoute_StepErrorPanic(): expected panic stack trace, but got %v", stack)
+	}
+	if history, _ := e.Message().Header("history"); history != 2 {
+		t.Fatalf("TestRoute_StepErrorPanic(): expected %d errors in history, but got %v", 2, history)
+	}
+	if len(e.ErrorHistory()) != 2 || e.ErrorHistory()[0].Error() != "first" {
+		t.Fatalf("TestRoute_StepErrorPanic(): unexpected error history: %v", e.ErrorHistory())
+	}
+}
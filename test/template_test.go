@@ -0,0 +1,101 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/timer"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"testing"
+	"time"
+)
+
+func newTemplateTestRuntime(t *testing.T) *camel.Runtime {
+	t.Helper()
+
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+	testCamelRuntime.MustRegisterComponent(timer.NewComponent())
+
+	route, err := camel.NewRoute("double", "direct:double").
+		SetBody("", expr.Simple("string(body * 2)")).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("failed to start camel runtime: %s", err)
+	}
+	return testCamelRuntime
+}
+
+func TestProducerTemplate(t *testing.T) {
+	testCamelRuntime := newTemplateTestRuntime(t)
+	defer testCamelRuntime.Stop()
+
+	pt := testCamelRuntime.NewProducerTemplate()
+
+	body, err := pt.RequestBody(context.TODO(), "direct:double", 21)
+	if err != nil {
+		t.Fatalf("TestProducerTemplate(): failed to request: %s", err)
+	}
+	if body != "42" {
+		t.Fatalf("TestProducerTemplate(): expected reply %v, but got %v", "42", body)
+	}
+
+	typed, err := camel.RequestBodyAs[int](context.TODO(), pt, "direct:double", 5)
+	if err != nil {
+		t.Fatalf("TestProducerTemplate(): failed to request typed body: %s", err)
+	}
+	if typed != 10 {
+		t.Fatalf("TestProducerTemplate(): expected typed reply %v, but got %v", 10, typed)
+	}
+
+	if err := pt.SendDefault(context.TODO(), 1, nil); !errors.Is(err, camel.ErrNoDefaultEndpoint) {
+		t.Fatalf("TestProducerTemplate(): expected ErrNoDefaultEndpoint, but got %v", err)
+	}
+	pt.SetDefaultEndpoint("direct:double")
+	reply, err := pt.RequestDefault(context.TODO(), 2, nil)
+	if err != nil {
+		t.Fatalf("TestProducerTemplate(): failed to request default endpoint: %s", err)
+	}
+	if reply.Body != "4" {
+		t.Fatalf("TestProducerTemplate(): expected reply %v, but got %v", "4", reply.Body)
+	}
+
+	future := pt.AsyncRequest(context.TODO(), "direct:double", 3, nil)
+	reply, err = future.Get(context.TODO())
+	if err != nil {
+		t.Fatalf("TestProducerTemplate(): failed to request async: %s", err)
+	}
+	if reply.Body != "6" {
+		t.Fatalf("TestProducerTemplate(): expected async reply %v, but got %v", "6", reply.Body)
+	}
+
+	if err := pt.SendBody(context.TODO(), "direct:unknown", 1); !errors.Is(err, direct.ErrNoConsumers) {
+		t.Fatalf("TestProducerTemplate(): expected ErrNoConsumers, but got %v", err)
+	}
+}
+
+func TestConsumerTemplate(t *testing.T) {
+	testCamelRuntime := newTemplateTestRuntime(t)
+	defer testCamelRuntime.Stop()
+
+	ct := testCamelRuntime.NewConsumerTemplate()
+	defer ct.Stop()
+
+	e, err := ct.Receive("timer:tick?interval=10ms", time.Second)
+	if err != nil {
+		t.Fatalf("TestConsumerTemplate(): failed to receive: %s", err)
+	}
+	if name, _ := e.Message().Header(timer.HeaderTimerName); name != "tick" {
+		t.Fatalf("TestConsumerTemplate(): expected timer name %v, but got %v", "tick", name)
+	}
+
+	if _, err := ct.Receive("direct:nothing", 10*time.Millisecond); !errors.Is(err, camel.ErrReceiveTimeout) {
+		t.Fatalf("TestConsumerTemplate(): expected ErrReceiveTimeout, but got %v", err)
+	}
+}
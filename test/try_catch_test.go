@@ -0,0 +1,147 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/paveldanilin/go-camel/pkg/camel"
+	"github.com/paveldanilin/go-camel/pkg/camel/component/direct"
+	"github.com/paveldanilin/go-camel/pkg/camel/errs"
+	"github.com/paveldanilin/go-camel/pkg/camel/exchange"
+	"github.com/paveldanilin/go-camel/pkg/camel/expr"
+	"testing"
+)
+
+var errOutOfStock = errors.New("out of stock")
+
+type paymentError struct {
+	Code int
+}
+
+func (e *paymentError) Error() string {
+	return fmt.Sprintf("payment declined: %d", e.Code)
+}
+
+func TestRoute_TryCatchMatchers(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		matcher  errs.Matcher
+		wantBody any
+	}{
+		{
+			name:     "is sentinel",
+			err:      fmt.Errorf("order 1: %w", errOutOfStock),
+			matcher:  errs.Is(errOutOfStock),
+			wantBody: "caught",
+		},
+		{
+			name:     "as type",
+			err:      fmt.Errorf("order 1: %w", &paymentError{Code: 51}),
+			matcher:  errs.As[*paymentError](),
+			wantBody: "caught",
+		},
+		{
+			name:     "predicate",
+			err:      &paymentError{Code: 51},
+			matcher:  errs.Predicate(expr.Simple("header.retryable == false")),
+			wantBody: "caught",
+		},
+		{
+			name:     "any of",
+			err:      errors.New("connection refused"),
+			matcher:  errs.AnyOf(errs.Is(errOutOfStock), errs.Contains("refused")),
+			wantBody: "caught",
+		},
+		{
+			name:     "all of",
+			err:      fmt.Errorf("order 1: %w", &paymentError{Code: 51}),
+			matcher:  errs.AllOf(errs.As[*paymentError](), errs.Not(errs.Is(errOutOfStock))),
+			wantBody: "caught",
+		},
+		{
+			name:     "not matched",
+			err:      errOutOfStock,
+			matcher:  errs.Not(errs.Is(errOutOfStock)),
+			wantBody: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+			testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+			defer testCamelRuntime.Stop()
+
+			route, err := camel.NewRoute("order", "direct:order").
+				SetHeader("", "retryable", expr.Constant(false)).
+				Try("", func(b *camel.RouteBuilder) {
+					b.SetError("", tt.err)
+				}).
+				Catch(tt.matcher, func(b *camel.RouteBuilder) {
+					b.SetBody("", expr.Constant("caught"))
+				}).
+				EndTry().
+				Build()
+			if err != nil {
+				t.Fatalf("TestRoute_TryCatchMatchers(): failed to build route: %s", err)
+			}
+			testCamelRuntime.MustRegisterRoute(route)
+
+			if err := testCamelRuntime.Start(); err != nil {
+				t.Fatalf("TestRoute_TryCatchMatchers(): failed to start camel runtime: %s", err)
+			}
+
+			e, err := testCamelRuntime.Send(context.TODO(), "direct:order", nil, nil)
+			if tt.wantBody == nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("TestRoute_TryCatchMatchers(): expected error %v, but got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TestRoute_TryCatchMatchers(): unexpected error: %s", err)
+			}
+			if e.Message().Body != tt.wantBody {
+				t.Fatalf("TestRoute_TryCatchMatchers(): expected body %v, but got %v", tt.wantBody, e.Message().Body)
+			}
+		})
+	}
+}
+
+func TestRoute_TryCatchExceptionCaught(t *testing.T) {
+	var testCamelRuntime = camel.NewRuntime(camel.RuntimeConfig{Name: "CamelTestRuntime"})
+	testCamelRuntime.MustRegisterComponent(direct.NewComponent())
+
+	defer testCamelRuntime.Stop()
+
+	route, err := camel.NewRoute("order", "direct:order").
+		Try("", func(b *camel.RouteBuilder) {
+			b.SetError("", fmt.Errorf("order 1: %w", errOutOfStock))
+		}).
+		Catch(errs.Is(errOutOfStock), func(b *camel.RouteBuilder) {
+			b.SetBody("", expr.Simple("exceptionChain[len(exceptionChain) - 1].Error()"))
+		}).
+		EndTry().
+		Build()
+	if err != nil {
+		t.Fatalf("TestRoute_TryCatchExceptionCaught(): failed to build route: %s", err)
+	}
+	testCamelRuntime.MustRegisterRoute(route)
+
+	if err := testCamelRuntime.Start(); err != nil {
+		t.Fatalf("TestRoute_TryCatchExceptionCaught(): failed to start camel runtime: %s", err)
+	}
+
+	e, err := testCamelRuntime.Send(context.TODO(), "direct:order", nil, nil)
+	if err != nil {
+		t.Fatalf("TestRoute_TryCatchExceptionCaught(): unexpected error: %s", err)
+	}
+	if e.Message().Body != errOutOfStock.Error() {
+		t.Fatalf("TestRoute_TryCatchExceptionCaught(): expected body %q, but got %v", errOutOfStock.Error(), e.Message().Body)
+	}
+	caught, _ := e.Property(exchange.CamelPropertyExceptionCaught)
+	if err, isErr := caught.(error); !isErr || !errors.Is(err, errOutOfStock) {
+		t.Fatalf("TestRoute_TryCatchExceptionCaught(): expected caught exception %v, but got %v", errOutOfStock, caught)
+	}
+}